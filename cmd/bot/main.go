@@ -33,6 +33,9 @@ func main() {
 		log.Println("成功注册 Telegram 命令")
 	}
 
+	// 启动后台任务
+	botManager.Start()
+
 	// 设置更新配置
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
@@ -70,6 +73,7 @@ func main() {
 
 		case <-sigChan:
 			log.Println("接收到中断信号，正在关闭...")
+			botManager.Stop()
 			return
 		}
 	}
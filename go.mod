@@ -5,4 +5,5 @@ go 1.21
 require (
 	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1
 	github.com/joho/godotenv v1.4.0
+	golang.org/x/image v0.18.0
 )
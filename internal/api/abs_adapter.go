@@ -3,6 +3,10 @@ package api
 import (
 	"fmt"
 	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+	"log"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -109,6 +113,9 @@ func (a *AbsAdapter) GetLibraries() ([]models.LibraryInfo, error) {
 			UpdatedAt: absLibrary.UpdatedAt,
 			LastScan:  absLibrary.LastScan,
 		}
+		for _, folder := range absLibrary.Folders {
+			libraries[i].Folders = append(libraries[i].Folders, folder.Path)
+		}
 	}
 
 	return libraries, nil
@@ -119,59 +126,307 @@ func (a *AbsAdapter) GetLibraryItemsCount(libraryID string) (int, error) {
 	return a.client.GetLibraryItemsCount(libraryID)
 }
 
+// absSearchLimit 只有过滤条件时每个媒体库列出的最大项目数量
+const absSearchLimit = 100
+
 // Search 实现 MediaServer 接口
-func (a *AbsAdapter) Search(query string) ([]models.SearchResult, error) {
-	books, err := a.client.SearchBooks(query, "")
+func (a *AbsAdapter) Search(query models.SearchQuery) ([]models.SearchResult, error) {
+	wantsBooks := query.WantsType(models.SearchTypeBook) || query.WantsType(models.SearchTypeAudiobook)
+	wantsPodcasts := query.WantsType(models.SearchTypePodcast)
+	if !wantsBooks && !wantsPodcasts {
+		return nil, nil
+	}
+
+	var books []models.AbsBook
+	var err error
+	if query.HasFilters() {
+		books, err = a.searchFiltered(query, wantsBooks, wantsPodcasts)
+	} else {
+		books, err = a.client.SearchBooks(query.Text, "")
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	// 转换Audiobookshelf搜索结果到通用搜索结果
-	results := make([]models.SearchResult, len(books))
-	for i, book := range books {
-		// 从相对路径中提取标题（通常是文件名）
-		title := book.RelPath
-		if len(title) > 0 {
-			// 提取文件名部分作为标题
-			title = extractFileName(book.RelPath)
-		}
-
-		// 获取媒体库名称，如果无法获取则使用ID
-		libraryName := "Unknown Library"
-		if book.LibraryID != "" {
-			// 尝试获取媒体库名称
-			libraryName = a.getLibraryNameByID(book.LibraryID)
-			if libraryName == "" {
-				libraryName = book.LibraryID // 如果无法获取名称，使用ID
+	var results []models.SearchResult
+	for _, book := range books {
+		result := a.bookToSearchResult(&book)
+		if query.Matches(result) {
+			results = append(results, *result)
+		}
+	}
+
+	return results, nil
+}
+
+// searchFiltered 按媒体库、类型和分类条件搜索，Audiobookshelf 搜索接口不支持过滤，只能逐个媒体库处理
+func (a *AbsAdapter) searchFiltered(query models.SearchQuery, wantsBooks, wantsPodcasts bool) ([]models.AbsBook, error) {
+	libraries, err := a.client.GetLibrariesInfo()
+	if err != nil {
+		return nil, fmt.Errorf("获取媒体库列表失败: %w", err)
+	}
+
+	// 只有一个没有别名的分类时可以使用 Audiobookshelf 的过滤表达式，其他情况在结果中过滤
+	var filter string
+	if len(query.Genres) == 1 && len(models.GenreNames(query.Genres[0])) == 1 {
+		filter = absFilter("genres", query.Genres[0])
+	}
+
+	var books []models.AbsBook
+	for _, lib := range libraries {
+		if (lib.MediaType == "podcast" && !wantsPodcasts) || (lib.MediaType != "podcast" && !wantsBooks) {
+			continue
+		}
+		if !query.MatchesLibrary(lib.Name) {
+			continue
+		}
+
+		if query.Text != "" {
+			libBooks, err := a.client.SearchBooks(query.Text, lib.ID)
+			if err != nil {
+				return nil, err
 			}
+			books = append(books, libBooks...)
+			continue
 		}
 
-		results[i] = models.SearchResult{
-			ID:      fmt.Sprintf("%s_%s", book.LibraryID, book.RelPath),
-			Title:   title,
-			Size:    book.Size,
-			AddedAt: book.AddedAt,
-			// 尝试获取媒体库名称，如果获取失败则使用ID
-			LibraryID:      book.LibraryID,
-			Library:        libraryName,
-			Type:           "book", // Audiobookshelf主要是书籍
-			RelPath:        book.RelPath,
-			Overview:       "",         // Audiobookshelf搜索结果中没有概述信息
-			Genres:         []string{}, // Audiobookshelf搜索结果中没有分类信息
-			Year:           0,          // Audiobookshelf搜索结果中没有年份信息
-			ProductionYear: 0,          // Audiobookshelf搜索结果中没有制作年份信息
-			PremiereDate:   "",         // Audiobookshelf搜索结果中没有首映日期
-			RunTime:        0,          // Audiobookshelf搜索结果中没有运行时间
-			MediaType:      "audio",    // Audiobookshelf主要是音频媒体
+		// 没有关键词时列出媒体库中的项目
+		items, _, err := a.client.GetLibraryItems(lib.ID, absSearchLimit, 0, "media.metadata.title", false, filter)
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			book := items[i].Book()
+			book.LibraryID = lib.ID
+			books = append(books, book)
 		}
 	}
 
-	return results, nil
+	return books, nil
+}
+
+// GetPlaybackStats 实现 MediaServer 接口
+func (a *AbsAdapter) GetPlaybackStats() (*models.PlaybackStats, error) {
+	listeningStats, err := a.client.GetListeningStats()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.PlaybackStats{
+		Source:      models.StatsSourceListeningStats,
+		TotalTime:   listeningStats.TotalTime,
+		ItemsPlayed: len(listeningStats.Items),
+		Days:        listeningStats.Days,
+	}
+
+	// 按收听时长统计项目和分类
+	genres := make(map[string]*models.GenreStat)
+	for id, item := range listeningStats.Items {
+		stats.TopItems = append(stats.TopItems, models.StatsItem{
+			ID:       id,
+			Title:    item.MediaMetadata.Title,
+			Author:   item.MediaMetadata.AuthorName(),
+			Type:     "book",
+			PlayTime: item.TimeListening,
+		})
+		addGenreStat(genres, item.MediaMetadata.Genres, item.TimeListening)
+	}
+	stats.TopItems = topStatsItems(stats.TopItems)
+	stats.TopGenres = topGenreStats(genres)
+
+	for _, session := range listeningStats.RecentSessions {
+		stats.RecentSessions = append(stats.RecentSessions, models.StatsSession{
+			ItemID:        session.LibraryItemID,
+			Title:         session.DisplayTitle,
+			TimeListening: session.TimeListening,
+			UpdatedAt:     session.UpdatedAt,
+		})
+	}
+
+	if err := a.fillWeekdayHours(stats); err != nil {
+		// 收听会话获取失败不影响其他统计
+		log.Printf("获取 Audiobookshelf 收听会话失败: %v", err)
+	}
+
+	inProgress, err := a.GetResumeItems()
+	if err != nil {
+		// 正在收听的项目获取失败不影响其他统计
+		log.Printf("获取 Audiobookshelf 正在收听的项目失败: %v", err)
+	}
+	stats.InProgress = inProgressStatsItems(inProgress)
+
+	return stats, nil
+}
+
+// fillWeekdayHours 根据最近的收听会话按星期和小时统计收听时长
+func (a *AbsAdapter) fillWeekdayHours(stats *models.PlaybackStats) error {
+	const itemsPerPage = 100
+	const maxPages = 5
+
+	for page := 0; page < maxPages; page++ {
+		sessions, numPages, err := a.client.GetListeningSessions(page, itemsPerPage)
+		if err != nil {
+			return err
+		}
+		for _, session := range sessions {
+			if session.StartedAt == 0 {
+				continue
+			}
+			startedAt := time.UnixMilli(session.StartedAt)
+			stats.WeekdayHours[startedAt.Weekday()][startedAt.Hour()] += session.TimeListening
+		}
+		if page+1 >= numPages {
+			break
+		}
+	}
+
+	return nil
+}
+
+// GetResumeItems 实现 MediaServer 接口
+func (a *AbsAdapter) GetResumeItems() ([]models.ResumeItem, error) {
+	user, err := a.client.GetCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+	items, err := a.client.GetItemsInProgress()
+	if err != nil {
+		return nil, err
+	}
+
+	// 进度信息在用户数据中，以项目ID关联
+	progressByItem := make(map[string]models.AbsMediaProgress)
+	for _, progress := range user.MediaProgress {
+		if progress.EpisodeID == "" {
+			progressByItem[progress.LibraryItemID] = progress
+		}
+	}
+
+	var result []models.ResumeItem
+	for _, item := range items {
+		progress, ok := progressByItem[item.ID]
+		if !ok || progress.IsFinished || progress.HideFromContinueListening {
+			continue
+		}
+		result = append(result, models.ResumeItem{
+			ID:         item.ID,
+			Title:      item.Media.Metadata.Title,
+			Author:     item.Media.Metadata.AuthorName(),
+			Type:       item.MediaType,
+			Position:   progress.CurrentTime,
+			Progress:   progress.Progress,
+			Duration:   progress.Duration,
+			LastPlayed: progress.LastUpdate,
+		})
+	}
+
+	return result, nil
+}
+
+// GetItem 实现 MediaServer 接口
+func (a *AbsAdapter) GetItem(itemID string) (*models.SearchResult, error) {
+	item, err := a.client.GetLibraryItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.toSearchResult(item), nil
 }
 
-// GetListeningStats 实现 MediaServer 接口
-func (a *AbsAdapter) GetListeningStats() (map[string]interface{}, error) {
-	return a.client.GetListeningStats()
+// ListItems 实现 MediaServer 接口
+func (a *AbsAdapter) ListItems(libraryID string, query models.ItemQuery) (*models.ItemPage, error) {
+	var sort string
+	switch query.SortBy {
+	case models.ItemSortName:
+		sort = "media.metadata.title"
+	case models.ItemSortYear:
+		sort = "media.metadata.publishedYear"
+	default:
+		sort = "addedAt"
+	}
+
+	// Audiobookshelf 的过滤表达式为 分组.base64(值)
+	var filter string
+	switch query.Filter {
+	case models.ItemFilterGenre:
+		filter = absFilter("genres", query.Genre)
+	case models.ItemFilterUnplayed:
+		filter = absFilter("progress", "not-started")
+	case models.ItemFilterInProgress:
+		filter = absFilter("progress", "in-progress")
+	}
+
+	items, total, err := a.client.GetLibraryItems(libraryID, query.PageSize, query.Page, sort, query.Descending, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.ItemPage{
+		Items: make([]models.SearchResult, len(items)),
+		Total: total,
+	}
+	for i := range items {
+		page.Items[i] = *a.toSearchResult(&items[i])
+	}
+
+	return page, nil
+}
+
+// toSearchResult 将媒体库项目转换为通用搜索结果
+func (a *AbsAdapter) toSearchResult(item *models.AbsLibraryItem) *models.SearchResult {
+	book := item.Book()
+	return a.bookToSearchResult(&book)
+}
+
+// bookToSearchResult 根据书籍或播客的元数据生成通用搜索结果
+func (a *AbsAdapter) bookToSearchResult(book *models.AbsBook) *models.SearchResult {
+	metadata := &book.Metadata
+	year, _ := strconv.Atoi(metadata.PublishedYear)
+
+	// 没有元数据标题时使用文件名
+	title := metadata.Title
+	if title == "" {
+		title = extractFileName(book.RelPath)
+	}
+
+	// 获取媒体库名称，如果无法获取则使用ID
+	libraryName := a.getLibraryNameByID(book.LibraryID)
+	if libraryName == "" {
+		libraryName = book.LibraryID
+	}
+
+	id := book.ID
+	if id == "" {
+		id = fmt.Sprintf("%s_%s", book.LibraryID, book.RelPath)
+	}
+	mediaType := book.MediaType
+	if mediaType == "" {
+		mediaType = "book" // Audiobookshelf主要是书籍
+	}
+
+	return &models.SearchResult{
+		ID:             id,
+		Title:          title,
+		Subtitle:       metadata.Subtitle,
+		Author:         metadata.AuthorName(),
+		Narrators:      metadata.NarratorList(),
+		Series:         metadata.SeriesList(),
+		Size:           book.Size,
+		AddedAt:        book.AddedAt,
+		LibraryID:      book.LibraryID,
+		Library:        libraryName,
+		Type:           mediaType,
+		Path:           book.Path,
+		RelPath:        book.RelPath,
+		Overview:       util.StripHTML(metadata.Description),
+		Genres:         metadata.Genres,
+		Year:           year,
+		ProductionYear: year,
+		MediaType:      "audio", // Audiobookshelf主要是音频媒体
+		Duration:       book.Duration,
+		ProviderIDs:    metadata.ProviderIDs(),
+	}
 }
 
 // getLibraryNameByID 根据ID获取媒体库名称
@@ -237,3 +492,120 @@ func extractFileName(path string) string {
 	}
 	return path
 }
+
+// GetSessions 实现 MediaServer 接口
+func (a *AbsAdapter) GetSessions() ([]models.PlaybackSession, error) {
+	absSessions, err := a.client.GetOpenSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换Audiobookshelf播放会话到通用播放会话
+	sessions := make([]models.PlaybackSession, len(absSessions))
+	for i, s := range absSessions {
+		userName := s.UserID
+		if s.User != nil && s.User.Username != "" {
+			userName = s.User.Username
+		}
+
+		deviceName := strings.TrimSpace(fmt.Sprintf("%s %s", s.DeviceInfo.Manufacturer, s.DeviceInfo.Model))
+		if deviceName == "" {
+			deviceName = s.DeviceInfo.OSName
+		}
+		if deviceName == "" {
+			deviceName = s.DeviceInfo.BrowserName
+		}
+
+		client := s.DeviceInfo.ClientName
+		if client == "" {
+			client = s.MediaPlayer
+		}
+
+		title := s.DisplayTitle
+		if s.DisplayAuthor != "" {
+			title = fmt.Sprintf("%s - %s", s.DisplayTitle, s.DisplayAuthor)
+		}
+
+		sessions[i] = models.PlaybackSession{
+			ID:         s.ID,
+			UserID:     s.UserID,
+			UserName:   userName,
+			DeviceName: deviceName,
+			Client:     client,
+			ItemID:     s.LibraryItemID,
+			ItemTitle:  title,
+			ItemType:   s.MediaType,
+			Position:   int64(s.CurrentTime * 1000),
+			Duration:   int64(s.Duration * 1000),
+			PlayMethod: absPlayMethod(s.PlayMethod),
+		}
+		if sessions[i].PlayMethod == models.PlayMethodTranscode {
+			sessions[i].Transcoding = &models.TranscodingInfo{}
+		}
+	}
+
+	return sessions, nil
+}
+
+// StopSession 实现 SessionController 接口
+func (a *AbsAdapter) StopSession(sessionID string) error {
+	return a.client.CloseSession(sessionID)
+}
+
+// ScanLibrary 实现 LibraryScanner 接口
+func (a *AbsAdapter) ScanLibrary(libraryID string) error {
+	return a.client.ScanLibrary(libraryID, false)
+}
+
+// RefreshLibraryMetadata 实现 LibraryScanner 接口，强制扫描会重新读取所有项目的元数据
+func (a *AbsAdapter) RefreshLibraryMetadata(libraryID string) error {
+	return a.client.ScanLibrary(libraryID, true)
+}
+
+// GetScanStatus 实现 LibraryScanner 接口
+func (a *AbsAdapter) GetScanStatus(libraryID string) (*models.ScanStatus, error) {
+	tasks, err := a.client.GetTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	// 取该媒体库最近开始的扫描任务
+	var latest *models.AbsTask
+	for i := range tasks {
+		task := &tasks[i]
+		if task.Action != models.AbsTaskActionLibraryScan || task.Data.LibraryID != libraryID {
+			continue
+		}
+		if latest == nil || task.StartedAt > latest.StartedAt {
+			latest = task
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+
+	message := latest.Error
+	if message == "" {
+		message = latest.Description
+	}
+	return &models.ScanStatus{
+		Running:    !latest.IsFinished,
+		Progress:   -1, // Audiobookshelf 不提供扫描进度
+		StartedAt:  latest.StartedAt,
+		FinishedAt: latest.FinishedAt,
+		Failed:     latest.IsFailed,
+		Message:    message,
+	}, nil
+}
+
+// absPlayMethod 将Audiobookshelf的播放方式转换为通用播放方式
+func absPlayMethod(playMethod int) string {
+	switch playMethod {
+	case models.AbsPlayMethodDirectStream:
+		return models.PlayMethodDirectStream
+	case models.AbsPlayMethodTranscode:
+		return models.PlayMethodTranscode
+	default:
+		return models.PlayMethodDirectPlay
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Heathcliff-third-space/MediaManager/internal/config"
 	"github.com/Heathcliff-third-space/MediaManager/internal/models"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"sync"
@@ -172,45 +173,109 @@ func (c *AbsClient) GetLibrariesInfo() ([]models.AbsLibraryInfo, error) {
 	return response.Libraries, nil
 }
 
-// SearchBooks 搜索图书，支持并行处理
-func (c *AbsClient) SearchBooks(term string, libraryID string) ([]models.AbsBook, error) {
+// absSearchResponse 媒体库搜索响应，书籍和播客分别在 book 和 podcast 中
+type absSearchResponse struct {
+	Results []struct {
+		LibraryItem absSearchItem `json:"libraryItem"`
+	} `json:"book"`
+	Podcasts []struct {
+		LibraryItem absSearchItem `json:"libraryItem"`
+	} `json:"podcast"`
+	Authors []struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		NumBooks int    `json:"numBooks"`
+	} `json:"authors"`
+	Series []struct {
+		Series struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"series"`
+		Books []absSearchItem `json:"books"`
+	} `json:"series"`
+	Narrators []struct {
+		Name     string `json:"name"`
+		NumBooks int    `json:"numBooks"`
+	} `json:"narrators"`
+	Tags []struct {
+		Name     string `json:"name"`
+		NumItems int    `json:"numItems"`
+	} `json:"tags"`
+}
+
+// items 返回所有书籍和播客结果
+func (r *absSearchResponse) items() []absSearchItem {
+	items := make([]absSearchItem, 0, len(r.Results)+len(r.Podcasts))
+	for _, result := range r.Results {
+		items = append(items, result.LibraryItem)
+	}
+	for _, result := range r.Podcasts {
+		items = append(items, result.LibraryItem)
+	}
+	return items
+}
+
+// absSearchItem 搜索结果中的媒体库项目
+type absSearchItem struct {
+	ID        string `json:"id"`
+	Path      string `json:"path"`
+	RelPath   string `json:"relPath"`
+	Size      int64  `json:"size"`
+	AddedAt   int64  `json:"addedAt"`
+	MediaType string `json:"mediaType"`
+	Media     struct {
+		Metadata models.AbsMediaMetadata `json:"metadata"`
+		Duration float64                 `json:"duration"` // 秒
+	} `json:"media"`
+}
+
+// toBook 转换为 AbsBook
+func (item absSearchItem) toBook(libraryID string) models.AbsBook {
+	return models.AbsBook{
+		ID:        item.ID,
+		LibraryID: libraryID,
+		Path:      item.Path,
+		RelPath:   item.RelPath,
+		Size:      item.Size,
+		AddedAt:   item.AddedAt,
+		MediaType: item.MediaType,
+		Duration:  item.Media.Duration,
+		Metadata:  item.Media.Metadata,
+	}
+}
+
+// searchLibrary 在单个媒体库中搜索，结果包含项目、作者、系列、演播者和标签
+func (c *AbsClient) searchLibrary(libraryID string, term string) (*absSearchResponse, error) {
 	params := url.Values{}
 	params.Add("q", term)
 
-	// 如果指定了特定的媒体库ID，则只搜索该库
-	if libraryID != "" {
-		endpoint := fmt.Sprintf("/api/libraries/%s/search?%s", libraryID, params.Encode())
+	data, err := c.doRequest("GET", fmt.Sprintf("/api/libraries/%s/search?%s", libraryID, params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
 
-		data, err := c.doRequest("GET", endpoint, nil)
-		if err != nil {
-			return nil, err
-		}
+	var response absSearchResponse
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling search results: %w", err)
+	}
 
-		var response struct {
-			Results []struct {
-				LibraryItem struct {
-					Path    string `json:"path"`
-					RelPath string `json:"relPath"`
-					Size    int64  `json:"size"`
-					AddedAt int64  `json:"addedAt"`
-				}
-			} `json:"book"`
-		}
+	return &response, nil
+}
 
-		err = json.Unmarshal(data, &response)
+// SearchBooks 搜索图书，支持并行处理
+func (c *AbsClient) SearchBooks(term string, libraryID string) ([]models.AbsBook, error) {
+	// 如果指定了特定的媒体库ID，则只搜索该库
+	if libraryID != "" {
+		response, err := c.searchLibrary(libraryID, term)
 		if err != nil {
-			return nil, fmt.Errorf("error unmarshaling search results: %w", err)
+			return nil, err
 		}
 
 		// 提取libraryItem中的字段
 		var books []models.AbsBook
-		for _, result := range response.Results {
-			books = append(books, models.AbsBook{
-				LibraryID: libraryID,
-				RelPath:   result.LibraryItem.RelPath,
-				Size:      result.LibraryItem.Size,
-				AddedAt:   result.LibraryItem.AddedAt,
-			})
+		for _, item := range response.items() {
+			books = append(books, item.toBook(libraryID))
 		}
 
 		return books, nil
@@ -241,26 +306,7 @@ func (c *AbsClient) SearchBooks(term string, libraryID string) ([]models.AbsBook
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			endpoint := fmt.Sprintf("/api/libraries/%s/search?%s", lib.ID, params.Encode())
-
-			data, err := c.doRequest("GET", endpoint, nil)
-			if err != nil {
-				// 继续搜索下一个库而不是完全失败
-				return
-			}
-
-			var response struct {
-				Results []struct {
-					LibraryItem struct {
-						Path    string `json:"path"`
-						RelPath string `json:"relPath"`
-						Size    int64  `json:"size"`
-						AddedAt int64  `json:"addedAt"`
-					}
-				} `json:"book"`
-			}
-
-			err = json.Unmarshal(data, &response)
+			response, err := c.searchLibrary(lib.ID, term)
 			if err != nil {
 				// 继续搜索下一个库而不是完全失败
 				return
@@ -269,15 +315,10 @@ func (c *AbsClient) SearchBooks(term string, libraryID string) ([]models.AbsBook
 			// 添加去重逻辑
 			mu.Lock()
 			defer mu.Unlock()
-			for _, result := range response.Results {
-				if !bookRelPaths[result.LibraryItem.RelPath] {
-					allBooks = append(allBooks, models.AbsBook{
-						LibraryID: lib.ID,
-						RelPath:   result.LibraryItem.RelPath,
-						Size:      result.LibraryItem.Size,
-						AddedAt:   result.LibraryItem.AddedAt,
-					})
-					bookRelPaths[result.LibraryItem.RelPath] = true
+			for _, item := range response.items() {
+				if !bookRelPaths[item.RelPath] {
+					allBooks = append(allBooks, item.toBook(lib.ID))
+					bookRelPaths[item.RelPath] = true
 				}
 			}
 		}(lib)
@@ -349,17 +390,453 @@ func (c *AbsClient) GetCurrentUser() (*models.AbsUserInfo, error) {
 }
 
 // GetListeningStats 获取当前用户的收听统计信息
-func (c *AbsClient) GetListeningStats() (map[string]interface{}, error) {
+func (c *AbsClient) GetListeningStats() (*models.AbsListeningStats, error) {
 	data, err := c.doRequest("GET", "/api/me/listening-stats", nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var stats map[string]interface{}
+	var stats models.AbsListeningStats
 	err = json.Unmarshal(data, &stats)
 	if err != nil {
 		return nil, fmt.Errorf("error unmarshaling listening stats: %w", err)
 	}
 
-	return stats, nil
+	return &stats, nil
+}
+
+// GetOpenSessions 获取当前打开的播放会话
+func (c *AbsClient) GetOpenSessions() ([]models.AbsPlaybackSession, error) {
+	data, err := c.doRequest("GET", "/api/sessions/open", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Sessions []models.AbsPlaybackSession `json:"sessions"`
+	}
+
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling open sessions: %w", err)
+	}
+
+	return response.Sessions, nil
+}
+
+// CloseSession 关闭指定的播放会话
+func (c *AbsClient) CloseSession(sessionID string) error {
+	_, err := c.doRequest("POST", fmt.Sprintf("/api/session/%s/close", sessionID), nil)
+	return err
+}
+
+// GetItemsInProgress 获取当前用户正在收听的项目
+func (c *AbsClient) GetItemsInProgress() ([]models.AbsLibraryItem, error) {
+	data, err := c.doRequest("GET", "/api/me/items-in-progress", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		LibraryItems []models.AbsLibraryItem `json:"libraryItems"`
+	}
+
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling items in progress: %w", err)
+	}
+
+	return response.LibraryItems, nil
+}
+
+// GetLibraryItem 获取单个媒体库项目的详细信息
+func (c *AbsClient) GetLibraryItem(itemID string) (*models.AbsLibraryItem, error) {
+	data, err := c.doRequest("GET", fmt.Sprintf("/api/items/%s?expanded=1", itemID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var item models.AbsLibraryItem
+	err = json.Unmarshal(data, &item)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling library item: %w", err)
+	}
+
+	return &item, nil
+}
+
+// ScanLibrary 触发媒体库扫描，force 为 true 时重新读取所有项目的元数据
+func (c *AbsClient) ScanLibrary(libraryID string, force bool) error {
+	path := fmt.Sprintf("/api/libraries/%s/scan", libraryID)
+	if force {
+		path += "?force=1"
+	}
+	_, err := c.doRequest("POST", path, nil)
+	return err
+}
+
+// GetTasks 获取服务器的后台任务列表
+func (c *AbsClient) GetTasks() ([]models.AbsTask, error) {
+	data, err := c.doRequest("GET", "/api/tasks", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Tasks []models.AbsTask `json:"tasks"`
+	}
+
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling tasks: %w", err)
+	}
+
+	return response.Tasks, nil
+}
+
+// GetLibraryItems 分页获取媒体库项目，sort 为排序字段，filter 为 Audiobookshelf 的过滤表达式
+func (c *AbsClient) GetLibraryItems(libraryID string, limit, page int, sort string, desc bool, filter string) ([]models.AbsLibraryItem, int, error) {
+	params := url.Values{}
+	params.Add("limit", fmt.Sprintf("%d", limit))
+	params.Add("page", fmt.Sprintf("%d", page))
+	params.Add("minified", "1")
+	if sort != "" {
+		params.Add("sort", sort)
+	}
+	if desc {
+		params.Add("desc", "1")
+	}
+	if filter != "" {
+		params.Add("filter", filter)
+	}
+
+	data, err := c.doRequest("GET", fmt.Sprintf("/api/libraries/%s/items?%s", libraryID, params.Encode()), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var response struct {
+		Results []models.AbsLibraryItem `json:"results"`
+		Total   int                     `json:"total"`
+	}
+
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error unmarshaling library items: %w", err)
+	}
+
+	return response.Results, response.Total, nil
+}
+
+// GetListeningSessions 分页获取当前用户的历史收听会话，page 从 0 开始
+func (c *AbsClient) GetListeningSessions(page, itemsPerPage int) ([]models.AbsPlaybackSession, int, error) {
+	params := url.Values{}
+	params.Add("page", fmt.Sprintf("%d", page))
+	params.Add("itemsPerPage", fmt.Sprintf("%d", itemsPerPage))
+
+	data, err := c.doRequest("GET", "/api/me/listening-sessions?"+params.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var response struct {
+		Total    int                         `json:"total"`
+		NumPages int                         `json:"numPages"`
+		Sessions []models.AbsPlaybackSession `json:"sessions"`
+	}
+
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error unmarshaling listening sessions: %w", err)
+	}
+
+	return response.Sessions, response.NumPages, nil
+}
+
+// GetRecentEpisodes 获取播客媒体库中最近加入的单集
+func (c *AbsClient) GetRecentEpisodes(libraryID string, limit int) ([]models.AbsPodcastEpisode, error) {
+	params := url.Values{}
+	params.Add("limit", fmt.Sprintf("%d", limit))
+	params.Add("page", "0")
+
+	data, err := c.doRequest("GET", fmt.Sprintf("/api/libraries/%s/recent-episodes?%s", libraryID, params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Episodes []models.AbsPodcastEpisode `json:"episodes"`
+	}
+
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling recent episodes: %w", err)
+	}
+
+	return response.Episodes, nil
+}
+
+// GetPodcastFeed 获取并解析播客的 RSS 订阅源
+func (c *AbsClient) GetPodcastFeed(feedURL string) (*models.AbsPodcastFeed, error) {
+	body := map[string]string{"rssFeed": feedURL}
+	data, err := c.doRequest("POST", "/api/podcasts/feed", body)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Podcast models.AbsPodcastFeed `json:"podcast"`
+	}
+
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling podcast feed: %w", err)
+	}
+
+	return &response.Podcast, nil
+}
+
+// CreatePodcast 在媒体库中创建播客
+func (c *AbsClient) CreatePodcast(podcast *models.AbsNewPodcast) (*models.AbsLibraryItem, error) {
+	data, err := c.doRequest("POST", "/api/podcasts", podcast)
+	if err != nil {
+		return nil, err
+	}
+
+	var item models.AbsLibraryItem
+	err = json.Unmarshal(data, &item)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling podcast: %w", err)
+	}
+
+	return &item, nil
+}
+
+// CheckNewEpisodes 检查播客的新单集，服务器会自动将找到的单集加入下载队列
+func (c *AbsClient) CheckNewEpisodes(itemID string, limit int) ([]models.AbsPodcastEpisode, error) {
+	data, err := c.doRequest("GET", fmt.Sprintf("/api/podcasts/%s/checknew?limit=%d", itemID, limit), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Episodes []models.AbsPodcastEpisode `json:"episodes"`
+	}
+
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling new episodes: %w", err)
+	}
+
+	return response.Episodes, nil
+}
+
+// DownloadEpisodes 将订阅源中的单集加入播客的下载队列
+func (c *AbsClient) DownloadEpisodes(itemID string, episodes []models.AbsPodcastFeedEpisode) error {
+	_, err := c.doRequest("POST", fmt.Sprintf("/api/podcasts/%s/download-episodes", itemID), episodes)
+	return err
+}
+
+// UpdateMediaProgress 更新当前用户在项目上的播放进度，没有进度记录时会创建
+func (c *AbsClient) UpdateMediaProgress(itemID string, update *models.AbsProgressUpdate) error {
+	_, err := c.doRequest("PATCH", fmt.Sprintf("/api/me/progress/%s", itemID), update)
+	return err
+}
+
+// GetCollections 获取所有合集
+func (c *AbsClient) GetCollections() ([]models.AbsCollection, error) {
+	data, err := c.doRequest("GET", "/api/collections", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Collections []models.AbsCollection `json:"collections"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling collections: %w", err)
+	}
+	return response.Collections, nil
+}
+
+// GetCollection 获取合集及其中的书籍
+func (c *AbsClient) GetCollection(collectionID string) (*models.AbsCollection, error) {
+	data, err := c.doRequest("GET", fmt.Sprintf("/api/collections/%s", collectionID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var collection models.AbsCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("error unmarshaling collection: %w", err)
+	}
+	return &collection, nil
+}
+
+// CreateCollection 在媒体库中创建包含指定书籍的合集
+func (c *AbsClient) CreateCollection(libraryID, name string, itemIDs []string) (*models.AbsCollection, error) {
+	body := map[string]interface{}{
+		"libraryId": libraryID,
+		"name":      name,
+		"books":     itemIDs,
+	}
+	data, err := c.doRequest("POST", "/api/collections", body)
+	if err != nil {
+		return nil, err
+	}
+
+	var collection models.AbsCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("error unmarshaling collection: %w", err)
+	}
+	return &collection, nil
+}
+
+// AddBookToCollection 将书籍加入合集
+func (c *AbsClient) AddBookToCollection(collectionID, itemID string) error {
+	_, err := c.doRequest("POST", fmt.Sprintf("/api/collections/%s/book", collectionID), map[string]string{"id": itemID})
+	return err
+}
+
+// GetPlaylists 获取当前用户的所有播放列表
+func (c *AbsClient) GetPlaylists() ([]models.AbsPlaylist, error) {
+	data, err := c.doRequest("GET", "/api/playlists", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Playlists []models.AbsPlaylist `json:"playlists"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling playlists: %w", err)
+	}
+	return response.Playlists, nil
+}
+
+// GetPlaylist 获取播放列表及其中的项目
+func (c *AbsClient) GetPlaylist(playlistID string) (*models.AbsPlaylist, error) {
+	data, err := c.doRequest("GET", fmt.Sprintf("/api/playlists/%s", playlistID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var playlist models.AbsPlaylist
+	if err := json.Unmarshal(data, &playlist); err != nil {
+		return nil, fmt.Errorf("error unmarshaling playlist: %w", err)
+	}
+	return &playlist, nil
+}
+
+// CreatePlaylist 在媒体库中为当前用户创建包含指定项目的播放列表
+func (c *AbsClient) CreatePlaylist(libraryID, name string, itemIDs []string) (*models.AbsPlaylist, error) {
+	items := make([]models.AbsPlaylistItem, len(itemIDs))
+	for i, id := range itemIDs {
+		items[i] = models.AbsPlaylistItem{LibraryItemID: id}
+	}
+	body := map[string]interface{}{
+		"libraryId": libraryID,
+		"name":      name,
+		"items":     items,
+	}
+	data, err := c.doRequest("POST", "/api/playlists", body)
+	if err != nil {
+		return nil, err
+	}
+
+	var playlist models.AbsPlaylist
+	if err := json.Unmarshal(data, &playlist); err != nil {
+		return nil, fmt.Errorf("error unmarshaling playlist: %w", err)
+	}
+	return &playlist, nil
+}
+
+// AddPlaylistItem 将项目加入播放列表
+func (c *AbsClient) AddPlaylistItem(playlistID, itemID string) error {
+	_, err := c.doRequest("POST", fmt.Sprintf("/api/playlists/%s/item", playlistID), models.AbsPlaylistItem{LibraryItemID: itemID})
+	return err
+}
+
+// openStream 以流的方式读取响应，end 大于 0 时只请求 [start, end) 字节范围，调用方负责关闭
+func (c *AbsClient) openStream(path string, start, end int64) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	if end > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end-1))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+	if end > 0 && resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf("server does not support range requests")
+	}
+
+	return resp.Body, nil
+}
+
+// OpenLibraryFile 打开媒体库项目中的文件，ino 为文件的 inode 标识
+func (c *AbsClient) OpenLibraryFile(itemID, ino string, start, end int64) (io.ReadCloser, error) {
+	return c.openStream(fmt.Sprintf("/api/items/%s/file/%s", itemID, ino), start, end)
+}
+
+// UploadFile 通过 multipart 表单将文件上传到媒体库文件夹，服务器会在文件夹下按作者和标题创建目录
+func (c *AbsClient) UploadFile(libraryID, folderID, title, author, fileName string, file io.Reader) error {
+	// 以流的方式写入表单，避免将整个文件读入内存
+	bodyReader, bodyWriter := io.Pipe()
+	form := multipart.NewWriter(bodyWriter)
+	go func() {
+		fields := [][2]string{{"title", title}, {"author", author}, {"library", libraryID}, {"folder", folderID}}
+		for _, field := range fields {
+			if err := form.WriteField(field[0], field[1]); err != nil {
+				bodyWriter.CloseWithError(err)
+				return
+			}
+		}
+		part, err := form.CreateFormFile("0", fileName)
+		if err == nil {
+			_, err = io.Copy(part, file)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		bodyWriter.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequest("POST", c.baseURL+"/api/upload", bodyReader)
+	if err != nil {
+		bodyReader.Close()
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		bodyReader.Close()
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+	// 服务器提前返回时结束写入协程
+	bodyReader.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
 }
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// GetCollections 实现 CollectionManager 接口，返回所有合集和当前用户的播放列表
+func (a *AbsAdapter) GetCollections() ([]models.Collection, error) {
+	absCollections, err := a.client.GetCollections()
+	if err != nil {
+		return nil, err
+	}
+	playlists, err := a.client.GetPlaylists()
+	if err != nil {
+		return nil, err
+	}
+
+	collections := make([]models.Collection, 0, len(absCollections)+len(playlists))
+	for _, collection := range absCollections {
+		collections = append(collections, models.Collection{
+			ID:        collection.ID,
+			Name:      collection.Name,
+			Kind:      models.CollectionKindCollection,
+			LibraryID: collection.LibraryID,
+			ItemCount: len(collection.Books),
+		})
+	}
+	for _, playlist := range playlists {
+		collections = append(collections, models.Collection{
+			ID:        playlist.ID,
+			Name:      playlist.Name,
+			Kind:      models.CollectionKindPlaylist,
+			LibraryID: playlist.LibraryID,
+			ItemCount: len(playlist.Items),
+		})
+	}
+	return collections, nil
+}
+
+// GetCollectionItems 实现 CollectionManager 接口，播放列表中的播客单集以 “播客 - 单集” 作为标题
+func (a *AbsAdapter) GetCollectionItems(kind, collectionID string) ([]models.SearchResult, error) {
+	if kind != models.CollectionKindPlaylist {
+		collection, err := a.client.GetCollection(collectionID)
+		if err != nil {
+			return nil, err
+		}
+		results := make([]models.SearchResult, len(collection.Books))
+		for i := range collection.Books {
+			results[i] = *a.toSearchResult(&collection.Books[i])
+		}
+		return results, nil
+	}
+
+	playlist, err := a.client.GetPlaylist(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	var results []models.SearchResult
+	for _, item := range playlist.Items {
+		if item.LibraryItem == nil {
+			continue
+		}
+		result := a.toSearchResult(item.LibraryItem)
+		if item.Episode != nil {
+			result.Title = fmt.Sprintf("%s - %s", result.Title, item.Episode.Title)
+			result.Duration = item.Episode.Duration
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+// CreateCollection 实现 CollectionManager 接口，新建的合集或播放列表属于项目所在的媒体库
+func (a *AbsAdapter) CreateCollection(kind, name, itemID string) (*models.Collection, error) {
+	item, err := a.client.GetLibraryItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == models.CollectionKindPlaylist {
+		playlist, err := a.client.CreatePlaylist(item.LibraryID, name, []string{itemID})
+		if err != nil {
+			return nil, err
+		}
+		return &models.Collection{ID: playlist.ID, Name: playlist.Name, Kind: kind, LibraryID: playlist.LibraryID, ItemCount: len(playlist.Items)}, nil
+	}
+
+	collection, err := a.client.CreateCollection(item.LibraryID, name, []string{itemID})
+	if err != nil {
+		return nil, err
+	}
+	return &models.Collection{ID: collection.ID, Name: collection.Name, Kind: kind, LibraryID: collection.LibraryID, ItemCount: len(collection.Books)}, nil
+}
+
+// AddToCollection 实现 CollectionManager 接口
+func (a *AbsAdapter) AddToCollection(kind, collectionID, itemID string) error {
+	if kind == models.CollectionKindPlaylist {
+		return a.client.AddPlaylistItem(collectionID, itemID)
+	}
+	return a.client.AddBookToCollection(collectionID, itemID)
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"io"
+	"sort"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// absMaxEpisodeFiles 下载播客时最多包含的最新单集数量
+const absMaxEpisodeFiles = 10
+
+// GetDownloadFiles 实现 FileDownloader 接口，返回书籍的音频文件和电子书文件，或播客最新单集的音频文件
+func (a *AbsAdapter) GetDownloadFiles(itemID string) ([]models.DownloadFile, error) {
+	item, err := a.client.GetLibraryItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &item.Media.Metadata
+	audioFiles := append([]models.AbsAudioFile(nil), item.Media.AudioFiles...)
+	sort.SliceStable(audioFiles, func(i, j int) bool {
+		return audioFiles[i].Index < audioFiles[j].Index
+	})
+
+	var files []models.DownloadFile
+	// 章节时间相对于整本书，按文件的起始时间换算到每个文件
+	var offset float64
+	for _, audioFile := range audioFiles {
+		file := models.DownloadFile{
+			ID:       audioFile.Ino,
+			Name:     audioFile.Metadata.Filename,
+			Size:     audioFile.Metadata.Size,
+			Duration: audioFile.Duration,
+			Title:    metadata.Title,
+			Artist:   metadata.AuthorName(),
+		}
+		for _, chapter := range item.Media.Chapters {
+			if chapter.Start < offset || chapter.Start >= offset+audioFile.Duration {
+				continue
+			}
+			end := chapter.End - offset
+			if end > audioFile.Duration {
+				end = audioFile.Duration
+			}
+			file.Chapters = append(file.Chapters, models.Chapter{Title: chapter.Title, Start: chapter.Start - offset, End: end})
+		}
+		offset += audioFile.Duration
+		files = append(files, file)
+	}
+
+	if ebook := item.Media.EbookFile; ebook != nil {
+		files = append(files, models.DownloadFile{
+			ID:    ebook.Ino,
+			Name:  ebook.Metadata.Filename,
+			Size:  ebook.Metadata.Size,
+			Title: metadata.Title,
+		})
+	}
+
+	episodes := append([]models.AbsPodcastEpisode(nil), item.Media.Episodes...)
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return episodes[i].PublishedAt > episodes[j].PublishedAt
+	})
+	for i, episode := range episodes {
+		if i >= absMaxEpisodeFiles {
+			break
+		}
+		if episode.AudioFile == nil {
+			continue
+		}
+		files = append(files, models.DownloadFile{
+			ID:       episode.AudioFile.Ino,
+			Name:     episode.AudioFile.Metadata.Filename,
+			Size:     episode.AudioFile.Metadata.Size,
+			Duration: episode.AudioFile.Duration,
+			Title:    episode.Title,
+			Artist:   metadata.Title,
+		})
+	}
+
+	return files, nil
+}
+
+// OpenFile 实现 FileDownloader 接口
+func (a *AbsAdapter) OpenFile(itemID, fileID string, start, end int64) (io.ReadCloser, error) {
+	return a.client.OpenLibraryFile(itemID, fileID, start, end)
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// absFilter 生成 Audiobookshelf 的过滤表达式，格式为 分组.base64(值)
+func absFilter(group, value string) string {
+	return group + "." + base64.StdEncoding.EncodeToString([]byte(value))
+}
+
+// SearchEntities 实现 EntitySearcher 接口，返回各媒体库搜索结果中的作者、系列、演播者和标签
+func (a *AbsAdapter) SearchEntities(text string) ([]models.Entity, error) {
+	libraries, err := a.client.GetLibrariesInfo()
+	if err != nil {
+		return nil, fmt.Errorf("获取媒体库列表失败: %w", err)
+	}
+
+	var entities []models.Entity
+	index := make(map[string]int)
+	// add 合并不同媒体库中的同名实体，累加项目数量
+	add := func(kind, id, name string, count int) {
+		key := kind + ":" + strings.ToLower(name)
+		if i, ok := index[key]; ok {
+			entities[i].ItemCount += count
+			return
+		}
+		index[key] = len(entities)
+		entities = append(entities, models.Entity{ID: id, Name: name, Kind: kind, ItemCount: count})
+	}
+
+	for _, lib := range libraries {
+		response, err := a.client.searchLibrary(lib.ID, text)
+		if err != nil {
+			return nil, err
+		}
+		for _, author := range response.Authors {
+			add(models.EntityAuthor, author.ID, author.Name, author.NumBooks)
+		}
+		for _, series := range response.Series {
+			add(models.EntitySeries, series.Series.ID, series.Series.Name, len(series.Books))
+		}
+		for _, narrator := range response.Narrators {
+			add(models.EntityNarrator, "", narrator.Name, narrator.NumBooks)
+		}
+		for _, tag := range response.Tags {
+			add(models.EntityTag, "", tag.Name, tag.NumItems)
+		}
+	}
+
+	return entities, nil
+}
+
+// GetEntityItems 实现 EntitySearcher 接口，在所有媒体库中按作者、系列、演播者或标签过滤项目
+func (a *AbsAdapter) GetEntityItems(kind, name string) ([]models.SearchResult, error) {
+	libraries, err := a.client.GetLibrariesInfo()
+	if err != nil {
+		return nil, fmt.Errorf("获取媒体库列表失败: %w", err)
+	}
+
+	var results []models.SearchResult
+	seen := make(map[string]bool)
+	for _, lib := range libraries {
+		// 播客媒体库只有标签
+		if lib.MediaType == "podcast" && kind != models.EntityTag {
+			continue
+		}
+
+		filters, err := a.entityFilters(lib.ID, kind, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, filter := range filters {
+			items, _, err := a.client.GetLibraryItems(lib.ID, absSearchLimit, 0, "media.metadata.title", false, filter)
+			if err != nil {
+				return nil, err
+			}
+			for i := range items {
+				if seen[items[i].ID] {
+					continue
+				}
+				seen[items[i].ID] = true
+				results = append(results, *a.toSearchResult(&items[i]))
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// entityFilters 返回媒体库中与实体对应的过滤表达式，作者和系列需要先搜索出 ID
+func (a *AbsAdapter) entityFilters(libraryID, kind, name string) ([]string, error) {
+	switch kind {
+	case models.EntityNarrator:
+		return []string{absFilter("narrators", name)}, nil
+	case models.EntityTag:
+		return []string{absFilter("tags", name)}, nil
+	}
+
+	response, err := a.client.searchLibrary(libraryID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var filters []string
+	switch kind {
+	case models.EntitySeries:
+		for _, series := range response.Series {
+			if strings.EqualFold(series.Series.Name, name) {
+				filters = append(filters, absFilter("series", series.Series.ID))
+			}
+		}
+	case models.EntityAuthor, models.EntityPerson:
+		for _, author := range response.Authors {
+			if strings.EqualFold(author.Name, name) {
+				filters = append(filters, absFilter("authors", author.ID))
+			}
+		}
+		// 其他服务器上的人物也可能是演播者
+		if kind == models.EntityPerson {
+			for _, narrator := range response.Narrators {
+				if strings.EqualFold(narrator.Name, name) {
+					filters = append(filters, absFilter("narrators", narrator.Name))
+				}
+			}
+		}
+	}
+
+	return filters, nil
+}
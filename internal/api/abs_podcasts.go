@@ -0,0 +1,279 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+const (
+	// absPodcastListLimit 每个播客媒体库最多列出的播客数量
+	absPodcastListLimit = 200
+	// absCheckNewLimit 检查新单集时最多下载的单集数量
+	absCheckNewLimit = 5
+)
+
+// absPodcastLibraries 返回所有播客媒体库
+func (a *AbsAdapter) absPodcastLibraries() ([]models.AbsLibraryInfo, error) {
+	libraries, err := a.client.GetLibrariesInfo()
+	if err != nil {
+		return nil, fmt.Errorf("获取媒体库列表失败: %w", err)
+	}
+
+	var podcastLibraries []models.AbsLibraryInfo
+	for _, lib := range libraries {
+		if lib.MediaType == "podcast" {
+			podcastLibraries = append(podcastLibraries, lib)
+		}
+	}
+	return podcastLibraries, nil
+}
+
+// GetPodcasts 实现 PodcastManager 接口
+func (a *AbsAdapter) GetPodcasts() ([]models.Podcast, error) {
+	libraries, err := a.absPodcastLibraries()
+	if err != nil {
+		return nil, err
+	}
+
+	var podcasts []models.Podcast
+	for _, lib := range libraries {
+		items, _, err := a.client.GetLibraryItems(lib.ID, absPodcastListLimit, 0, "media.metadata.title", false, "")
+		if err != nil {
+			return nil, fmt.Errorf("获取媒体库 %s 的播客失败: %w", lib.Name, err)
+		}
+		for i := range items {
+			podcast := toPodcast(&items[i])
+			podcast.Library = lib.Name
+			podcasts = append(podcasts, podcast)
+		}
+	}
+
+	return podcasts, nil
+}
+
+// toPodcast 将媒体库项目转换为播客
+func toPodcast(item *models.AbsLibraryItem) models.Podcast {
+	episodeCount := item.Media.NumEpisodes
+	if len(item.Media.Episodes) > 0 {
+		episodeCount = len(item.Media.Episodes)
+	}
+	return models.Podcast{
+		ID:           item.ID,
+		LibraryID:    item.LibraryID,
+		Title:        item.Media.Metadata.Title,
+		Author:       item.Media.Metadata.AuthorName(),
+		FeedURL:      item.Media.Metadata.FeedURL,
+		EpisodeCount: episodeCount,
+		AutoDownload: item.Media.AutoDownloadEpisodes,
+	}
+}
+
+// toPodcastEpisode 将 Audiobookshelf 单集转换为通用单集
+func toPodcastEpisode(episode *models.AbsPodcastEpisode) models.PodcastEpisode {
+	result := models.PodcastEpisode{
+		ID:          episode.ID,
+		PodcastID:   episode.LibraryItemID,
+		Title:       episode.Title,
+		PublishedAt: episode.PublishedAt,
+		AddedAt:     episode.AddedAt,
+		Duration:    episode.Duration,
+	}
+	if episode.Podcast != nil {
+		result.PodcastTitle = episode.Podcast.Metadata.Title
+	}
+	return result
+}
+
+// GetRecentEpisodes 实现 PodcastManager 接口
+func (a *AbsAdapter) GetRecentEpisodes(limit int) ([]models.PodcastEpisode, error) {
+	libraries, err := a.absPodcastLibraries()
+	if err != nil {
+		return nil, err
+	}
+
+	var episodes []models.PodcastEpisode
+	for _, lib := range libraries {
+		libraryEpisodes, err := a.client.GetRecentEpisodes(lib.ID, limit)
+		if err != nil {
+			return nil, fmt.Errorf("获取媒体库 %s 的最新单集失败: %w", lib.Name, err)
+		}
+		for i := range libraryEpisodes {
+			episodes = append(episodes, toPodcastEpisode(&libraryEpisodes[i]))
+		}
+	}
+
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return episodes[i].AddedAt > episodes[j].AddedAt
+	})
+	if len(episodes) > limit {
+		episodes = episodes[:limit]
+	}
+
+	return episodes, nil
+}
+
+// AddPodcast 实现 PodcastManager 接口，播客添加到第一个播客媒体库的第一个文件夹中
+func (a *AbsAdapter) AddPodcast(feedURL string) (*models.Podcast, error) {
+	libraries, err := a.absPodcastLibraries()
+	if err != nil {
+		return nil, err
+	}
+	var library *models.AbsLibraryInfo
+	for i := range libraries {
+		if len(libraries[i].Folders) > 0 {
+			library = &libraries[i]
+			break
+		}
+	}
+	if library == nil {
+		return nil, fmt.Errorf("没有可用的播客媒体库")
+	}
+
+	feed, err := a.client.GetPodcastFeed(feedURL)
+	if err != nil {
+		return nil, fmt.Errorf("获取 RSS 订阅源失败: %w", err)
+	}
+	if feed.Metadata.FeedURL == "" {
+		feed.Metadata.FeedURL = feedURL
+	}
+
+	folder := library.Folders[0]
+	dirName := sanitizeFileName(feed.Metadata.Title)
+	if dirName == "" {
+		return nil, fmt.Errorf("订阅源缺少播客标题")
+	}
+
+	request := &models.AbsNewPodcast{
+		Path:      strings.TrimRight(folder.Path, "/\\") + "/" + dirName,
+		FolderID:  folder.ID,
+		LibraryID: library.ID,
+	}
+	request.Media.Metadata = models.AbsMediaMetadata{
+		Title:       feed.Metadata.Title,
+		Author:      feed.Metadata.Author,
+		Description: feed.Metadata.Description,
+		Genres:      feed.Metadata.Categories,
+		FeedURL:     feed.Metadata.FeedURL,
+		ImageURL:    feed.Metadata.ImageURL,
+	}
+
+	item, err := a.client.CreatePodcast(request)
+	if err != nil {
+		return nil, fmt.Errorf("创建播客失败: %w", err)
+	}
+
+	podcast := toPodcast(item)
+	podcast.LibraryID = library.ID
+	podcast.Library = library.Name
+	return &podcast, nil
+}
+
+// sanitizeFileName 去掉文件名中不允许的字符
+func sanitizeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < 0x20 {
+			return -1
+		}
+		return r
+	}, name)
+	return strings.Trim(strings.TrimSpace(name), ".")
+}
+
+// CheckNewEpisodes 实现 PodcastManager 接口
+func (a *AbsAdapter) CheckNewEpisodes(podcastID string) ([]models.PodcastEpisode, error) {
+	absEpisodes, err := a.client.CheckNewEpisodes(podcastID, absCheckNewLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	episodes := make([]models.PodcastEpisode, len(absEpisodes))
+	for i := range absEpisodes {
+		episodes[i] = toPodcastEpisode(&absEpisodes[i])
+		episodes[i].PodcastID = podcastID
+	}
+	return episodes, nil
+}
+
+// feedEpisodeKey 返回订阅源单集的标识，优先使用 GUID
+func feedEpisodeKey(guid string, enclosure *models.AbsPodcastEnclosure) string {
+	if guid != "" {
+		return guid
+	}
+	if enclosure != nil {
+		return enclosure.URL
+	}
+	return ""
+}
+
+// podcastFeed 获取播客及其订阅源，返回已下载单集的标识集合
+func (a *AbsAdapter) podcastFeed(podcastID string) (*models.AbsPodcastFeed, map[string]bool, error) {
+	item, err := a.client.GetLibraryItem(podcastID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if item.Media.Metadata.FeedURL == "" {
+		return nil, nil, fmt.Errorf("播客没有设置 RSS 地址")
+	}
+
+	feed, err := a.client.GetPodcastFeed(item.Media.Metadata.FeedURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取 RSS 订阅源失败: %w", err)
+	}
+
+	downloaded := make(map[string]bool)
+	for _, episode := range item.Media.Episodes {
+		if episode.GUID != "" {
+			downloaded[episode.GUID] = true
+		}
+		if episode.Enclosure != nil && episode.Enclosure.URL != "" {
+			downloaded[episode.Enclosure.URL] = true
+		}
+	}
+	return feed, downloaded, nil
+}
+
+// GetFeedEpisodes 实现 PodcastManager 接口
+func (a *AbsAdapter) GetFeedEpisodes(podcastID string) ([]models.PodcastFeedEpisode, error) {
+	feed, downloaded, err := a.podcastFeed(podcastID)
+	if err != nil {
+		return nil, err
+	}
+
+	var episodes []models.PodcastFeedEpisode
+	for _, episode := range feed.Episodes {
+		key := feedEpisodeKey(episode.GUID, episode.Enclosure)
+		if key == "" {
+			continue
+		}
+		isDownloaded := downloaded[key]
+		if episode.Enclosure != nil && downloaded[episode.Enclosure.URL] {
+			isDownloaded = true
+		}
+		episodes = append(episodes, models.PodcastFeedEpisode{
+			Key:         key,
+			Title:       episode.Title,
+			PublishedAt: episode.PublishedAt,
+			Duration:    episode.Duration,
+			Downloaded:  isDownloaded,
+		})
+	}
+	return episodes, nil
+}
+
+// DownloadEpisode 实现 PodcastManager 接口
+func (a *AbsAdapter) DownloadEpisode(podcastID, episodeKey string) error {
+	feed, _, err := a.podcastFeed(podcastID)
+	if err != nil {
+		return err
+	}
+
+	for _, episode := range feed.Episodes {
+		if feedEpisodeKey(episode.GUID, episode.Enclosure) == episodeKey {
+			return a.client.DownloadEpisodes(podcastID, []models.AbsPodcastFeedEpisode{episode})
+		}
+	}
+	return fmt.Errorf("订阅源中没有找到该单集")
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"log"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// GetProgressItems 实现 ProgressTracker 接口，返回当前用户有进度记录的书籍，播客单集不包括在内
+func (a *AbsAdapter) GetProgressItems() ([]models.ItemProgress, error) {
+	user, err := a.client.GetCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []models.ItemProgress
+	for _, progress := range user.MediaProgress {
+		if progress.EpisodeID != "" {
+			continue
+		}
+		item, err := a.client.GetLibraryItem(progress.LibraryItemID)
+		if err != nil {
+			// 项目可能已被删除，进度记录仍然保留
+			log.Printf("获取 Audiobookshelf 项目 %s 失败: %v", progress.LibraryItemID, err)
+			continue
+		}
+		duration := progress.Duration
+		if duration <= 0 {
+			duration = item.Media.Duration
+		}
+		result = append(result, models.ItemProgress{
+			Item:       *a.toSearchResult(item),
+			Position:   progress.CurrentTime,
+			Duration:   duration,
+			Finished:   progress.IsFinished,
+			LastUpdate: progress.LastUpdate,
+		})
+	}
+
+	return result, nil
+}
+
+// SetProgress 实现 ProgressTracker 接口
+func (a *AbsAdapter) SetProgress(itemID string, position float64, finished bool) error {
+	item, err := a.client.GetLibraryItem(itemID)
+	if err != nil {
+		return err
+	}
+
+	update := &models.AbsProgressUpdate{
+		Duration:    item.Media.Duration,
+		CurrentTime: position,
+		IsFinished:  finished,
+	}
+	if finished {
+		update.Progress = 1
+	} else if item.Media.Duration > 0 {
+		update.Progress = position / item.Media.Duration
+	}
+	return a.client.UpdateMediaProgress(itemID, update)
+}
+
+// SetPlayed 实现 ProgressTracker 接口
+func (a *AbsAdapter) SetPlayed(itemID string, played bool) error {
+	update := &models.AbsProgressUpdate{IsFinished: played}
+	if played {
+		update.Progress = 1
+	}
+	return a.client.UpdateMediaProgress(itemID, update)
+}
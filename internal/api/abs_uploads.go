@@ -0,0 +1,40 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// GetUploadTargets 实现 FileUploader 接口，返回书籍媒体库的所有文件夹
+// 播客媒体库的单集由订阅源下载，不接受上传
+func (a *AbsAdapter) GetUploadTargets() ([]models.UploadTarget, error) {
+	libraries, err := a.client.GetLibrariesInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []models.UploadTarget
+	for _, library := range libraries {
+		if library.MediaType == "podcast" {
+			continue
+		}
+		for _, folder := range library.Folders {
+			targets = append(targets, models.UploadTarget{
+				LibraryID: library.ID,
+				Library:   library.Name,
+				FolderID:  folder.ID,
+				Folder:    folder.Path,
+			})
+		}
+	}
+	return targets, nil
+}
+
+// UploadFile 实现 FileUploader 接口，服务器会在文件夹下创建 “作者/标题” 目录
+func (a *AbsAdapter) UploadFile(target models.UploadTarget, upload models.FileUpload) error {
+	if upload.Title == "" {
+		return fmt.Errorf("上传到 Audiobookshelf 需要填写标题")
+	}
+	return a.client.UploadFile(target.LibraryID, target.FolderID, upload.Title, upload.Author, upload.Name, upload.Reader)
+}
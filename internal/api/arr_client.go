@@ -0,0 +1,222 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+const (
+	// arrQueuePageSize 读取下载队列时的分页大小
+	arrQueuePageSize = 50
+	// arrEventDownloadImported 历史记录中文件导入完成的事件类型，Radarr 和 Sonarr 的 downloadFolderImported 与 Readarr 的 bookFileImported 都是 3
+	arrEventDownloadImported = 3
+)
+
+// arrClient Radarr、Sonarr 和 Readarr 共用的 API 客户端，各服务的接口路径和认证方式相同
+type arrClient struct {
+	name       string
+	baseURL    string
+	apiVersion string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// newArrClient 创建 API 客户端，apiVersion 为 v3 或 v1
+func newArrClient(name, baseURL, apiVersion, apiKey string) *arrClient {
+	return &arrClient{
+		name:       name,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiVersion: apiVersion,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name 实现 MediaAcquirer 接口
+func (c *arrClient) Name() string {
+	return c.name
+}
+
+// doRequest performs an HTTP request to the API and decodes the JSON response into v
+func (c *arrClient) doRequest(method, path string, params url.Values, body, v interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error marshaling request body: %w", err)
+		}
+		reqBody = bytes.NewBuffer(jsonData)
+	}
+
+	endpoint := c.baseURL + "/api/" + c.apiVersion + path
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+	req, err := http.NewRequest(method, endpoint, reqBody)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("X-Api-Key", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if v == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, v); err != nil {
+		return fmt.Errorf("error unmarshaling %s response: %w", path, err)
+	}
+	return nil
+}
+
+// GetQualityProfiles 实现 MediaAcquirer 接口
+func (c *arrClient) GetQualityProfiles() ([]models.ArrProfile, error) {
+	var profiles []models.ArrProfile
+	if err := c.doRequest(http.MethodGet, "/qualityprofile", nil, nil, &profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// GetRootFolders 实现 MediaAcquirer 接口
+func (c *arrClient) GetRootFolders() ([]models.ArrRootFolder, error) {
+	var folders []models.ArrRootFolder
+	if err := c.doRequest(http.MethodGet, "/rootfolder", nil, nil, &folders); err != nil {
+		return nil, err
+	}
+	return folders, nil
+}
+
+// getQueue 获取下载队列，itemField 为记录中作品 ID 的字段名，如 movieId
+func (c *arrClient) getQueue(params url.Values, itemField string) ([]models.ArrQueueItem, error) {
+	params.Set("page", "1")
+	params.Set("pageSize", fmt.Sprintf("%d", arrQueuePageSize))
+
+	var response struct {
+		Records []map[string]interface{} `json:"records"`
+	}
+	if err := c.doRequest(http.MethodGet, "/queue", params, nil, &response); err != nil {
+		return nil, err
+	}
+
+	items := make([]models.ArrQueueItem, 0, len(response.Records))
+	for _, record := range response.Records {
+		items = append(items, models.ArrQueueItem{
+			ID:       jsonInt(record["id"]),
+			ItemID:   jsonInt(record[itemField]),
+			Title:    jsonString(record["title"]),
+			Status:   jsonString(record["status"]),
+			State:    jsonString(record["trackedDownloadState"]),
+			Size:     jsonFloat(record["size"]),
+			SizeLeft: jsonFloat(record["sizeleft"]),
+			TimeLeft: jsonString(record["timeleft"]),
+		})
+	}
+	return items, nil
+}
+
+// getImportedSince 获取指定时间之后导入完成的历史记录，itemField 为记录中作品 ID 的字段名
+func (c *arrClient) getImportedSince(since time.Time, itemField string) ([]models.ArrImport, error) {
+	params := url.Values{}
+	params.Set("date", since.UTC().Format(time.RFC3339))
+	params.Set("eventType", fmt.Sprintf("%d", arrEventDownloadImported))
+
+	var records []map[string]interface{}
+	if err := c.doRequest(http.MethodGet, "/history/since", params, nil, &records); err != nil {
+		return nil, err
+	}
+
+	imports := make([]models.ArrImport, 0, len(records))
+	for _, record := range records {
+		date, _ := time.Parse(time.RFC3339, jsonString(record["date"]))
+		imports = append(imports, models.ArrImport{
+			ID:     jsonInt(record["id"]),
+			ItemID: jsonInt(record[itemField]),
+			Title:  jsonString(record["sourceTitle"]),
+			Date:   date,
+		})
+	}
+	return imports, nil
+}
+
+// addResource 将查找得到的作品连同添加选项提交给服务，lookup 中已有 ID 时视为已存在
+func (c *arrClient) addResource(path string, lookup map[string]interface{}, fields map[string]interface{}) (*models.ArrItem, error) {
+	if id := jsonInt(lookup["id"]); id > 0 {
+		return &models.ArrItem{ID: id, Title: jsonString(lookup["title"]), Year: jsonInt(lookup["year"]), Existed: true}, nil
+	}
+
+	for key, value := range fields {
+		lookup[key] = value
+	}
+	var added map[string]interface{}
+	if err := c.doRequest(http.MethodPost, path, nil, lookup, &added); err != nil {
+		return nil, err
+	}
+	return &models.ArrItem{ID: jsonInt(added["id"]), Title: jsonString(added["title"]), Year: jsonInt(added["year"])}, nil
+}
+
+// pickLookupResult 在查找结果中选择请求的作品：外部 ID 相同，或者标题一致且年份相差不超过一年
+// idField 为结果中外部 ID 的字段名，如 tmdbId
+func pickLookupResult(results []map[string]interface{}, metadata *models.MediaMetadata, idField, providerID string) (map[string]interface{}, bool) {
+	if providerID != "" {
+		for _, result := range results {
+			if fmt.Sprintf("%d", jsonInt(result[idField])) == providerID {
+				return result, true
+			}
+		}
+	}
+
+	titles := map[string]bool{util.NormalizeTitle(metadata.Title): true}
+	if metadata.OriginalTitle != "" {
+		titles[util.NormalizeTitle(metadata.OriginalTitle)] = true
+	}
+	for _, result := range results {
+		if !titles[util.NormalizeTitle(jsonString(result["title"]))] {
+			continue
+		}
+		year := jsonInt(result["year"])
+		if metadata.Year == 0 || year == 0 || (year-metadata.Year <= 1 && metadata.Year-year <= 1) {
+			return result, true
+		}
+	}
+	return nil, false
+}
+
+// jsonString 读取 JSON 对象中的字符串字段
+func jsonString(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
+
+// jsonFloat 读取 JSON 对象中的数字字段
+func jsonFloat(value interface{}) float64 {
+	f, _ := value.(float64)
+	return f
+}
+
+// jsonInt 读取 JSON 对象中的整数字段
+func jsonInt(value interface{}) int {
+	return int(jsonFloat(value))
+}
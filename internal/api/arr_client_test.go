@@ -0,0 +1,529 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+const testArrAPIKey = "test-key"
+
+// fakeArr 模拟 Radarr/Sonarr/Readarr 的 API，routes 的键为 “方法 路径”，记录收到的请求
+type fakeArr struct {
+	t      *testing.T
+	routes map[string]func(r *http.Request) (int, interface{})
+
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   map[string]map[string]interface{}
+}
+
+func newFakeArr(t *testing.T, routes map[string]func(r *http.Request) (int, interface{})) (*fakeArr, string) {
+	fake := &fakeArr{t: t, routes: routes, bodies: make(map[string]map[string]interface{})}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server.URL
+}
+
+func (f *fakeArr) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Api-Key") != testArrAPIKey {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	key := r.Method + " " + r.URL.Path
+	f.mu.Lock()
+	f.requests = append(f.requests, r)
+	if r.Body != nil {
+		data, _ := io.ReadAll(r.Body)
+		if len(data) > 0 {
+			var body map[string]interface{}
+			if err := json.Unmarshal(data, &body); err != nil {
+				f.t.Errorf("%s: invalid JSON body: %v", key, err)
+			}
+			f.bodies[key] = body
+		}
+	}
+	f.mu.Unlock()
+
+	route, ok := f.routes[key]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	status, response := route(r)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if s, ok := response.(string); ok {
+		w.Write([]byte(s))
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// body 返回指定请求的 JSON 请求体
+func (f *fakeArr) body(key string) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.bodies[key]
+}
+
+// called 判断是否收到过指定请求
+func (f *fakeArr) called(key string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.requests {
+		if r.Method+" "+r.URL.Path == key {
+			return true
+		}
+	}
+	return false
+}
+
+func ok(v interface{}) func(*http.Request) (int, interface{}) {
+	return func(*http.Request) (int, interface{}) { return http.StatusOK, v }
+}
+
+func newTestRadarr(url string) *RadarrClient {
+	return NewRadarrClient(&config.Config{RadarrURL: url + "/", RadarrAPIKey: testArrAPIKey})
+}
+
+func newTestSonarr(url string) *SonarrClient {
+	return NewSonarrClient(&config.Config{SonarrURL: url, SonarrAPIKey: testArrAPIKey})
+}
+
+func TestArrProfilesAndRootFolders(t *testing.T) {
+	_, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v3/qualityprofile": ok([]map[string]interface{}{{"id": 1, "name": "Any"}, {"id": 4, "name": "HD-1080p"}}),
+		"GET /api/v3/rootfolder":     ok([]map[string]interface{}{{"id": 2, "path": "/media/movies", "freeSpace": 1 << 40}}),
+	})
+	client := newTestRadarr(url)
+
+	profiles, err := client.GetQualityProfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 2 || profiles[1].ID != 4 || profiles[1].Name != "HD-1080p" {
+		t.Errorf("profiles = %+v", profiles)
+	}
+
+	folders, err := client.GetRootFolders()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(folders) != 1 || folders[0].Path != "/media/movies" || folders[0].FreeSpace != 1<<40 {
+		t.Errorf("folders = %+v", folders)
+	}
+}
+
+func TestRadarrAddByTMDbID(t *testing.T) {
+	fake, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v3/movie/lookup/tmdb": func(r *http.Request) (int, interface{}) {
+			if r.URL.Query().Get("tmdbId") != "438631" {
+				t.Errorf("tmdbId = %q", r.URL.Query().Get("tmdbId"))
+			}
+			return http.StatusOK, map[string]interface{}{"title": "Dune", "year": 2021, "tmdbId": 438631}
+		},
+		"POST /api/v3/movie": ok(map[string]interface{}{"id": 7, "title": "Dune", "year": 2021}),
+	})
+
+	metadata := &models.MediaMetadata{Title: "沙丘", Year: 2021, ProviderIDs: map[string]string{models.ProviderTMDb: "438631"}}
+	item, err := newTestRadarr(url).Add(metadata, models.ArrAddOptions{QualityProfileID: 4, RootFolderPath: "/media/movies"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ID != 7 || item.Existed {
+		t.Errorf("item = %+v", item)
+	}
+
+	body := fake.body("POST /api/v3/movie")
+	if body["qualityProfileId"] != float64(4) || body["rootFolderPath"] != "/media/movies" || body["monitored"] != true {
+		t.Errorf("add body = %v", body)
+	}
+	if body["tmdbId"] != float64(438631) || body["title"] != "Dune" {
+		t.Errorf("add body lost lookup fields: %v", body)
+	}
+	if options, _ := body["addOptions"].(map[string]interface{}); options["searchForMovie"] != true {
+		t.Errorf("addOptions = %v", body["addOptions"])
+	}
+}
+
+func TestRadarrAddByTitle(t *testing.T) {
+	fake, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v3/movie/lookup": func(r *http.Request) (int, interface{}) {
+			if r.URL.Query().Get("term") != "Dune" {
+				return http.StatusOK, []interface{}{}
+			}
+			return http.StatusOK, []map[string]interface{}{
+				{"title": "Dune", "year": 1984, "tmdbId": 841},
+				{"title": "Dune", "year": 2021, "tmdbId": 438631},
+			}
+		},
+		"POST /api/v3/movie": ok(map[string]interface{}{"id": 8, "title": "Dune", "year": 2021}),
+	})
+
+	metadata := &models.MediaMetadata{Title: "沙丘", OriginalTitle: "Dune", Year: 2021}
+	if _, err := newTestRadarr(url).Add(metadata, models.ArrAddOptions{QualityProfileID: 1, RootFolderPath: "/m"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.body("POST /api/v3/movie")["tmdbId"]; got != float64(438631) {
+		t.Errorf("added tmdbId = %v, want 438631", got)
+	}
+}
+
+func TestRadarrAddExisting(t *testing.T) {
+	fake, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v3/movie/lookup/tmdb": ok(map[string]interface{}{"id": 3, "title": "Dune", "year": 2021}),
+	})
+
+	metadata := &models.MediaMetadata{Title: "Dune", ProviderIDs: map[string]string{models.ProviderTMDb: "438631"}}
+	item, err := newTestRadarr(url).Add(metadata, models.ArrAddOptions{QualityProfileID: 1, RootFolderPath: "/m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ID != 3 || !item.Existed {
+		t.Errorf("item = %+v, want existing #3", item)
+	}
+	if fake.called("POST /api/v3/movie") {
+		t.Error("existing movie was added again")
+	}
+}
+
+func TestRadarrAddNotFound(t *testing.T) {
+	_, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v3/movie/lookup": ok([]map[string]interface{}{{"title": "Something Else", "year": 2021}}),
+	})
+
+	_, err := newTestRadarr(url).Add(&models.MediaMetadata{Title: "Dune", Year: 2021}, models.ArrAddOptions{})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("err = %v, want not found", err)
+	}
+}
+
+func TestSonarrAddFallsBackToOriginalTitle(t *testing.T) {
+	fake, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v3/series/lookup": func(r *http.Request) (int, interface{}) {
+			if r.URL.Query().Get("term") != "Severance" {
+				return http.StatusOK, []interface{}{}
+			}
+			return http.StatusOK, []map[string]interface{}{{"title": "Severance", "year": 2022, "tvdbId": 371980, "tmdbId": 95396}}
+		},
+		"GET /api/v3/languageprofile": ok([]map[string]interface{}{{"id": 1, "name": "English"}}),
+		"POST /api/v3/series":         ok(map[string]interface{}{"id": 12, "title": "Severance", "year": 2022}),
+	})
+
+	metadata := &models.MediaMetadata{Title: "人生切割术", OriginalTitle: "Severance", Year: 2022}
+	item, err := newTestSonarr(url).Add(metadata, models.ArrAddOptions{QualityProfileID: 6, RootFolderPath: "/media/tv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ID != 12 {
+		t.Errorf("item = %+v", item)
+	}
+
+	body := fake.body("POST /api/v3/series")
+	if body["qualityProfileId"] != float64(6) || body["languageProfileId"] != float64(1) || body["rootFolderPath"] != "/media/tv" {
+		t.Errorf("add body = %v", body)
+	}
+	if options, _ := body["addOptions"].(map[string]interface{}); options["searchForMissingEpisodes"] != true {
+		t.Errorf("addOptions = %v", body["addOptions"])
+	}
+}
+
+func TestSonarrAddByTVDbID(t *testing.T) {
+	var terms []string
+	fake, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v3/series/lookup": func(r *http.Request) (int, interface{}) {
+			terms = append(terms, r.URL.Query().Get("term"))
+			return http.StatusOK, []map[string]interface{}{{"title": "Severance", "year": 2022, "tvdbId": 371980}}
+		},
+		"GET /api/v3/languageprofile": ok([]map[string]interface{}{{"id": 2, "name": "Chinese"}}),
+		"POST /api/v3/series":         ok(map[string]interface{}{"id": 13, "title": "Severance", "year": 2022}),
+	})
+
+	metadata := &models.MediaMetadata{Title: "人生切割术", ProviderIDs: map[string]string{models.ProviderTVDb: "371980"}}
+	if _, err := newTestSonarr(url).Add(metadata, models.ArrAddOptions{QualityProfileID: 1, RootFolderPath: "/media/tv"}); err != nil {
+		t.Fatal(err)
+	}
+	// 按 TVDb ID 找到后不再按译名查找
+	if len(terms) != 1 || terms[0] != "tvdb:371980" {
+		t.Errorf("lookup terms = %q, want only tvdb:371980", terms)
+	}
+	if got := fake.body("POST /api/v3/series")["tvdbId"]; got != float64(371980) {
+		t.Errorf("added tvdbId = %v", got)
+	}
+}
+
+func TestSonarrAddWithoutLanguageProfile(t *testing.T) {
+	_, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v3/series/lookup":   ok([]map[string]interface{}{{"title": "Severance", "year": 2022}}),
+		"GET /api/v3/languageprofile": ok([]interface{}{}),
+	})
+
+	_, err := newTestSonarr(url).Add(&models.MediaMetadata{Title: "Severance", Year: 2022}, models.ArrAddOptions{})
+	if err == nil || !strings.Contains(err.Error(), "language profile") {
+		t.Errorf("err = %v, want missing language profile", err)
+	}
+}
+
+func TestArrQueue(t *testing.T) {
+	_, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v3/queue": func(r *http.Request) (int, interface{}) {
+			if r.URL.Query().Get("includeSeries") != "false" || r.URL.Query().Get("pageSize") == "" {
+				t.Errorf("queue query = %s", r.URL.RawQuery)
+			}
+			return http.StatusOK, map[string]interface{}{"records": []map[string]interface{}{{
+				"id": 1, "seriesId": 12, "title": "Severance.S01E01", "status": "downloading",
+				"trackedDownloadState": "downloading", "size": 1000.0, "sizeleft": 250.0, "timeleft": "00:05:00",
+			}}}
+		},
+	})
+
+	items, err := newTestSonarr(url).GetQueue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].ItemID != 12 || items[0].TimeLeft != "00:05:00" || items[0].Progress() != 0.75 {
+		t.Errorf("queue = %+v", items)
+	}
+}
+
+func TestArrImportedSince(t *testing.T) {
+	since := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	_, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v3/history/since": func(r *http.Request) (int, interface{}) {
+			query := r.URL.Query()
+			if query.Get("date") != "2026-10-01T12:00:00Z" || query.Get("eventType") != "3" {
+				t.Errorf("history query = %s", r.URL.RawQuery)
+			}
+			return http.StatusOK, []map[string]interface{}{
+				{"id": 40, "movieId": 7, "sourceTitle": "Dune.2021.2160p", "date": "2026-10-01T13:30:00Z"},
+			}
+		},
+	})
+
+	imports, err := newTestRadarr(url).GetImportedSince(since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2026, 10, 1, 13, 30, 0, 0, time.UTC)
+	if len(imports) != 1 || imports[0].ItemID != 7 || imports[0].Title != "Dune.2021.2160p" || !imports[0].Date.Equal(want) {
+		t.Errorf("imports = %+v", imports)
+	}
+}
+
+func TestArrErrors(t *testing.T) {
+	_, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v3/qualityprofile": func(*http.Request) (int, interface{}) {
+			return http.StatusInternalServerError, `{"message":"database is locked"}`
+		},
+		"GET /api/v3/rootfolder": ok("not json"),
+	})
+
+	if _, err := newTestRadarr(url).GetQualityProfiles(); err == nil || !strings.Contains(err.Error(), "status 500") {
+		t.Errorf("server error = %v, want status 500", err)
+	}
+	if _, err := newTestRadarr(url).GetRootFolders(); err == nil || !strings.Contains(err.Error(), "unmarshaling") {
+		t.Errorf("bad JSON error = %v, want unmarshaling error", err)
+	}
+
+	unauthorized := NewRadarrClient(&config.Config{RadarrURL: url, RadarrAPIKey: "wrong"})
+	if _, err := unauthorized.GetQueue(); err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Errorf("unauthorized error = %v, want status 401", err)
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	if _, err := newTestRadarr(closed.URL).GetImportedSince(time.Now()); err == nil {
+		t.Error("expected connection error")
+	}
+}
+
+func newTestReadarr(url string) *ReadarrClient {
+	return NewReadarrClient(&config.Config{ReadarrURL: url, ReadarrAPIKey: testArrAPIKey})
+}
+
+// readarrBook 返回 Readarr 搜索结果中的书籍，authorID 为 0 表示作者尚未添加
+func readarrBook(foreignID, title, author string, authorID int) map[string]interface{} {
+	return map[string]interface{}{
+		"foreignBookId": foreignID,
+		"title":         title,
+		"releaseDate":   "2008-01-01T00:00:00Z",
+		"author":        map[string]interface{}{"id": authorID, "authorName": author},
+		"editions": []map[string]interface{}{
+			{"title": title, "monitored": false},
+			{"title": title + " (Audiobook)", "monitored": false},
+		},
+	}
+}
+
+func TestReadarrSearchMetadata(t *testing.T) {
+	fake, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v1/search": func(r *http.Request) (int, interface{}) {
+			if r.URL.Query().Get("term") != "刘慈欣" {
+				return http.StatusOK, []interface{}{}
+			}
+			return http.StatusOK, []map[string]interface{}{
+				{"foreignId": "a1", "author": map[string]interface{}{"authorName": "刘慈欣"}},
+				{"foreignId": "b1", "book": readarrBook("b1", "三体", "刘慈欣", 0)},
+			}
+		},
+		"GET /api/v1/book/lookup": func(r *http.Request) (int, interface{}) {
+			if r.URL.Query().Get("term") != "刘慈欣" {
+				t.Errorf("book lookup term = %q", r.URL.Query().Get("term"))
+			}
+			return http.StatusOK, []map[string]interface{}{
+				readarrBook("b1", "三体", "刘慈欣", 0),
+				readarrBook("b2", "球状闪电", "刘慈欣", 0),
+				readarrBook("b3", "刘慈欣传", "某传记作者", 0),
+			}
+		},
+	})
+
+	results, err := newTestReadarr(url).SearchMetadata("刘慈欣")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, result := range results {
+		if result.Provider != "readarr" || result.Kind != models.MediaKindBook || result.Author != "刘慈欣" || result.Year != 2008 {
+			t.Errorf("result = %+v", result)
+		}
+		titles = append(titles, result.Title)
+	}
+	// 作者的书籍去重，其他作者的书籍不列出
+	if strings.Join(titles, ",") != "三体,球状闪电" {
+		t.Errorf("titles = %q, want 三体,球状闪电", titles)
+	}
+
+	results, err = newTestReadarr(url).SearchMetadata("不存在")
+	if err != nil || len(results) != 0 {
+		t.Errorf("empty search = %v, %v", results, err)
+	}
+	if !fake.called("GET /api/v1/book/lookup") {
+		t.Error("author books were not looked up")
+	}
+}
+
+func TestReadarrAddNewAuthor(t *testing.T) {
+	var terms []string
+	fake, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v1/search": func(r *http.Request) (int, interface{}) {
+			terms = append(terms, r.URL.Query().Get("term"))
+			return http.StatusOK, []map[string]interface{}{
+				{"book": readarrBook("b9", "三体", "其他作者", 0)},
+				{"book": readarrBook("b1", "三体", "刘慈欣", 0)},
+			}
+		},
+		"POST /api/v1/book": ok(map[string]interface{}{"id": 21, "title": "三体"}),
+	})
+
+	metadata := &models.MediaMetadata{Provider: "openlibrary", Title: "三体", Author: "刘慈欣"}
+	options := models.ArrAddOptions{QualityProfileID: 2, MetadataProfileID: 3, RootFolderPath: "/audiobooks"}
+	item, err := newTestReadarr(url).Add(metadata, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ID != 21 || item.Existed || item.Year != 2008 {
+		t.Errorf("item = %+v", item)
+	}
+	if len(terms) != 1 || terms[0] != "三体 刘慈欣" {
+		t.Errorf("search terms = %q", terms)
+	}
+
+	body := fake.body("POST /api/v1/book")
+	if body["foreignBookId"] != "b1" || body["monitored"] != true {
+		t.Errorf("add body = %v", body)
+	}
+	if options, _ := body["addOptions"].(map[string]interface{}); options["searchForNewBook"] != true {
+		t.Errorf("book addOptions = %v", body["addOptions"])
+	}
+	author, _ := body["author"].(map[string]interface{})
+	if author["qualityProfileId"] != float64(2) || author["metadataProfileId"] != float64(3) ||
+		author["rootFolderPath"] != "/audiobooks" || author["monitored"] != true {
+		t.Errorf("author = %v", author)
+	}
+	// 只监控请求的书，不监控作者的其他书籍
+	if options, _ := author["addOptions"].(map[string]interface{}); options["monitor"] != "none" || options["searchForMissingBooks"] != false {
+		t.Errorf("author addOptions = %v", author["addOptions"])
+	}
+	editions, _ := body["editions"].([]interface{})
+	if len(editions) != 2 || editions[0].(map[string]interface{})["monitored"] != true || editions[1].(map[string]interface{})["monitored"] != false {
+		t.Errorf("editions = %v, want only the first monitored", editions)
+	}
+}
+
+func TestReadarrAddByForeignID(t *testing.T) {
+	fake, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v1/search": ok([]map[string]interface{}{
+			{"book": readarrBook("b1", "The Three-Body Problem", "Cixin Liu", 0)},
+			{"book": readarrBook("b2", "三体", "刘慈欣", 0)},
+		}),
+		"POST /api/v1/book": ok(map[string]interface{}{"id": 22, "title": "三体"}),
+	})
+
+	metadata := &models.MediaMetadata{Provider: "readarr", ID: "b2", Title: "三体 (有声书)"}
+	if _, err := newTestReadarr(url).Add(metadata, models.ArrAddOptions{RootFolderPath: "/audiobooks"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.body("POST /api/v1/book")["foreignBookId"]; got != "b2" {
+		t.Errorf("added foreignBookId = %v, want b2", got)
+	}
+}
+
+func TestReadarrAddExistingAuthor(t *testing.T) {
+	fake, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v1/search": ok([]map[string]interface{}{{"book": readarrBook("b1", "三体", "刘慈欣", 5)}}),
+		"POST /api/v1/book":  ok(map[string]interface{}{"id": 23, "title": "三体", "year": 2008}),
+	})
+
+	if _, err := newTestReadarr(url).Add(&models.MediaMetadata{Title: "三体"}, models.ArrAddOptions{QualityProfileID: 2, RootFolderPath: "/other"}); err != nil {
+		t.Fatal(err)
+	}
+	// 已添加的作者保留原有设置
+	author, _ := fake.body("POST /api/v1/book")["author"].(map[string]interface{})
+	if _, ok := author["rootFolderPath"]; ok || author["id"] != float64(5) {
+		t.Errorf("existing author = %v", author)
+	}
+}
+
+func TestReadarrAddExistingBook(t *testing.T) {
+	book := readarrBook("b1", "三体", "刘慈欣", 5)
+	book["id"] = 11
+	fake, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v1/search": ok([]map[string]interface{}{{"book": book}}),
+	})
+
+	item, err := newTestReadarr(url).Add(&models.MediaMetadata{Title: "三体", Author: "刘慈欣"}, models.ArrAddOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ID != 11 || !item.Existed {
+		t.Errorf("item = %+v, want existing #11", item)
+	}
+	if fake.called("POST /api/v1/book") {
+		t.Error("existing book was added again")
+	}
+}
+
+func TestReadarrAddNotFound(t *testing.T) {
+	_, url := newFakeArr(t, map[string]func(*http.Request) (int, interface{}){
+		"GET /api/v1/search": ok([]map[string]interface{}{
+			{"book": readarrBook("b9", "三体", "其他作者", 0)},
+			{"author": map[string]interface{}{"authorName": "刘慈欣"}},
+		}),
+	})
+
+	_, err := newTestReadarr(url).Add(&models.MediaMetadata{Title: "三体", Author: "刘慈欣"}, models.ArrAddOptions{})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("err = %v, want not found", err)
+	}
+}
@@ -12,17 +12,25 @@ import (
 // EmbyAdapter 实现 MediaServer 接口，作为 Emby 的适配器
 type EmbyAdapter struct {
 	client *EmbyClient
-	// 添加媒体库缓存
-	librariesCache      []models.LibraryInfo
+	// 媒体库缓存，用于确定项目所属的媒体库
+	librariesCache      []embyLibraryFolder
 	librariesCacheTime  time.Time
 	librariesCacheMutex sync.RWMutex
-	cacheExpiry         time.Duration
+	// 通过上级项目确定的媒体库，上级项目 ID -> 媒体库 ID，空字符串表示不属于任何媒体库，随媒体库缓存一起过期
+	parentLibraries map[string]string
+	cacheExpiry     time.Duration
+	// 机器人代表的 Emby 用户
+	userID    string
+	userMutex sync.Mutex
+	// 上传目标，媒体库名称 -> 机器人可写入的本地文件夹
+	uploadFolders map[string]string
 }
 
 // NewEmbyAdapter 创建新的 Emby 适配器
 func NewEmbyAdapter(client *EmbyClient) *EmbyAdapter {
 	return &EmbyAdapter{
-		client: client,
+		client:      client,
+		cacheExpiry: 30 * time.Minute, // 默认30分钟缓存过期时间
 	}
 }
 
@@ -94,21 +102,85 @@ func (e *EmbyAdapter) GetUsers() ([]models.UserInfo, error) {
 
 // GetCurrentUser 实现 MediaServer 接口
 func (e *EmbyAdapter) GetCurrentUser() (*models.UserInfo, error) {
-	data, err := e.client.GetCurrentUser()
+	embyUser, err := e.resolveUser()
 	if err != nil {
 		return nil, err
 	}
 
-	var embyUser models.EmbyUser
+	user := toUser(embyUser)
+
+	return user, nil
+}
+
+// resolveUser 确定机器人代表的 Emby 用户
+// 依次使用配置的用户、API 密钥所属的用户和第一个管理员
+func (e *EmbyAdapter) resolveUser() (*models.EmbyUser, error) {
+	if configured := e.client.ConfiguredUser(); configured != "" {
+		data, err := e.client.GetUsers()
+		if err != nil {
+			return nil, err
+		}
+
+		var embyUsers []models.EmbyUser
+		err = json.Unmarshal(data, &embyUsers)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshaling users: %w", err)
+		}
+
+		for i := range embyUsers {
+			if embyUsers[i].ID == configured || strings.EqualFold(embyUsers[i].Name, configured) {
+				return &embyUsers[i], nil
+			}
+		}
+		return nil, fmt.Errorf("emby user %s not found", configured)
+	}
+
+	data, err := e.client.GetCurrentUser()
+	if err == nil {
+		var embyUser models.EmbyUser
+		err = json.Unmarshal(data, &embyUser)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshaling current user: %w", err)
+		}
+		return &embyUser, nil
+	}
+
+	// 使用 API 密钥时没有当前用户，退回到第一个管理员
+	data, err = e.client.GetUsers()
+	if err != nil {
+		return nil, err
+	}
 
-	err = json.Unmarshal(data, &embyUser)
+	var embyUsers []models.EmbyUser
+	err = json.Unmarshal(data, &embyUsers)
 	if err != nil {
-		return nil, fmt.Errorf("error unmarshaling current user: %w", err)
+		return nil, fmt.Errorf("error unmarshaling users: %w", err)
+	}
+
+	for i := range embyUsers {
+		if embyUsers[i].Policy.IsAdministrator && !embyUsers[i].Policy.IsDisabled {
+			return &embyUsers[i], nil
+		}
 	}
+	return nil, fmt.Errorf("no emby user available")
+}
 
-	user := toUser(&embyUser)
+// getUserID 返回机器人代表的 Emby 用户ID，结果会被缓存
+func (e *EmbyAdapter) getUserID() (string, error) {
+	e.userMutex.Lock()
+	defer e.userMutex.Unlock()
 
-	return user, nil
+	if e.userID != "" {
+		return e.userID, nil
+	}
+
+	user, err := e.resolveUser()
+	if err != nil {
+		return "", err
+	}
+	e.userID = user.ID
+
+	return e.userID, nil
 }
 
 func toUser(embyUser *models.EmbyUser) *models.UserInfo {
@@ -211,34 +283,83 @@ func (e *EmbyAdapter) GetLibraryItemsCount(libraryID string) (int, error) {
 	return itemsResponse.TotalRecordCount, nil
 }
 
+// embySearchLimit 每个媒体库返回的最大搜索结果数量
+const embySearchLimit = 50
+
 // Search 实现 MediaServer 接口
-func (e *EmbyAdapter) Search(query string) ([]models.SearchResult, error) {
-	data, err := e.client.SearchItems(query, "", 50) // 限制返回50个结果
+func (e *EmbyAdapter) Search(query models.SearchQuery) ([]models.SearchResult, error) {
+	// 指定的类型在 Emby 中都不存在时无需搜索
+	if len(query.Types) > 0 && len(embyItemTypes(query)) == 0 {
+		return nil, nil
+	}
+
+	parentIDs := []string{""}
+	if len(query.Libraries) > 0 {
+		var err error
+		parentIDs, err = e.findLibraryIDs(query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var results []models.SearchResult
+	for _, parentID := range parentIDs {
+		libraryResults, err := e.searchLibrary(query, parentID)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, libraryResults...)
+	}
+
+	return results, nil
+}
+
+// findLibraryIDs 返回名称符合搜索条件的媒体库ID
+func (e *EmbyAdapter) findLibraryIDs(query models.SearchQuery) ([]string, error) {
+	libraries, err := e.libraries()
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, library := range libraries {
+		if query.MatchesLibrary(library.Name) {
+			ids = append(ids, library.ID)
+		}
+	}
+	return ids, nil
+}
+
+// searchLibrary 在指定媒体库中搜索，parentID 为空时搜索所有媒体库
+func (e *EmbyAdapter) searchLibrary(query models.SearchQuery, parentID string) ([]models.SearchResult, error) {
+	data, err := e.client.SearchItems(query, parentID, "", embySearchLimit)
 	if err != nil {
 		return nil, err
 	}
 
 	var searchResponse struct {
 		Items []struct {
-			ID             string   `json:"Id"`
-			Name           string   `json:"Name"`
-			Type           string   `json:"Type"`
-			IsFolder       bool     `json:"IsFolder"`
-			Size           int64    `json:"Size"`
-			DateCreated    string   `json:"DateCreated"`
-			ParentId       string   `json:"ParentId"`
-			Path           string   `json:"Path"`
-			ProductionYear int      `json:"ProductionYear"`
-			PremiereDate   string   `json:"PremiereDate"`
-			Overview       string   `json:"Overview"`
-			Genres         []string `json:"Genres"`
-			MediaType      string   `json:"MediaType"`
-			RunTimeTicks   int64    `json:"RunTimeTicks"`
-			ProviderIds    struct {
-				Tmdb string `json:"Tmdb"`
-				Imdb string `json:"Imdb"`
-				Tvdb string `json:"Tvdb"`
-			} `json:"ProviderIds"`
+			ID             string            `json:"Id"`
+			Name           string            `json:"Name"`
+			Type           string            `json:"Type"`
+			IsFolder       bool              `json:"IsFolder"`
+			Size           int64             `json:"Size"`
+			DateCreated    string            `json:"DateCreated"`
+			ParentId       string            `json:"ParentId"`
+			Path           string            `json:"Path"`
+			ProductionYear int               `json:"ProductionYear"`
+			PremiereDate   string            `json:"PremiereDate"`
+			Overview       string            `json:"Overview"`
+			Genres         []string          `json:"Genres"`
+			MediaType      string            `json:"MediaType"`
+			RunTimeTicks   int64             `json:"RunTimeTicks"`
+			ProviderIds    map[string]string `json:"ProviderIds"`
+
+			// 单集所属的剧集、季号和集号
+			SeriesId          string `json:"SeriesId"`
+			SeriesName        string `json:"SeriesName"`
+			ParentIndexNumber int    `json:"ParentIndexNumber"`
+			IndexNumber       int    `json:"IndexNumber"`
 		} `json:"Items"`
 	}
 
@@ -247,9 +368,9 @@ func (e *EmbyAdapter) Search(query string) ([]models.SearchResult, error) {
 		return nil, fmt.Errorf("error unmarshaling search results: %w", err)
 	}
 
-	// 转换Emby搜索结果到通用搜索结果
-	results := make([]models.SearchResult, len(searchResponse.Items))
-	for i, item := range searchResponse.Items {
+	// 转换Emby搜索结果到通用搜索结果，并过滤 Emby 无法直接处理的条件
+	var results []models.SearchResult
+	for _, item := range searchResponse.Items {
 		var addedAt int64 = 0
 		// 解析ISO 8601时间格式
 		if item.DateCreated != "" {
@@ -259,20 +380,13 @@ func (e *EmbyAdapter) Search(query string) ([]models.SearchResult, error) {
 			}
 		}
 
-		// 从路径中提取库信息 - 实际中需要获取库名称
-		library := item.ParentId // 实际中需要获取库名称
-		if library == "" {
-			library = "Unknown Library"
-		}
-
-		results[i] = models.SearchResult{
+		result := models.SearchResult{
 			ID:             item.ID,
 			Title:          item.Name,
 			Author:         "", // Emby通常没有作者字段，除非是audiobook类型
 			Size:           item.Size,
 			AddedAt:        addedAt,
 			LibraryID:      item.ParentId,
-			Library:        library,
 			Type:           strings.ToLower(item.Type),
 			Path:           item.Path,
 			RelPath:        item.Path,
@@ -283,31 +397,284 @@ func (e *EmbyAdapter) Search(query string) ([]models.SearchResult, error) {
 			PremiereDate:   item.PremiereDate,
 			RunTime:        item.RunTimeTicks,
 			MediaType:      item.MediaType,
+			ProviderIDs:    embyProviderIDs(item.ProviderIds),
+			SeriesID:       item.SeriesId,
+			SeriesName:     item.SeriesName,
+			SeasonNumber:   item.ParentIndexNumber,
+			EpisodeNumber:  item.IndexNumber,
 		}
+		if !query.Matches(&result) {
+			continue
+		}
+		// 在指定媒体库中搜索时直接使用该媒体库
+		if parentID != "" {
+			result.LibraryID = parentID
+		}
+		e.resolveLibrary(&result)
+		results = append(results, result)
 	}
 
 	return results, nil
 }
 
-// GetListeningStats 实现 MediaServer 接口
-func (e *EmbyAdapter) GetListeningStats() (map[string]interface{}, error) {
-	// 获取当前用户信息以获取用户ID
-	currentUser, err := e.GetCurrentUser()
+// embyItemDetails Emby 项目详情中的字段
+type embyItemDetails struct {
+	ID             string            `json:"Id"`
+	Name           string            `json:"Name"`
+	Type           string            `json:"Type"`
+	SeriesName     string            `json:"SeriesName"`
+	Size           int64             `json:"Size"`
+	DateCreated    string            `json:"DateCreated"`
+	ParentId       string            `json:"ParentId"`
+	Path           string            `json:"Path"`
+	ProductionYear int               `json:"ProductionYear"`
+	PremiereDate   string            `json:"PremiereDate"`
+	Overview       string            `json:"Overview"`
+	Genres         []string          `json:"Genres"`
+	MediaType      string            `json:"MediaType"`
+	RunTimeTicks   int64             `json:"RunTimeTicks"`
+	AlbumArtist    string            `json:"AlbumArtist"`
+	ProviderIds    map[string]string `json:"ProviderIds"`
+
+	// 单集所属的剧集、季号和集号
+	SeriesId          string `json:"SeriesId"`
+	ParentIndexNumber int    `json:"ParentIndexNumber"`
+	IndexNumber       int    `json:"IndexNumber"`
+}
+
+// toSearchResult 将 Emby 项目详情转换为通用搜索结果
+func (item *embyItemDetails) toSearchResult() *models.SearchResult {
+	title := item.Name
+	if item.SeriesName != "" {
+		title = fmt.Sprintf("%s - %s", item.SeriesName, item.Name)
+	}
+
+	var addedAt int64
+	if t := parseEmbyTime(item.DateCreated); !t.IsZero() {
+		addedAt = t.UnixMilli()
+	}
+
+	return &models.SearchResult{
+		ID:             item.ID,
+		Title:          title,
+		Author:         item.AlbumArtist,
+		Size:           item.Size,
+		AddedAt:        addedAt,
+		LibraryID:      item.ParentId,
+		Type:           strings.ToLower(item.Type),
+		Path:           item.Path,
+		RelPath:        item.Path,
+		Overview:       item.Overview,
+		Genres:         item.Genres,
+		Year:           item.ProductionYear,
+		ProductionYear: item.ProductionYear,
+		PremiereDate:   item.PremiereDate,
+		RunTime:        item.RunTimeTicks,
+		MediaType:      item.MediaType,
+		Duration:       float64(item.RunTimeTicks) / embyTicksPerSecond,
+		ProviderIDs:    embyProviderIDs(item.ProviderIds),
+		SeriesID:       item.SeriesId,
+		SeriesName:     item.SeriesName,
+		SeasonNumber:   item.ParentIndexNumber,
+		EpisodeNumber:  item.IndexNumber,
+	}
+}
+
+// embyProviderIDs 将 Emby 的 ProviderIds 转换为小写键名，忽略空值
+func embyProviderIDs(providerIDs map[string]string) map[string]string {
+	if len(providerIDs) == 0 {
+		return nil
+	}
+	ids := make(map[string]string, len(providerIDs))
+	for provider, id := range providerIDs {
+		if id != "" {
+			ids[strings.ToLower(provider)] = id
+		}
+	}
+	return ids
+}
+
+// GetItem 实现 MediaServer 接口
+func (e *EmbyAdapter) GetItem(itemID string) (*models.SearchResult, error) {
+	userID, err := e.getUserID()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := e.client.GetItem(userID, itemID)
 	if err != nil {
 		return nil, err
 	}
 
-	// 获取用户的媒体播放进度
-	data, err := e.client.GetUserData(currentUser.ID)
+	var item embyItemDetails
+	err = json.Unmarshal(data, &item)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling item: %w", err)
+	}
+
+	result := item.toSearchResult()
+	e.resolveLibrary(result)
+	return result, nil
+}
+
+// ListItems 实现 MediaServer 接口
+func (e *EmbyAdapter) ListItems(libraryID string, query models.ItemQuery) (*models.ItemPage, error) {
+	userID, err := e.getUserID()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := e.client.GetLibraryItems(userID, libraryID, query)
 	if err != nil {
 		return nil, err
 	}
 
-	var stats map[string]interface{}
-	err = json.Unmarshal(data, &stats)
+	var response struct {
+		Items            []embyItemDetails `json:"Items"`
+		TotalRecordCount int               `json:"TotalRecordCount"`
+	}
+
+	err = json.Unmarshal(data, &response)
 	if err != nil {
-		return nil, fmt.Errorf("error unmarshaling listening stats: %w", err)
+		return nil, fmt.Errorf("error unmarshaling library items: %w", err)
+	}
+
+	page := &models.ItemPage{
+		Items: make([]models.SearchResult, len(response.Items)),
+		Total: response.TotalRecordCount,
+	}
+	for i := range response.Items {
+		result := response.Items[i].toSearchResult()
+		result.LibraryID = libraryID
+		e.resolveLibrary(result)
+		page.Items[i] = *result
 	}
 
-	return stats, nil
+	return page, nil
+}
+
+// embyTicksPerMillisecond Emby 时间刻度（100 纳秒）与毫秒的换算
+const embyTicksPerMillisecond = 10000
+
+// GetSessions 实现 MediaServer 接口
+func (e *EmbyAdapter) GetSessions() ([]models.PlaybackSession, error) {
+	data, err := e.client.GetSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	var embySessions []struct {
+		ID                 string `json:"Id"`
+		UserID             string `json:"UserId"`
+		UserName           string `json:"UserName"`
+		Client             string `json:"Client"`
+		DeviceName         string `json:"DeviceName"`
+		ApplicationVersion string `json:"ApplicationVersion"`
+		NowPlayingItem     *struct {
+			ID           string `json:"Id"`
+			Name         string `json:"Name"`
+			SeriesName   string `json:"SeriesName"`
+			Type         string `json:"Type"`
+			RunTimeTicks int64  `json:"RunTimeTicks"`
+			Bitrate      int64  `json:"Bitrate"`
+		} `json:"NowPlayingItem"`
+		PlayState struct {
+			PositionTicks int64  `json:"PositionTicks"`
+			IsPaused      bool   `json:"IsPaused"`
+			PlayMethod    string `json:"PlayMethod"`
+		} `json:"PlayState"`
+		TranscodingInfo *struct {
+			AudioCodec               string   `json:"AudioCodec"`
+			VideoCodec               string   `json:"VideoCodec"`
+			Container                string   `json:"Container"`
+			Bitrate                  int64    `json:"Bitrate"`
+			IsVideoDirect            bool     `json:"IsVideoDirect"`
+			VideoDecoderIsHardware   bool     `json:"VideoDecoderIsHardware"`
+			VideoDecoderHwAccel      string   `json:"VideoDecoderHwAccel"`
+			VideoEncoderIsHardware   bool     `json:"VideoEncoderIsHardware"`
+			VideoEncoderHwAccel      string   `json:"VideoEncoderHwAccel"`
+			HardwareAccelerationType string   `json:"HardwareAccelerationType"`
+			TranscodeReasons         []string `json:"TranscodeReasons"`
+		} `json:"TranscodingInfo"`
+	}
+
+	err = json.Unmarshal(data, &embySessions)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling sessions: %w", err)
+	}
+
+	// 只保留正在播放内容的会话
+	sessions := make([]models.PlaybackSession, 0, len(embySessions))
+	for _, s := range embySessions {
+		if s.NowPlayingItem == nil {
+			continue
+		}
+
+		title := s.NowPlayingItem.Name
+		if s.NowPlayingItem.SeriesName != "" {
+			title = fmt.Sprintf("%s - %s", s.NowPlayingItem.SeriesName, s.NowPlayingItem.Name)
+		}
+
+		playMethod := s.PlayState.PlayMethod
+		if playMethod == "" {
+			playMethod = models.PlayMethodDirectPlay
+		}
+
+		session := models.PlaybackSession{
+			ID:         s.ID,
+			UserID:     s.UserID,
+			UserName:   s.UserName,
+			DeviceName: s.DeviceName,
+			Client:     s.Client,
+			ItemID:     s.NowPlayingItem.ID,
+			ItemTitle:  title,
+			ItemType:   strings.ToLower(s.NowPlayingItem.Type),
+			Position:   s.PlayState.PositionTicks / embyTicksPerMillisecond,
+			Duration:   s.NowPlayingItem.RunTimeTicks / embyTicksPerMillisecond,
+			IsPaused:   s.PlayState.IsPaused,
+			PlayMethod: playMethod,
+			Bitrate:    s.NowPlayingItem.Bitrate,
+		}
+
+		if s.TranscodingInfo != nil {
+			// 优先使用编码器的硬件加速方式，其次是解码器
+			hwAccel := s.TranscodingInfo.HardwareAccelerationType
+			switch {
+			case s.TranscodingInfo.VideoEncoderIsHardware:
+				hwAccel = s.TranscodingInfo.VideoEncoderHwAccel
+			case s.TranscodingInfo.VideoDecoderIsHardware:
+				hwAccel = s.TranscodingInfo.VideoDecoderHwAccel
+			}
+			if strings.EqualFold(hwAccel, "none") {
+				hwAccel = ""
+			}
+
+			session.Transcoding = &models.TranscodingInfo{
+				VideoCodec:           s.TranscodingInfo.VideoCodec,
+				AudioCodec:           s.TranscodingInfo.AudioCodec,
+				Container:            s.TranscodingInfo.Container,
+				Bitrate:              s.TranscodingInfo.Bitrate,
+				HardwareAcceleration: hwAccel,
+				Reasons:              s.TranscodingInfo.TranscodeReasons,
+			}
+			// 转码时以转码后的码率为准
+			if s.TranscodingInfo.Bitrate > 0 {
+				session.Bitrate = s.TranscodingInfo.Bitrate
+			}
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// StopSession 实现 SessionController 接口
+func (e *EmbyAdapter) StopSession(sessionID string) error {
+	return e.client.StopSession(sessionID)
+}
+
+// SendSessionMessage 实现 SessionMessenger 接口
+func (e *EmbyAdapter) SendSessionMessage(sessionID, text string) error {
+	return e.client.SendSessionMessage(sessionID, "MediaManager", text, 10000)
 }
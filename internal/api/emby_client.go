@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -15,7 +17,10 @@ import (
 type EmbyClient struct {
 	baseURL    string
 	apiKey     string
+	user       string
 	httpClient *http.Client
+	// streamClient 读取文件数据流使用的客户端，没有整体超时，避免大文件在发送途中被中断
+	streamClient *http.Client
 }
 
 // NewEmbyClient creates a new Emby API client
@@ -30,13 +35,24 @@ func NewEmbyClient(config *config.Config) *EmbyClient {
 		Timeout: 30 * time.Second,
 	}
 
+	// 下载文件的时间取决于文件大小，只限制等待响应头的时间
+	streamTransport := http.DefaultTransport.(*http.Transport).Clone()
+	streamTransport.ResponseHeaderTimeout = 30 * time.Second
+
 	return &EmbyClient{
-		baseURL:    baseURL,
-		apiKey:     config.EmbyToken,
-		httpClient: client,
+		baseURL:      baseURL,
+		apiKey:       config.EmbyToken,
+		user:         config.EmbyUser,
+		httpClient:   client,
+		streamClient: &http.Client{Transport: streamTransport},
 	}
 }
 
+// ConfiguredUser 返回配置中关联的 Emby 用户名或ID
+func (c *EmbyClient) ConfiguredUser() string {
+	return c.user
+}
+
 // doRequest performs an HTTP request to the Emby API
 func (c *EmbyClient) doRequest(method, path string, body interface{}) ([]byte, error) {
 	var reqBody io.Reader
@@ -96,6 +112,19 @@ func (c *EmbyClient) GetMediaFolders() ([]byte, error) {
 	return c.doRequest("GET", "/Library/MediaFolders", nil)
 }
 
+// GetVirtualFolders 获取媒体库及其包含的文件夹路径，需要管理员权限
+func (c *EmbyClient) GetVirtualFolders() ([]byte, error) {
+	return c.doRequest("GET", "/Library/VirtualFolders", nil)
+}
+
+// GetItemAncestors 获取项目的所有上级项目，从直接父项目到根目录
+func (c *EmbyClient) GetItemAncestors(userID, itemID string) ([]byte, error) {
+	params := url.Values{}
+	params.Add("UserId", userID)
+
+	return c.doRequest("GET", fmt.Sprintf("/Items/%s/Ancestors?%s", itemID, params.Encode()), nil)
+}
+
 // GetItems 获取媒体项目
 func (c *EmbyClient) GetItems(parentID string, userID string, itemTypes []string) ([]byte, error) {
 	params := url.Values{}
@@ -121,12 +150,48 @@ func (c *EmbyClient) GetItems(parentID string, userID string, itemTypes []string
 	return c.doRequest("GET", path, nil)
 }
 
-// SearchItems 搜索媒体项目
-func (c *EmbyClient) SearchItems(searchTerm string, userID string, limit int) ([]byte, error) {
+// embySearchItemTypes 默认搜索的项目类型
+const embySearchItemTypes = "Movie,Series,MusicAlbum,MusicArtist,Playlist,Audio,Book,Folder,Photo,PhotoAlbum"
+
+// embyTypesBySearchType 规范化搜索类型对应的 Emby 项目类型
+var embyTypesBySearchType = map[string][]string{
+	models.SearchTypeMovie:     {"Movie"},
+	models.SearchTypeSeries:    {"Series"},
+	models.SearchTypeEpisode:   {"Episode"},
+	models.SearchTypeMusic:     {"MusicAlbum", "MusicArtist", "Audio"},
+	models.SearchTypeBook:      {"Book", "AudioBook"},
+	models.SearchTypeAudiobook: {"AudioBook"},
+}
+
+// embyItemTypes 将搜索条件中的类型转换为 Emby 项目类型，未指定类型时返回 nil
+func embyItemTypes(query models.SearchQuery) []string {
+	var itemTypes []string
+	seen := make(map[string]bool)
+	for _, t := range query.Types {
+		for _, itemType := range embyTypesBySearchType[t] {
+			if !seen[itemType] {
+				seen[itemType] = true
+				itemTypes = append(itemTypes, itemType)
+			}
+		}
+	}
+	return itemTypes
+}
+
+// SearchItems 按结构化条件搜索媒体项目，parentID 不为空时只搜索该媒体库
+func (c *EmbyClient) SearchItems(query models.SearchQuery, parentID string, userID string, limit int) ([]byte, error) {
 	params := url.Values{}
-	params.Add("SearchTerm", searchTerm)
-	// 使用更全面的搜索端点
-	params.Add("IncludeItemTypes", "Movie,Series,MusicAlbum,MusicArtist,Playlist,Audio,Book,Folder,Photo,PhotoAlbum")
+	if query.Text != "" {
+		params.Add("SearchTerm", query.Text)
+	}
+	if itemTypes := embyItemTypes(query); len(itemTypes) > 0 {
+		params.Add("IncludeItemTypes", strings.Join(itemTypes, ","))
+	} else {
+		// 使用更全面的搜索端点
+		params.Add("IncludeItemTypes", embySearchItemTypes)
+		// 排除剧集类型，确保搜索结果中不包含剧集类型的媒体
+		params.Add("ExcludeItemTypes", "Episode")
+	}
 	// 添加必要的字段参数以获取完整媒体信息
 	params.Add("Fields", "Path,DateCreated,Size,Overview,ProviderIds,Genres,Studios,Taglines,LocalTrailerCount,OfficialRating,CumulativeRunTimeTicks,ItemCounts,DisplayPreferencesId,ChildCount,RecursiveChildCount,ProductionLocations,CriticRating,ShortOverview,MediaSourceCount,PrimaryImageAspectRatio")
 	// 包括所有类型的媒体
@@ -134,10 +199,37 @@ func (c *EmbyClient) SearchItems(searchTerm string, userID string, limit int) ([
 	params.Add("IncludeGenres", "true")
 	params.Add("IncludeStudios", "true")
 	params.Add("IncludeArtists", "true")
-	// 排除剧集类型，确保搜索结果中不包含剧集类型的媒体
-	params.Add("ExcludeItemTypes", "Episode")
 	params.Add("Recursive", "true")
 	params.Add("EnableTotalRecordCount", "false")
+
+	// 年份范围转换为年份列表
+	if query.YearMin > 0 || query.YearMax > 0 {
+		from, to := query.YearMin, query.YearMax
+		if from == 0 {
+			from = 1900
+		}
+		if to == 0 {
+			to = time.Now().Year() + 1
+		}
+		years := make([]string, 0, to-from+1)
+		for year := from; year <= to; year++ {
+			years = append(years, fmt.Sprintf("%d", year))
+		}
+		params.Add("Years", strings.Join(years, ","))
+	}
+
+	// 分类之间为“或”关系，同时带上常见缩写对应的完整名称
+	if len(query.Genres) > 0 {
+		var genres []string
+		for _, genre := range query.Genres {
+			genres = append(genres, models.GenreNames(genre)...)
+		}
+		params.Add("Genres", strings.Join(genres, "|"))
+	}
+
+	if parentID != "" {
+		params.Add("ParentId", parentID)
+	}
 	if userID != "" {
 		params.Add("UserId", userID)
 	}
@@ -183,12 +275,354 @@ func (c *EmbyClient) GetLibraryItemsCount(libraryID string) (int, error) {
 	return response.TotalRecordCount, nil
 }
 
-// GetUserData 获取用户的媒体播放进度
-func (c *EmbyClient) GetUserData(userID string) ([]byte, error) {
-	return c.doRequest("GET", fmt.Sprintf("/Users/%s/Items", userID), nil)
-}
-
 // GetResumeItems 获取用户继续播放的项目
 func (c *EmbyClient) GetResumeItems(userID string) ([]byte, error) {
 	return c.doRequest("GET", fmt.Sprintf("/Users/%s/Items/Resume", userID), nil)
 }
+
+// embyBrowseItemTypes 浏览媒体库时显示的顶层项目类型
+const embyBrowseItemTypes = "Movie,Series,MusicAlbum,Book,AudioBook,Video,MusicVideo"
+
+// GetLibraryItems 分页获取媒体库中的顶层项目
+func (c *EmbyClient) GetLibraryItems(userID, parentID string, query models.ItemQuery) ([]byte, error) {
+	params := url.Values{}
+	params.Add("ParentId", parentID)
+	params.Add("Recursive", "true")
+	if query.Filter == models.ItemFilterInProgress {
+		// 剧集和音轨才有播放进度
+		params.Add("IncludeItemTypes", embyBrowseItemTypes+",Episode,Audio")
+	} else {
+		params.Add("IncludeItemTypes", embyBrowseItemTypes)
+	}
+	params.Add("Fields", "DateCreated,Genres,ProductionYear,Overview,Path,ParentId,RunTimeTicks,ProviderIds")
+	params.Add("EnableUserData", "true")
+	params.Add("EnableTotalRecordCount", "true")
+	params.Add("StartIndex", fmt.Sprintf("%d", query.Page*query.PageSize))
+	params.Add("Limit", fmt.Sprintf("%d", query.PageSize))
+
+	switch query.SortBy {
+	case models.ItemSortName:
+		params.Add("SortBy", "SortName")
+	case models.ItemSortYear:
+		params.Add("SortBy", "ProductionYear,SortName")
+	default:
+		params.Add("SortBy", "DateCreated,SortName")
+	}
+	if query.Descending {
+		params.Add("SortOrder", "Descending")
+	} else {
+		params.Add("SortOrder", "Ascending")
+	}
+
+	switch query.Filter {
+	case models.ItemFilterGenre:
+		params.Add("Genres", query.Genre)
+	case models.ItemFilterUnplayed:
+		params.Add("Filters", "IsUnplayed")
+	case models.ItemFilterInProgress:
+		params.Add("Filters", "IsResumable")
+	}
+
+	return c.doRequest("GET", fmt.Sprintf("/Users/%s/Items?%s", userID, params.Encode()), nil)
+}
+
+// GetItem 获取用户视角下的单个项目详情
+func (c *EmbyClient) GetItem(userID, itemID string) ([]byte, error) {
+	return c.doRequest("GET", fmt.Sprintf("/Users/%s/Items/%s", userID, itemID), nil)
+}
+
+// SearchPersons 按名称搜索人物（演员、导演、作者等）
+func (c *EmbyClient) SearchPersons(userID, term string, limit int) ([]byte, error) {
+	params := url.Values{}
+	params.Add("SearchTerm", term)
+	params.Add("UserId", userID)
+	params.Add("Recursive", "true")
+	params.Add("EnableImages", "false")
+	params.Add("Limit", fmt.Sprintf("%d", limit))
+
+	return c.doRequest("GET", fmt.Sprintf("/Persons?%s", params.Encode()), nil)
+}
+
+// GetRelatedItems 获取与人物或标签关联的项目，filterKey 为 PersonIds 或 Tags
+func (c *EmbyClient) GetRelatedItems(userID, filterKey, filterValue string, limit int) ([]byte, error) {
+	params := url.Values{}
+	params.Add(filterKey, filterValue)
+	params.Add("Recursive", "true")
+	params.Add("IncludeItemTypes", embyBrowseItemTypes)
+	params.Add("Fields", "DateCreated,Genres,ProductionYear,Overview,Path,ParentId,RunTimeTicks,ProviderIds")
+	params.Add("SortBy", "ProductionYear,SortName")
+	params.Add("SortOrder", "Descending")
+	params.Add("Limit", fmt.Sprintf("%d", limit))
+
+	return c.doRequest("GET", fmt.Sprintf("/Users/%s/Items?%s", userID, params.Encode()), nil)
+}
+
+// GetSeasons 获取剧集的所有季，包含用户的播放状态
+func (c *EmbyClient) GetSeasons(userID, seriesID string) ([]byte, error) {
+	params := url.Values{}
+	params.Add("UserId", userID)
+	params.Add("Fields", "ChildCount")
+	params.Add("EnableUserData", "true")
+	params.Add("EnableImages", "false")
+
+	return c.doRequest("GET", fmt.Sprintf("/Shows/%s/Seasons?%s", seriesID, params.Encode()), nil)
+}
+
+// GetEpisodes 获取剧集的单集，seasonID 不为空时只返回该季，包含用户的播放状态
+func (c *EmbyClient) GetEpisodes(userID, seriesID, seasonID string) ([]byte, error) {
+	params := url.Values{}
+	params.Add("UserId", userID)
+	if seasonID != "" {
+		params.Add("SeasonId", seasonID)
+	}
+	params.Add("Fields", "Overview")
+	params.Add("EnableUserData", "true")
+	params.Add("EnableImages", "false")
+
+	return c.doRequest("GET", fmt.Sprintf("/Shows/%s/Episodes?%s", seriesID, params.Encode()), nil)
+}
+
+// embyCollectionItemFields 列出合集和播放列表中的项目时需要的字段
+const embyCollectionItemFields = "ProductionYear,RunTimeTicks,ParentId,ProviderIds"
+
+// GetCollectionsByType 获取用户可见的合集（BoxSet）或播放列表（Playlist）
+func (c *EmbyClient) GetCollectionsByType(userID, itemType string) ([]byte, error) {
+	params := url.Values{}
+	params.Add("IncludeItemTypes", itemType)
+	params.Add("Recursive", "true")
+	params.Add("Fields", "ChildCount")
+	params.Add("SortBy", "SortName")
+	params.Add("EnableImages", "false")
+
+	return c.doRequest("GET", fmt.Sprintf("/Users/%s/Items?%s", userID, params.Encode()), nil)
+}
+
+// GetBoxSetItems 获取合集中的项目
+func (c *EmbyClient) GetBoxSetItems(userID, boxSetID string) ([]byte, error) {
+	params := url.Values{}
+	params.Add("ParentId", boxSetID)
+	params.Add("Fields", embyCollectionItemFields)
+	params.Add("SortBy", "ProductionYear,SortName")
+	params.Add("EnableImages", "false")
+
+	return c.doRequest("GET", fmt.Sprintf("/Users/%s/Items?%s", userID, params.Encode()), nil)
+}
+
+// GetPlaylistItems 获取播放列表中的项目，按播放顺序排列
+func (c *EmbyClient) GetPlaylistItems(userID, playlistID string) ([]byte, error) {
+	params := url.Values{}
+	params.Add("UserId", userID)
+	params.Add("Fields", embyCollectionItemFields)
+	params.Add("EnableImages", "false")
+
+	return c.doRequest("GET", fmt.Sprintf("/Playlists/%s/Items?%s", playlistID, params.Encode()), nil)
+}
+
+// CreateBoxSet 创建包含指定项目的合集
+func (c *EmbyClient) CreateBoxSet(name string, itemIDs []string) ([]byte, error) {
+	params := url.Values{}
+	params.Add("Name", name)
+	params.Add("Ids", strings.Join(itemIDs, ","))
+
+	return c.doRequest("POST", "/Collections?"+params.Encode(), nil)
+}
+
+// AddToBoxSet 将项目加入合集
+func (c *EmbyClient) AddToBoxSet(boxSetID string, itemIDs []string) error {
+	params := url.Values{}
+	params.Add("Ids", strings.Join(itemIDs, ","))
+
+	_, err := c.doRequest("POST", fmt.Sprintf("/Collections/%s/Items?%s", boxSetID, params.Encode()), nil)
+	return err
+}
+
+// CreatePlaylist 为用户创建包含指定项目的播放列表
+func (c *EmbyClient) CreatePlaylist(userID, name string, itemIDs []string) ([]byte, error) {
+	params := url.Values{}
+	params.Add("Name", name)
+	params.Add("Ids", strings.Join(itemIDs, ","))
+	params.Add("UserId", userID)
+
+	return c.doRequest("POST", "/Playlists?"+params.Encode(), nil)
+}
+
+// AddToPlaylist 将项目加入播放列表
+func (c *EmbyClient) AddToPlaylist(userID, playlistID string, itemIDs []string) error {
+	params := url.Values{}
+	params.Add("Ids", strings.Join(itemIDs, ","))
+	params.Add("UserId", userID)
+
+	_, err := c.doRequest("POST", fmt.Sprintf("/Playlists/%s/Items?%s", playlistID, params.Encode()), nil)
+	return err
+}
+
+// RefreshLibrary 扫描所有媒体库，对应计划任务中的“扫描媒体库”
+func (c *EmbyClient) RefreshLibrary() error {
+	_, err := c.doRequest("POST", "/Library/Refresh", nil)
+	return err
+}
+
+// RefreshItemMetadata 刷新项目及其子项目的元数据和图片，不覆盖已有内容
+func (c *EmbyClient) RefreshItemMetadata(itemID string) error {
+	params := url.Values{}
+	params.Add("Recursive", "true")
+	params.Add("MetadataRefreshMode", "FullRefresh")
+	params.Add("ImageRefreshMode", "FullRefresh")
+	params.Add("ReplaceAllMetadata", "false")
+	params.Add("ReplaceAllImages", "false")
+	_, err := c.doRequest("POST", fmt.Sprintf("/Items/%s/Refresh?%s", itemID, params.Encode()), nil)
+	return err
+}
+
+// GetScheduledTasks 获取计划任务列表
+func (c *EmbyClient) GetScheduledTasks() ([]byte, error) {
+	return c.doRequest("GET", "/ScheduledTasks", nil)
+}
+
+// GetSessions 获取当前活动的会话
+func (c *EmbyClient) GetSessions() ([]byte, error) {
+	params := url.Values{}
+	// 只返回最近活动的会话
+	params.Add("ActiveWithinSeconds", "960")
+	return c.doRequest("GET", "/Sessions?"+params.Encode(), nil)
+}
+
+// StopSession 停止指定会话的播放
+func (c *EmbyClient) StopSession(sessionID string) error {
+	_, err := c.doRequest("POST", fmt.Sprintf("/Sessions/%s/Playing/Stop", sessionID), nil)
+	return err
+}
+
+// SendSessionMessage 向指定会话的客户端发送消息
+func (c *EmbyClient) SendSessionMessage(sessionID, header, text string, timeoutMs int) error {
+	body := map[string]interface{}{
+		"Header": header,
+		"Text":   text,
+	}
+	if timeoutMs > 0 {
+		body["TimeoutMs"] = timeoutMs
+	}
+	_, err := c.doRequest("POST", fmt.Sprintf("/Sessions/%s/Message", sessionID), body)
+	return err
+}
+
+// GetPlayedItems 获取用户已播放的项目，按最后播放时间倒序排列
+func (c *EmbyClient) GetPlayedItems(userID string, limit int) ([]byte, error) {
+	params := url.Values{}
+	params.Add("Filters", "IsPlayed")
+	params.Add("Recursive", "true")
+	params.Add("IncludeItemTypes", "Movie,Episode,Audio,AudioBook")
+	params.Add("Fields", "Genres,RunTimeTicks")
+	params.Add("SortBy", "DatePlayed")
+	params.Add("SortOrder", "Descending")
+	params.Add("EnableUserData", "true")
+	params.Add("EnableTotalRecordCount", "true")
+	if limit > 0 {
+		params.Add("Limit", fmt.Sprintf("%d", limit))
+	}
+
+	return c.doRequest("GET", fmt.Sprintf("/Users/%s/Items?%s", userID, params.Encode()), nil)
+}
+
+// GetAudioProgressItems 获取用户有播放进度或已播放的音频项目，filter 为 IsResumable 或 IsPlayed
+func (c *EmbyClient) GetAudioProgressItems(userID, filter string, limit int) ([]byte, error) {
+	params := url.Values{}
+	params.Add("Filters", filter)
+	params.Add("Recursive", "true")
+	params.Add("IncludeItemTypes", "Audio,AudioBook")
+	params.Add("Fields", "ProviderIds,RunTimeTicks,ParentId,Path")
+	params.Add("SortBy", "DatePlayed")
+	params.Add("SortOrder", "Descending")
+	params.Add("EnableUserData", "true")
+	if limit > 0 {
+		params.Add("Limit", fmt.Sprintf("%d", limit))
+	}
+
+	return c.doRequest("GET", fmt.Sprintf("/Users/%s/Items?%s", userID, params.Encode()), nil)
+}
+
+// UpdateUserItemData 更新用户在项目上的播放位置和已播放状态
+func (c *EmbyClient) UpdateUserItemData(userID, itemID string, positionTicks int64, played bool) error {
+	body := map[string]interface{}{
+		"PlaybackPositionTicks": positionTicks,
+		"Played":                played,
+	}
+	_, err := c.doRequest("POST", fmt.Sprintf("/Users/%s/Items/%s/UserData", userID, itemID), body)
+	return err
+}
+
+// MarkPlayed 将项目标记为用户已播放
+func (c *EmbyClient) MarkPlayed(userID, itemID string) error {
+	_, err := c.doRequest("POST", fmt.Sprintf("/Users/%s/PlayedItems/%s", userID, itemID), nil)
+	return err
+}
+
+// MarkUnplayed 将项目标记为用户未播放
+func (c *EmbyClient) MarkUnplayed(userID, itemID string) error {
+	_, err := c.doRequest("DELETE", fmt.Sprintf("/Users/%s/PlayedItems/%s", userID, itemID), nil)
+	return err
+}
+
+// GetItemsByIDs 根据ID列表获取项目
+func (c *EmbyClient) GetItemsByIDs(userID string, ids []string, fields string) ([]byte, error) {
+	params := url.Values{}
+	params.Add("Ids", strings.Join(ids, ","))
+	if fields != "" {
+		params.Add("Fields", fields)
+	}
+
+	return c.doRequest("GET", fmt.Sprintf("/Users/%s/Items?%s", userID, params.Encode()), nil)
+}
+
+// SubmitCustomQuery 向 Playback Reporting 插件提交自定义查询
+func (c *EmbyClient) SubmitCustomQuery(query string) ([]byte, error) {
+	body := map[string]interface{}{
+		"CustomQueryString": query,
+		"ReplaceUserId":     false,
+	}
+	return c.doRequest("POST", "/user_usage_stats/submit_custom_query", body)
+}
+
+// GetMediaFiles 获取文件夹类项目（专辑、剧集、合集等）中所有包含媒体文件的子项目
+func (c *EmbyClient) GetMediaFiles(userID, parentID string) ([]byte, error) {
+	params := url.Values{}
+	params.Add("ParentId", parentID)
+	params.Add("Recursive", "true")
+	params.Add("IsFolder", "false")
+	params.Add("MediaTypes", "Audio,Video,Book")
+	params.Add("Fields", "Path,MediaSources,Chapters")
+	params.Add("SortBy", "ParentIndexNumber,IndexNumber,SortName")
+	params.Add("EnableImages", "false")
+
+	return c.doRequest("GET", fmt.Sprintf("/Users/%s/Items?%s", userID, params.Encode()), nil)
+}
+
+// OpenDownload 打开项目原始文件的数据流，end 大于 0 时只请求 [start, end) 字节范围，调用方负责关闭
+func (c *EmbyClient) OpenDownload(itemID string, start, end int64) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/Items/%s/Download", c.baseURL, itemID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("X-Emby-Token", c.apiKey)
+	if end > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end-1))
+	}
+
+	resp, err := c.streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+	if end > 0 && resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf("server does not support range requests")
+	}
+
+	return resp.Body, nil
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// embyCollectionTypes 合集类型对应的 Emby 项目类型
+var embyCollectionTypes = map[string]string{
+	models.CollectionKindCollection: "BoxSet",
+	models.CollectionKindPlaylist:   "Playlist",
+}
+
+// GetCollections 实现 CollectionManager 接口，返回所有合集（BoxSet）和播放列表
+func (e *EmbyAdapter) GetCollections() ([]models.Collection, error) {
+	userID, err := e.getUserID()
+	if err != nil {
+		return nil, err
+	}
+
+	var collections []models.Collection
+	for _, kind := range []string{models.CollectionKindCollection, models.CollectionKindPlaylist} {
+		data, err := e.client.GetCollectionsByType(userID, embyCollectionTypes[kind])
+		if err != nil {
+			return nil, err
+		}
+
+		var response struct {
+			Items []struct {
+				ID         string `json:"Id"`
+				Name       string `json:"Name"`
+				ChildCount int    `json:"ChildCount"`
+			} `json:"Items"`
+		}
+		if err := json.Unmarshal(data, &response); err != nil {
+			return nil, fmt.Errorf("error unmarshaling collections: %w", err)
+		}
+
+		for _, item := range response.Items {
+			collections = append(collections, models.Collection{
+				ID:        item.ID,
+				Name:      item.Name,
+				Kind:      kind,
+				ItemCount: item.ChildCount,
+			})
+		}
+	}
+
+	return collections, nil
+}
+
+// GetCollectionItems 实现 CollectionManager 接口
+func (e *EmbyAdapter) GetCollectionItems(kind, collectionID string) ([]models.SearchResult, error) {
+	userID, err := e.getUserID()
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if kind == models.CollectionKindPlaylist {
+		data, err = e.client.GetPlaylistItems(userID, collectionID)
+	} else {
+		data, err = e.client.GetBoxSetItems(userID, collectionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Items []embyItemDetails `json:"Items"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling collection items: %w", err)
+	}
+
+	results := make([]models.SearchResult, len(response.Items))
+	for i := range response.Items {
+		results[i] = *response.Items[i].toSearchResult()
+	}
+	return results, nil
+}
+
+// CreateCollection 实现 CollectionManager 接口，播放列表属于机器人关联的用户
+func (e *EmbyAdapter) CreateCollection(kind, name, itemID string) (*models.Collection, error) {
+	var data []byte
+	var err error
+	if kind == models.CollectionKindPlaylist {
+		userID, userErr := e.getUserID()
+		if userErr != nil {
+			return nil, userErr
+		}
+		data, err = e.client.CreatePlaylist(userID, name, []string{itemID})
+	} else {
+		data, err = e.client.CreateBoxSet(name, []string{itemID})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		ID string `json:"Id"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling created collection: %w", err)
+	}
+
+	return &models.Collection{ID: response.ID, Name: name, Kind: kind, ItemCount: 1}, nil
+}
+
+// AddToCollection 实现 CollectionManager 接口
+func (e *EmbyAdapter) AddToCollection(kind, collectionID, itemID string) error {
+	if kind == models.CollectionKindPlaylist {
+		userID, err := e.getUserID()
+		if err != nil {
+			return err
+		}
+		return e.client.AddToPlaylist(userID, collectionID, []string{itemID})
+	}
+	return e.client.AddToBoxSet(collectionID, []string{itemID})
+}
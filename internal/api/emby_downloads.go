@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// embyMediaFileItem 包含媒体文件的项目
+type embyMediaFileItem struct {
+	ID           string `json:"Id"`
+	Name         string `json:"Name"`
+	IsFolder     bool   `json:"IsFolder"`
+	Path         string `json:"Path"`
+	RunTimeTicks int64  `json:"RunTimeTicks"`
+	AlbumArtist  string `json:"AlbumArtist"`
+	SeriesName   string `json:"SeriesName"`
+	MediaSources []struct {
+		Path      string `json:"Path"`
+		Size      int64  `json:"Size"`
+		Container string `json:"Container"`
+	} `json:"MediaSources"`
+	Chapters []struct {
+		Name               string `json:"Name"`
+		StartPositionTicks int64  `json:"StartPositionTicks"`
+	} `json:"Chapters"`
+}
+
+// toDownloadFile 将项目的第一个媒体源转换为可下载文件，没有媒体源时返回 false
+func (item *embyMediaFileItem) toDownloadFile() (models.DownloadFile, bool) {
+	if len(item.MediaSources) == 0 {
+		return models.DownloadFile{}, false
+	}
+	source := item.MediaSources[0]
+
+	filePath := source.Path
+	if filePath == "" {
+		filePath = item.Path
+	}
+	name := path.Base(strings.ReplaceAll(filePath, "\\", "/"))
+	if filePath == "" || name == "." || name == "/" {
+		name = item.Name + "." + source.Container
+	}
+
+	artist := item.AlbumArtist
+	if artist == "" {
+		artist = item.SeriesName
+	}
+
+	file := models.DownloadFile{
+		ID:       item.ID,
+		Name:     name,
+		Size:     source.Size,
+		Duration: float64(item.RunTimeTicks) / embyTicksPerSecond,
+		Title:    item.Name,
+		Artist:   artist,
+	}
+	for i, chapter := range item.Chapters {
+		end := file.Duration
+		if i+1 < len(item.Chapters) {
+			end = float64(item.Chapters[i+1].StartPositionTicks) / embyTicksPerSecond
+		}
+		file.Chapters = append(file.Chapters, models.Chapter{
+			Title: chapter.Name,
+			Start: float64(chapter.StartPositionTicks) / embyTicksPerSecond,
+			End:   end,
+		})
+	}
+	return file, true
+}
+
+// GetDownloadFiles 实现 FileDownloader 接口，文件夹类项目返回其中所有媒体文件
+func (e *EmbyAdapter) GetDownloadFiles(itemID string) ([]models.DownloadFile, error) {
+	userID, err := e.getUserID()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := e.client.GetItem(userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	var item embyMediaFileItem
+	err = json.Unmarshal(data, &item)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling item: %w", err)
+	}
+
+	if !item.IsFolder {
+		if file, ok := item.toDownloadFile(); ok {
+			return []models.DownloadFile{file}, nil
+		}
+		return nil, nil
+	}
+
+	data, err = e.client.GetMediaFiles(userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Items []embyMediaFileItem `json:"Items"`
+	}
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling media files: %w", err)
+	}
+
+	var files []models.DownloadFile
+	for i := range response.Items {
+		if file, ok := response.Items[i].toDownloadFile(); ok {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// OpenFile 实现 FileDownloader 接口，Emby 中文件 ID 即为项目 ID
+func (e *EmbyAdapter) OpenFile(itemID, fileID string, start, end int64) (io.ReadCloser, error) {
+	return e.client.OpenDownload(fileID, start, end)
+}
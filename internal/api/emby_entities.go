@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// embyEntityLimit 人物搜索和关联项目的最大数量
+const embyEntityLimit = 50
+
+// searchPersons 按名称搜索人物
+func (e *EmbyAdapter) searchPersons(userID, text string) ([]embyItem, error) {
+	data, err := e.client.SearchPersons(userID, text, embyEntityLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var response embyItemsResponse
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling persons: %w", err)
+	}
+
+	return response.Items, nil
+}
+
+// SearchEntities 实现 EntitySearcher 接口，返回名称匹配的人物
+func (e *EmbyAdapter) SearchEntities(text string) ([]models.Entity, error) {
+	userID, err := e.getUserID()
+	if err != nil {
+		return nil, err
+	}
+
+	persons, err := e.searchPersons(userID, text)
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]models.Entity, len(persons))
+	for i, person := range persons {
+		entities[i] = models.Entity{ID: person.ID, Name: person.Name, Kind: models.EntityPerson}
+	}
+
+	return entities, nil
+}
+
+// GetEntityItems 实现 EntitySearcher 接口，Emby 只有人物和标签，没有书籍系列
+func (e *EmbyAdapter) GetEntityItems(kind, name string) ([]models.SearchResult, error) {
+	userID, err := e.getUserID()
+	if err != nil {
+		return nil, err
+	}
+
+	var filters [][2]string
+	switch {
+	case models.IsPersonKind(kind):
+		persons, err := e.searchPersons(userID, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, person := range persons {
+			if strings.EqualFold(person.Name, name) {
+				filters = append(filters, [2]string{"PersonIds", person.ID})
+			}
+		}
+	case kind == models.EntityTag:
+		filters = append(filters, [2]string{"Tags", name})
+	}
+
+	var results []models.SearchResult
+	seen := make(map[string]bool)
+	for _, filter := range filters {
+		data, err := e.client.GetRelatedItems(userID, filter[0], filter[1], embyEntityLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		var response struct {
+			Items []embyItemDetails `json:"Items"`
+		}
+		err = json.Unmarshal(data, &response)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshaling related items: %w", err)
+		}
+
+		for i := range response.Items {
+			if seen[response.Items[i].ID] {
+				continue
+			}
+			seen[response.Items[i].ID] = true
+			result := response.Items[i].toSearchResult()
+			e.resolveLibrary(result)
+			results = append(results, *result)
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,229 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// embyLibraryFolder 媒体库及其包含的文件夹路径
+type embyLibraryFolder struct {
+	ID        string
+	Name      string
+	Locations []string
+}
+
+// libraries 返回缓存的媒体库列表，缓存过期时重新获取
+func (e *EmbyAdapter) libraries() ([]embyLibraryFolder, error) {
+	e.librariesCacheMutex.RLock()
+	if time.Since(e.librariesCacheTime) < e.cacheExpiry && e.librariesCache != nil {
+		cached := e.librariesCache
+		e.librariesCacheMutex.RUnlock()
+		return cached, nil
+	}
+	e.librariesCacheMutex.RUnlock()
+
+	e.librariesCacheMutex.Lock()
+	defer e.librariesCacheMutex.Unlock()
+
+	// 双重检查，防止并发重复获取
+	if time.Since(e.librariesCacheTime) < e.cacheExpiry && e.librariesCache != nil {
+		return e.librariesCache, nil
+	}
+
+	libraries, err := e.fetchLibraryFolders()
+	if err != nil {
+		return nil, err
+	}
+	e.librariesCache = libraries
+	e.librariesCacheTime = time.Now()
+	e.parentLibraries = make(map[string]string)
+
+	return libraries, nil
+}
+
+// fetchLibraryFolders 获取媒体库及其文件夹路径，没有管理员权限时只获取媒体库名称
+func (e *EmbyAdapter) fetchLibraryFolders() ([]embyLibraryFolder, error) {
+	data, err := e.client.GetVirtualFolders()
+	if err == nil {
+		var virtualFolders []struct {
+			Name      string   `json:"Name"`
+			ItemID    string   `json:"ItemId"`
+			Locations []string `json:"Locations"`
+		}
+		err = json.Unmarshal(data, &virtualFolders)
+		if err == nil {
+			libraries := make([]embyLibraryFolder, len(virtualFolders))
+			for i, folder := range virtualFolders {
+				libraries[i] = embyLibraryFolder{ID: folder.ItemID, Name: folder.Name, Locations: folder.Locations}
+			}
+			return libraries, nil
+		}
+		err = fmt.Errorf("error unmarshaling virtual folders: %w", err)
+	}
+	log.Printf("获取 Emby 媒体库路径失败，只能通过上级项目确定媒体库: %v", err)
+
+	data, err = e.client.GetMediaFolders()
+	if err != nil {
+		return nil, err
+	}
+
+	var mediaFolders struct {
+		Items []struct {
+			Name string `json:"Name"`
+			ID   string `json:"Id"`
+		} `json:"Items"`
+	}
+	err = json.Unmarshal(data, &mediaFolders)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling media folders: %w", err)
+	}
+
+	libraries := make([]embyLibraryFolder, len(mediaFolders.Items))
+	for i, folder := range mediaFolders.Items {
+		libraries[i] = embyLibraryFolder{ID: folder.ID, Name: folder.Name}
+	}
+	return libraries, nil
+}
+
+// libraryByID 根据 ID 查找媒体库
+func libraryByID(libraries []embyLibraryFolder, id string) *embyLibraryFolder {
+	if id == "" {
+		return nil
+	}
+	for i := range libraries {
+		if libraries[i].ID == id {
+			return &libraries[i]
+		}
+	}
+	return nil
+}
+
+// libraryByPath 根据文件路径查找所属媒体库，媒体库文件夹互相嵌套时选择最长的匹配路径
+func libraryByPath(libraries []embyLibraryFolder, path string) *embyLibraryFolder {
+	path = normalizeLibraryPath(path)
+	if path == "" {
+		return nil
+	}
+
+	var best *embyLibraryFolder
+	bestLength := 0
+	for i := range libraries {
+		for _, location := range libraries[i].Locations {
+			location = normalizeLibraryPath(location)
+			if location == "" || len(location) <= bestLength {
+				continue
+			}
+			// 必须在路径分隔符处匹配，避免 /media/movies 匹配 /media/movies2
+			if path == location || strings.HasPrefix(path, location+"/") {
+				best = &libraries[i]
+				bestLength = len(location)
+			}
+		}
+	}
+	return best
+}
+
+// normalizeLibraryPath 统一路径分隔符并去掉末尾的分隔符，Windows 路径不区分大小写
+func normalizeLibraryPath(path string) string {
+	path = strings.TrimRight(strings.ReplaceAll(path, "\\", "/"), "/")
+	if len(path) >= 2 && path[1] == ':' {
+		path = strings.ToLower(path)
+	}
+	return path
+}
+
+// libraryFromAncestors 通过项目的上级项目确定所属媒体库
+func (e *EmbyAdapter) libraryFromAncestors(libraries []embyLibraryFolder, itemID string) (*embyLibraryFolder, error) {
+	userID, err := e.getUserID()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := e.client.GetItemAncestors(userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ancestors []struct {
+		ID   string `json:"Id"`
+		Path string `json:"Path"`
+	}
+	err = json.Unmarshal(data, &ancestors)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling ancestors: %w", err)
+	}
+
+	for _, ancestor := range ancestors {
+		if library := libraryByID(libraries, ancestor.ID); library != nil {
+			return library, nil
+		}
+		if library := libraryByPath(libraries, ancestor.Path); library != nil {
+			return library, nil
+		}
+	}
+	return nil, nil
+}
+
+// libraryFromParent 通过上级项目确定所属媒体库，同一父项目下的项目属于同一媒体库，因此按父项目缓存结果，
+// 避免没有媒体库路径时每个结果都请求一次上级项目
+func (e *EmbyAdapter) libraryFromParent(libraries []embyLibraryFolder, result *models.SearchResult) *embyLibraryFolder {
+	parentID := result.LibraryID
+	if parentID != "" {
+		e.librariesCacheMutex.RLock()
+		libraryID, ok := e.parentLibraries[parentID]
+		e.librariesCacheMutex.RUnlock()
+		if ok {
+			return libraryByID(libraries, libraryID)
+		}
+	}
+
+	library, err := e.libraryFromAncestors(libraries, result.ID)
+	if err != nil {
+		log.Printf("获取 Emby 项目 %s 的上级项目失败: %v", result.ID, err)
+		return nil
+	}
+
+	if parentID != "" {
+		libraryID := ""
+		if library != nil {
+			libraryID = library.ID
+		}
+		e.librariesCacheMutex.Lock()
+		if e.parentLibraries == nil {
+			e.parentLibraries = make(map[string]string)
+		}
+		e.parentLibraries[parentID] = libraryID
+		e.librariesCacheMutex.Unlock()
+	}
+	return library
+}
+
+// resolveLibrary 将结果中的媒体库设置为项目所属的媒体库，依次按媒体库 ID、文件路径和上级项目确定
+func (e *EmbyAdapter) resolveLibrary(result *models.SearchResult) {
+	libraries, err := e.libraries()
+	if err != nil {
+		log.Printf("获取 Emby 媒体库列表失败: %v", err)
+		return
+	}
+
+	library := libraryByID(libraries, result.LibraryID)
+	if library == nil {
+		library = libraryByPath(libraries, result.Path)
+	}
+	if library == nil {
+		library = e.libraryFromParent(libraries, result)
+	}
+
+	if library == nil {
+		// 无法确定媒体库时不显示父文件夹 ID
+		result.Library = ""
+		return
+	}
+	result.LibraryID = library.ID
+	result.Library = library.Name
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// testLibraries 嵌套的媒体库文件夹：/media/tv/anime 是 /media/tv 中的独立媒体库
+var testLibraries = []embyLibraryFolder{
+	{ID: "movies", Name: "电影", Locations: []string{"/media/movies/"}},
+	{ID: "tv", Name: "剧集", Locations: []string{"/media/tv"}},
+	{ID: "tv2", Name: "剧集2", Locations: []string{"/media/tv2"}},
+	{ID: "anime", Name: "动画", Locations: []string{"/media/tv/anime"}},
+	{ID: "win", Name: "Windows", Locations: []string{`D:\Media\Books\`}},
+}
+
+func TestNormalizeLibraryPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/media/tv/", "/media/tv"},
+		{"/media/tv//", "/media/tv"},
+		{"/media/tv", "/media/tv"},
+		{`D:\Media\Books\`, "d:/media/books"},
+		{`\\nas\share\tv\`, "//nas/share/tv"},
+		{"/", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeLibraryPath(tt.path); got != tt.want {
+			t.Errorf("normalizeLibraryPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLibraryByPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/media/movies/Dune (2021)/Dune.mkv", "movies"},
+		{"/media/movies", "movies"},
+		{"/media/movies/", "movies"},
+		{"/media/tv/Severance/Season 1/S01E01.mkv", "tv"},
+		{"/media/tv/anime/Frieren/S01E01.mkv", "anime"},
+		{"/media/tv/anime/", "anime"},
+		{"/media/tv/animated/Show/S01E01.mkv", "tv"},
+		{"/media/tv2/Show/S01E01.mkv", "tv2"},
+		{"/media/tv22/Show/S01E01.mkv", ""},
+		{"/media/movies2/Film.mkv", ""},
+		{`d:\media\books\Author\Book.epub`, "win"},
+		{"D:/Media/Books", "win"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		got := ""
+		if library := libraryByPath(testLibraries, tt.path); library != nil {
+			got = library.ID
+		}
+		if got != tt.want {
+			t.Errorf("libraryByPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestResolveLibrary(t *testing.T) {
+	var ancestorRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/Library/VirtualFolders":
+			// 非管理员令牌无法获取媒体库路径
+			http.Error(w, "forbidden", http.StatusForbidden)
+		case r.URL.Path == "/Library/MediaFolders":
+			w.Write([]byte(`{"Items":[{"Id":"lib-tv","Name":"剧集"},{"Id":"lib-movies","Name":"电影"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/Ancestors"):
+			atomic.AddInt32(&ancestorRequests, 1)
+			if strings.Contains(r.URL.Path, "orphan") {
+				w.Write([]byte(`[{"Id":"folder-x"},{"Id":"root"}]`))
+				return
+			}
+			w.Write([]byte(`[{"Id":"season-1"},{"Id":"series-1"},{"Id":"lib-tv"},{"Id":"root"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	adapter := NewEmbyAdapter(NewEmbyClient(&config.Config{EmbyURL: server.URL}))
+	adapter.userID = "user"
+
+	tests := []struct {
+		name        string
+		result      models.SearchResult
+		wantID      string
+		wantLibrary string
+	}{
+		{name: "library id", result: models.SearchResult{ID: "m1", LibraryID: "lib-movies"}, wantID: "lib-movies", wantLibrary: "电影"},
+		{name: "ancestors", result: models.SearchResult{ID: "e1", LibraryID: "season-1"}, wantID: "lib-tv", wantLibrary: "剧集"},
+		{name: "cached parent", result: models.SearchResult{ID: "e2", LibraryID: "season-1"}, wantID: "lib-tv", wantLibrary: "剧集"},
+		{name: "no library", result: models.SearchResult{ID: "orphan", LibraryID: "folder-x"}, wantID: "folder-x", wantLibrary: ""},
+		{name: "cached no library", result: models.SearchResult{ID: "orphan2", LibraryID: "folder-x"}, wantID: "folder-x", wantLibrary: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.result
+			adapter.resolveLibrary(&result)
+			if result.LibraryID != tt.wantID || result.Library != tt.wantLibrary {
+				t.Errorf("library = %q (%q), want %q (%q)", result.LibraryID, result.Library, tt.wantID, tt.wantLibrary)
+			}
+		})
+	}
+
+	// 每个父项目只请求一次上级项目
+	if n := atomic.LoadInt32(&ancestorRequests); n != 2 {
+		t.Errorf("ancestor requests = %d, want 2", n)
+	}
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// embyProgressItem 带有外部 ID 和艺术家的音频项目
+type embyProgressItem struct {
+	embyItem
+	AlbumArtist string            `json:"AlbumArtist"`
+	ParentId    string            `json:"ParentId"`
+	Path        string            `json:"Path"`
+	ProviderIds map[string]string `json:"ProviderIds"`
+}
+
+// GetProgressItems 实现 ProgressTracker 接口，返回有播放进度或已播放的音频项目
+// Emby 的进度记录在单个音轨上，由多个音轨组成的有声书只能按音轨匹配
+func (e *EmbyAdapter) GetProgressItems() ([]models.ItemProgress, error) {
+	userID, err := e.getUserID()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []models.ItemProgress
+	seen := make(map[string]bool)
+	for _, filter := range []string{"IsResumable", "IsPlayed"} {
+		data, err := e.client.GetAudioProgressItems(userID, filter, playedItemsLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		var response struct {
+			Items []embyProgressItem `json:"Items"`
+		}
+		if err := json.Unmarshal(data, &response); err != nil {
+			return nil, fmt.Errorf("error unmarshaling progress items: %w", err)
+		}
+
+		for _, item := range response.Items {
+			if seen[item.ID] {
+				continue
+			}
+			seen[item.ID] = true
+
+			var lastUpdate int64
+			if t := parseEmbyTime(item.UserData.LastPlayedDate); !t.IsZero() {
+				lastUpdate = t.UnixMilli()
+			}
+			duration := float64(item.RunTimeTicks) / embyTicksPerSecond
+			result = append(result, models.ItemProgress{
+				Item: models.SearchResult{
+					ID:          item.ID,
+					Title:       item.Name,
+					Author:      item.AlbumArtist,
+					LibraryID:   item.ParentId,
+					Type:        strings.ToLower(item.Type),
+					Path:        item.Path,
+					MediaType:   "Audio",
+					Duration:    duration,
+					RunTime:     item.RunTimeTicks,
+					ProviderIDs: embyProviderIDs(item.ProviderIds),
+				},
+				Position:   float64(item.UserData.PlaybackPositionTicks) / embyTicksPerSecond,
+				Duration:   duration,
+				Finished:   item.UserData.Played,
+				LastUpdate: lastUpdate,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// SetProgress 实现 ProgressTracker 接口，标记为已播放时清除播放位置
+func (e *EmbyAdapter) SetProgress(itemID string, position float64, finished bool) error {
+	userID, err := e.getUserID()
+	if err != nil {
+		return err
+	}
+
+	ticks := int64(position * embyTicksPerSecond)
+	if finished {
+		ticks = 0
+	}
+	return e.client.UpdateUserItemData(userID, itemID, ticks, finished)
+}
+
+// SetPlayed 实现 ProgressTracker 接口
+func (e *EmbyAdapter) SetPlayed(itemID string, played bool) error {
+	userID, err := e.getUserID()
+	if err != nil {
+		return err
+	}
+
+	if played {
+		return e.client.MarkPlayed(userID, itemID)
+	}
+	if err := e.client.MarkUnplayed(userID, itemID); err != nil {
+		return err
+	}
+	// 取消已播放标记不会清除播放位置
+	return e.client.UpdateUserItemData(userID, itemID, 0, false)
+}
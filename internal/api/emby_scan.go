@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// embyRefreshLibraryTaskKey “扫描媒体库”计划任务的标识
+const embyRefreshLibraryTaskKey = "RefreshLibrary"
+
+// embyScheduledTask Emby 计划任务信息
+type embyScheduledTask struct {
+	Name                      string  `json:"Name"`
+	Key                       string  `json:"Key"`
+	State                     string  `json:"State"` // Idle, Cancelling, Running
+	CurrentProgressPercentage float64 `json:"CurrentProgressPercentage"`
+	LastExecutionResult       *struct {
+		StartTimeUtc string `json:"StartTimeUtc"`
+		EndTimeUtc   string `json:"EndTimeUtc"`
+		Status       string `json:"Status"` // Completed, Failed, Cancelled, Aborted
+		ErrorMessage string `json:"ErrorMessage"`
+	} `json:"LastExecutionResult"`
+}
+
+// ScanLibrary 实现 LibraryScanner 接口
+// Emby 的扫描任务针对所有媒体库，因此会同时扫描其他媒体库
+func (e *EmbyAdapter) ScanLibrary(libraryID string) error {
+	return e.client.RefreshLibrary()
+}
+
+// RefreshLibraryMetadata 实现 LibraryScanner 接口
+func (e *EmbyAdapter) RefreshLibraryMetadata(libraryID string) error {
+	return e.client.RefreshItemMetadata(libraryID)
+}
+
+// ReportsMetadataRefresh 实现 MetadataRefreshReporter 接口
+// 元数据刷新由 /Items/{id}/Refresh 触发，不经过“扫描媒体库”计划任务，无法跟踪进度
+func (e *EmbyAdapter) ReportsMetadataRefresh() bool {
+	return false
+}
+
+// GetScanStatus 实现 LibraryScanner 接口，返回“扫描媒体库”计划任务的状态
+func (e *EmbyAdapter) GetScanStatus(libraryID string) (*models.ScanStatus, error) {
+	data, err := e.client.GetScheduledTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []embyScheduledTask
+	err = json.Unmarshal(data, &tasks)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling scheduled tasks: %w", err)
+	}
+
+	for _, task := range tasks {
+		if task.Key != embyRefreshLibraryTaskKey {
+			continue
+		}
+
+		status := &models.ScanStatus{
+			Running:  task.State == "Running",
+			Progress: task.CurrentProgressPercentage,
+		}
+		if result := task.LastExecutionResult; result != nil {
+			// 正在运行时上次执行结果属于之前的任务
+			if !status.Running {
+				if t := parseEmbyTime(result.StartTimeUtc); !t.IsZero() {
+					status.StartedAt = t.UnixMilli()
+				}
+				if t := parseEmbyTime(result.EndTimeUtc); !t.IsZero() {
+					status.FinishedAt = t.UnixMilli()
+				}
+				status.Failed = result.Status != "Completed"
+				status.Message = result.ErrorMessage
+				if status.Message == "" && status.Failed {
+					status.Message = result.Status
+				}
+			}
+		}
+		return status, nil
+	}
+
+	return nil, nil
+}
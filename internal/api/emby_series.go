@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// embyShowItem 季和单集列表中的字段
+type embyShowItem struct {
+	ID                string `json:"Id"`
+	Name              string `json:"Name"`
+	IndexNumber       int    `json:"IndexNumber"`
+	ParentIndexNumber int    `json:"ParentIndexNumber"`
+	Overview          string `json:"Overview"`
+	RunTimeTicks      int64  `json:"RunTimeTicks"`
+	ChildCount        int    `json:"ChildCount"`
+	UserData          struct {
+		Played            bool    `json:"Played"`
+		PlayedPercentage  float64 `json:"PlayedPercentage"`
+		UnplayedItemCount int     `json:"UnplayedItemCount"`
+	} `json:"UserData"`
+}
+
+// getShowItems 解析季或单集列表
+func getShowItems(data []byte) ([]embyShowItem, error) {
+	var response struct {
+		Items []embyShowItem `json:"Items"`
+	}
+	err := json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling show items: %w", err)
+	}
+	return response.Items, nil
+}
+
+// GetSeasons 实现 SeriesBrowser 接口
+func (e *EmbyAdapter) GetSeasons(seriesID string) ([]models.Season, error) {
+	userID, err := e.getUserID()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := e.client.GetSeasons(userID, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := getShowItems(data)
+	if err != nil {
+		return nil, err
+	}
+
+	seasons := make([]models.Season, len(items))
+	for i, item := range items {
+		seasons[i] = models.Season{
+			ID:            item.ID,
+			Name:          item.Name,
+			IndexNumber:   item.IndexNumber,
+			EpisodeCount:  item.ChildCount,
+			UnplayedCount: item.UserData.UnplayedItemCount,
+			Played:        item.UserData.Played,
+		}
+	}
+
+	return seasons, nil
+}
+
+// GetEpisodes 实现 SeriesBrowser 接口
+func (e *EmbyAdapter) GetEpisodes(seriesID, seasonID string) ([]models.Episode, error) {
+	userID, err := e.getUserID()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := e.client.GetEpisodes(userID, seriesID, seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := getShowItems(data)
+	if err != nil {
+		return nil, err
+	}
+
+	episodes := make([]models.Episode, len(items))
+	for i, item := range items {
+		episodes[i] = models.Episode{
+			ID:            item.ID,
+			Name:          item.Name,
+			SeasonNumber:  item.ParentIndexNumber,
+			EpisodeNumber: item.IndexNumber,
+			Overview:      item.Overview,
+			Duration:      float64(item.RunTimeTicks) / embyTicksPerSecond,
+			Played:        item.UserData.Played,
+			Progress:      item.UserData.PlayedPercentage / 100,
+		}
+	}
+
+	return episodes, nil
+}
@@ -0,0 +1,328 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+const (
+	// playbackReportingDays 从 Playback Reporting 插件读取按天统计的天数
+	playbackReportingDays = 90
+	// playedItemsLimit 无插件时读取的已播放项目数量
+	playedItemsLimit = 500
+	// embyTicksPerSecond Emby 时间刻度与秒的换算
+	embyTicksPerSecond = 10000000
+)
+
+// embyItem Emby 项目列表中的通用字段
+type embyItem struct {
+	ID             string   `json:"Id"`
+	Name           string   `json:"Name"`
+	Type           string   `json:"Type"`
+	SeriesName     string   `json:"SeriesName"`
+	RunTimeTicks   int64    `json:"RunTimeTicks"`
+	Genres         []string `json:"Genres"`
+	ProductionYear int      `json:"ProductionYear"`
+	UserData       struct {
+		PlayCount             int     `json:"PlayCount"`
+		Played                bool    `json:"Played"`
+		PlaybackPositionTicks int64   `json:"PlaybackPositionTicks"`
+		PlayedPercentage      float64 `json:"PlayedPercentage"`
+		LastPlayedDate        string  `json:"LastPlayedDate"`
+	} `json:"UserData"`
+}
+
+// displayTitle 返回项目的显示标题，剧集会带上剧名
+func (item *embyItem) displayTitle() string {
+	if item.SeriesName != "" {
+		return fmt.Sprintf("%s - %s", item.SeriesName, item.Name)
+	}
+	return item.Name
+}
+
+// embyItemsResponse Emby 项目列表响应
+type embyItemsResponse struct {
+	Items            []embyItem `json:"Items"`
+	TotalRecordCount int        `json:"TotalRecordCount"`
+}
+
+// parseEmbyTime 解析 Emby 返回的 ISO 8601 时间，失败时返回零值
+func parseEmbyTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// GetPlaybackStats 实现 MediaServer 接口
+func (e *EmbyAdapter) GetPlaybackStats() (*models.PlaybackStats, error) {
+	userID, err := e.getUserID()
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := e.playbackReportingStats(userID)
+	if err != nil {
+		// 未安装 Playback Reporting 插件时使用已播放项目的用户数据
+		log.Printf("Playback Reporting 插件不可用，使用用户数据统计: %v", err)
+		stats, err = e.userDataStats(userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	inProgress, err := e.GetResumeItems()
+	if err != nil {
+		// 正在观看的项目获取失败不影响其他统计
+		log.Printf("获取 Emby 继续观看项目失败: %v", err)
+	}
+	stats.InProgress = inProgressStatsItems(inProgress)
+
+	return stats, nil
+}
+
+// playbackReportingStats 使用 Playback Reporting 插件的播放记录生成统计
+func (e *EmbyAdapter) playbackReportingStats(userID string) (*models.PlaybackStats, error) {
+	userFilter := fmt.Sprintf("UserId = '%s'", strings.ReplaceAll(userID, "'", "''"))
+
+	totals, err := e.customQuery(fmt.Sprintf(
+		"SELECT SUM(PlayDuration), COUNT(DISTINCT ItemId) FROM PlaybackActivity WHERE %s", userFilter))
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.PlaybackStats{
+		Source: models.StatsSourcePlaybackReporting,
+		Days:   make(map[string]float64),
+	}
+	if len(totals) > 0 && len(totals[0]) >= 2 {
+		stats.TotalTime, _ = strconv.ParseFloat(totals[0][0], 64)
+		stats.ItemsPlayed, _ = strconv.Atoi(totals[0][1])
+	}
+
+	days, err := e.customQuery(fmt.Sprintf(
+		"SELECT date(DateCreated), SUM(PlayDuration) FROM PlaybackActivity WHERE %s AND DateCreated >= date('now', '-%d days') GROUP BY date(DateCreated)",
+		userFilter, playbackReportingDays))
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range days {
+		if len(row) < 2 {
+			continue
+		}
+		seconds, _ := strconv.ParseFloat(row[1], 64)
+		stats.Days[row[0]] = seconds
+	}
+
+	hours, err := e.customQuery(fmt.Sprintf(
+		"SELECT strftime('%%w', DateCreated), strftime('%%H', DateCreated), SUM(PlayDuration) FROM PlaybackActivity WHERE %s AND DateCreated >= date('now', '-%d days') GROUP BY 1, 2",
+		userFilter, playbackReportingDays))
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range hours {
+		if len(row) < 3 {
+			continue
+		}
+		weekday, err1 := strconv.Atoi(row[0])
+		hour, err2 := strconv.Atoi(row[1])
+		if err1 != nil || err2 != nil || weekday < 0 || weekday > 6 || hour < 0 || hour > 23 {
+			continue
+		}
+		seconds, _ := strconv.ParseFloat(row[2], 64)
+		stats.WeekdayHours[weekday][hour] += seconds
+	}
+
+	top, err := e.customQuery(fmt.Sprintf(
+		"SELECT ItemId, ItemName, ItemType, SUM(PlayDuration), COUNT(*), MAX(DateCreated) FROM PlaybackActivity WHERE %s GROUP BY ItemId ORDER BY SUM(PlayDuration) DESC LIMIT 50",
+		userFilter))
+	if err != nil {
+		return nil, err
+	}
+
+	playTimeByID := make(map[string]float64)
+	ids := make([]string, 0, len(top))
+	for _, row := range top {
+		if len(row) < 6 {
+			continue
+		}
+		playTime, _ := strconv.ParseFloat(row[3], 64)
+		playCount, _ := strconv.Atoi(row[4])
+
+		var lastPlayed int64
+		if len(row[5]) >= 19 {
+			if t, err := time.ParseInLocation("2006-01-02 15:04:05", row[5][:19], time.Local); err == nil {
+				lastPlayed = t.UnixMilli()
+			}
+		}
+
+		stats.TopItems = append(stats.TopItems, models.StatsItem{
+			ID:         row[0],
+			Title:      row[1],
+			Type:       strings.ToLower(row[2]),
+			PlayTime:   playTime,
+			PlayCount:  playCount,
+			LastPlayed: lastPlayed,
+		})
+		playTimeByID[row[0]] = playTime
+		ids = append(ids, row[0])
+	}
+	stats.TopItems = topStatsItems(stats.TopItems)
+
+	// 播放记录中没有分类信息，需要查询项目详情
+	if len(ids) > 0 {
+		data, err := e.client.GetItemsByIDs(userID, ids, "Genres")
+		if err == nil {
+			var response embyItemsResponse
+			if err := json.Unmarshal(data, &response); err == nil {
+				genres := make(map[string]*models.GenreStat)
+				for _, item := range response.Items {
+					addGenreStat(genres, item.Genres, playTimeByID[item.ID])
+				}
+				stats.TopGenres = topGenreStats(genres)
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// customQuery 执行 Playback Reporting 自定义查询，返回字符串形式的结果行
+func (e *EmbyAdapter) customQuery(query string) ([][]string, error) {
+	data, err := e.client.SubmitCustomQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Columns []string        `json:"colums"`
+		Results [][]interface{} `json:"results"`
+		Message string          `json:"message"`
+	}
+
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling custom query result: %w", err)
+	}
+	if response.Message != "" && len(response.Results) == 0 {
+		return nil, fmt.Errorf("custom query failed: %s", response.Message)
+	}
+
+	rows := make([][]string, len(response.Results))
+	for i, result := range response.Results {
+		rows[i] = make([]string, len(result))
+		for j, value := range result {
+			if value != nil {
+				rows[i][j] = fmt.Sprint(value)
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+// userDataStats 根据已播放项目的用户数据估算统计
+func (e *EmbyAdapter) userDataStats(userID string) (*models.PlaybackStats, error) {
+	data, err := e.client.GetPlayedItems(userID, playedItemsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var response embyItemsResponse
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling played items: %w", err)
+	}
+
+	stats := &models.PlaybackStats{
+		Source:      models.StatsSourceUserData,
+		ItemsPlayed: response.TotalRecordCount,
+		Days:        make(map[string]float64),
+	}
+
+	genres := make(map[string]*models.GenreStat)
+	for _, item := range response.Items {
+		// 用户数据只有播放次数，以时长乘以次数估算播放时间
+		playCount := item.UserData.PlayCount
+		if playCount == 0 {
+			playCount = 1
+		}
+		playTime := float64(item.RunTimeTicks) / embyTicksPerSecond * float64(playCount)
+		stats.TotalTime += playTime
+
+		lastPlayed := parseEmbyTime(item.UserData.LastPlayedDate)
+		if !lastPlayed.IsZero() {
+			local := lastPlayed.Local()
+			runTime := float64(item.RunTimeTicks) / embyTicksPerSecond
+			stats.Days[local.Format(models.StatsDateLayout)] += runTime
+			stats.WeekdayHours[local.Weekday()][local.Hour()] += runTime
+		}
+
+		var lastPlayedMs int64
+		if !lastPlayed.IsZero() {
+			lastPlayedMs = lastPlayed.UnixMilli()
+		}
+		stats.TopItems = append(stats.TopItems, models.StatsItem{
+			ID:         item.ID,
+			Title:      item.displayTitle(),
+			Type:       strings.ToLower(item.Type),
+			PlayTime:   playTime,
+			PlayCount:  item.UserData.PlayCount,
+			Duration:   float64(item.RunTimeTicks) / embyTicksPerSecond,
+			LastPlayed: lastPlayedMs,
+		})
+		addGenreStat(genres, item.Genres, playTime)
+	}
+	stats.TopItems = topStatsItems(stats.TopItems)
+	stats.TopGenres = topGenreStats(genres)
+
+	return stats, nil
+}
+
+// GetResumeItems 实现 MediaServer 接口
+func (e *EmbyAdapter) GetResumeItems() ([]models.ResumeItem, error) {
+	userID, err := e.getUserID()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := e.client.GetResumeItems(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var response embyItemsResponse
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling resume items: %w", err)
+	}
+
+	items := make([]models.ResumeItem, len(response.Items))
+	for i, item := range response.Items {
+		var lastPlayed int64
+		if t := parseEmbyTime(item.UserData.LastPlayedDate); !t.IsZero() {
+			lastPlayed = t.UnixMilli()
+		}
+		items[i] = models.ResumeItem{
+			ID:         item.ID,
+			Title:      item.displayTitle(),
+			Type:       strings.ToLower(item.Type),
+			Position:   float64(item.UserData.PlaybackPositionTicks) / embyTicksPerSecond,
+			Duration:   float64(item.RunTimeTicks) / embyTicksPerSecond,
+			Progress:   item.UserData.PlayedPercentage / 100,
+			LastPlayed: lastPlayed,
+		}
+	}
+
+	return items, nil
+}
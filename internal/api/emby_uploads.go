@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// SetUploadFolders 设置上传目标，键为媒体库名称，值为机器人可写入的本地文件夹
+// Emby 没有上传接口，文件直接写入与媒体库文件夹相同的目录（如共享挂载），然后扫描入库
+func (e *EmbyAdapter) SetUploadFolders(folders map[string]string) {
+	e.uploadFolders = folders
+}
+
+// GetUploadTargets 实现 FileUploader 接口，返回配置了本地文件夹且存在于服务器上的媒体库
+func (e *EmbyAdapter) GetUploadTargets() ([]models.UploadTarget, error) {
+	if len(e.uploadFolders) == 0 {
+		return nil, nil
+	}
+
+	libraries, err := e.libraries()
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []models.UploadTarget
+	for _, library := range libraries {
+		folder, ok := e.uploadFolders[library.Name]
+		if !ok {
+			continue
+		}
+		targets = append(targets, models.UploadTarget{
+			LibraryID: library.ID,
+			Library:   library.Name,
+			FolderID:  library.Name,
+			Folder:    folder,
+		})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Library < targets[j].Library
+	})
+	return targets, nil
+}
+
+// UploadFile 实现 FileUploader 接口，文件写入 “文件夹/标题/文件名”，写入完成前使用临时文件名，避免扫描到不完整的文件
+func (e *EmbyAdapter) UploadFile(target models.UploadTarget, upload models.FileUpload) error {
+	name := sanitizeFileName(upload.Name)
+	if name == "" {
+		return fmt.Errorf("文件名无效")
+	}
+
+	dir := target.Folder
+	if title := sanitizeFileName(upload.Title); title != "" {
+		dir = filepath.Join(dir, title)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("创建文件夹失败: %w", err)
+	}
+
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("文件已存在: %s", path)
+	}
+
+	tempPath := path + ".part"
+	file, err := os.Create(tempPath)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	if _, err := io.Copy(file, upload.Reader); err != nil {
+		file.Close()
+		os.Remove(tempPath)
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("重命名文件失败: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// failingReader 读取部分数据后返回错误
+type failingReader struct {
+	sent bool
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.sent {
+		return 0, errors.New("connection reset")
+	}
+	r.sent = true
+	return copy(p, "partial"), nil
+}
+
+// listFiles 返回目录下所有文件相对 root 的路径
+func listFiles(t *testing.T, root string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			rel, _ := filepath.Rel(root, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestEmbyUploadFile(t *testing.T) {
+	root := t.TempDir()
+	adapter := &EmbyAdapter{}
+	target := models.UploadTarget{Library: "电影", Folder: root}
+
+	err := adapter.UploadFile(target, models.FileUpload{Name: "Dune.mkv", Title: "Dune (2021)", Reader: strings.NewReader("video")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "Dune (2021)", "Dune.mkv"))
+	if err != nil || string(data) != "video" {
+		t.Fatalf("uploaded file = %q, %v", data, err)
+	}
+
+	// 已存在的文件不被覆盖
+	err = adapter.UploadFile(target, models.FileUpload{Name: "Dune.mkv", Title: "Dune (2021)", Reader: strings.NewReader("other")})
+	if err == nil || !strings.Contains(err.Error(), "文件已存在") {
+		t.Errorf("err = %v, want file exists", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "Dune (2021)", "Dune.mkv")); string(data) != "video" {
+		t.Errorf("existing file overwritten: %q", data)
+	}
+
+	if got := listFiles(t, root); len(got) != 1 {
+		t.Errorf("files = %v, want only the uploaded file", got)
+	}
+}
+
+func TestEmbyUploadFileSanitizesNames(t *testing.T) {
+	root := t.TempDir()
+	adapter := &EmbyAdapter{}
+	target := models.UploadTarget{Folder: filepath.Join(root, "library")}
+
+	err := adapter.UploadFile(target, models.FileUpload{Name: "../../escape.mkv", Title: "../Movie: Part 1?", Reader: strings.NewReader("x")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := listFiles(t, root); len(got) != 1 || got[0] != "library/Movie Part 1/escape.mkv" {
+		t.Errorf("files = %v, want library/Movie Part 1/escape.mkv", got)
+	}
+
+	// 没有标题时直接写入媒体库文件夹
+	if err := adapter.UploadFile(target, models.FileUpload{Name: "a.mp4", Title: "..", Reader: strings.NewReader("x")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "library", "a.mp4")); err != nil {
+		t.Error(err)
+	}
+
+	if err := adapter.UploadFile(target, models.FileUpload{Name: "../..", Reader: strings.NewReader("x")}); err == nil {
+		t.Error("expected error for an empty file name")
+	}
+}
+
+func TestEmbyUploadFileRemovesPartialFile(t *testing.T) {
+	root := t.TempDir()
+	adapter := &EmbyAdapter{}
+
+	err := adapter.UploadFile(models.UploadTarget{Folder: root}, models.FileUpload{Name: "Dune.mkv", Reader: &failingReader{}})
+	if err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Fatalf("err = %v, want read error", err)
+	}
+	if got := listFiles(t, root); len(got) != 0 {
+		t.Errorf("files = %v, want no partial file", got)
+	}
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+const (
+	// openLibraryBaseURL Open Library API 地址
+	openLibraryBaseURL = "https://openlibrary.org"
+	// openLibrarySearchLimit 每次搜索返回的书籍数量
+	openLibrarySearchLimit = 10
+)
+
+// OpenLibraryClient Open Library 书籍元数据客户端，不需要 API Key
+type OpenLibraryClient struct {
+	httpClient *http.Client
+}
+
+// NewOpenLibraryClient 创建 Open Library 客户端
+func NewOpenLibraryClient() *OpenLibraryClient {
+	return &OpenLibraryClient{
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Name 实现 MetadataProvider 接口
+func (c *OpenLibraryClient) Name() string {
+	return "openlibrary"
+}
+
+// SearchMetadata 实现 MetadataProvider 接口
+func (c *OpenLibraryClient) SearchMetadata(text string) ([]models.MediaMetadata, error) {
+	params := url.Values{}
+	params.Add("q", text)
+	params.Add("limit", fmt.Sprintf("%d", openLibrarySearchLimit))
+	params.Add("fields", "key,title,author_name,first_publish_year,isbn")
+
+	resp, err := c.httpClient.Get(openLibraryBaseURL + "/search.json?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Docs []struct {
+			Key              string   `json:"key"`
+			Title            string   `json:"title"`
+			AuthorName       []string `json:"author_name"`
+			FirstPublishYear int      `json:"first_publish_year"`
+			ISBN             []string `json:"isbn"`
+		} `json:"docs"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling search results: %w", err)
+	}
+
+	results := make([]models.MediaMetadata, 0, len(response.Docs))
+	for _, doc := range response.Docs {
+		metadata := models.MediaMetadata{
+			Provider: c.Name(),
+			ID:       strings.TrimPrefix(doc.Key, "/works/"),
+			Kind:     models.MediaKindBook,
+			Title:    doc.Title,
+			Year:     doc.FirstPublishYear,
+			Author:   strings.Join(doc.AuthorName, ", "),
+		}
+		// 同一作品有多个版本的 ISBN，只能用于展示，匹配时按标题和作者
+		if len(doc.ISBN) > 0 {
+			metadata.ProviderIDs = map[string]string{models.ProviderISBN: doc.ISBN[0]}
+		}
+		results = append(results, metadata)
+	}
+	return results, nil
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// qbittorrentPausedStates qBittorrent 中表示已暂停的状态，5.0 起 paused 改名为 stopped
+var qbittorrentPausedStates = map[string]bool{
+	"pausedDL":  true,
+	"pausedUP":  true,
+	"stoppedDL": true,
+	"stoppedUP": true,
+}
+
+// QbittorrentClient qBittorrent WebUI API 客户端
+type QbittorrentClient struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	// loginMutex 避免会话过期时多个请求同时重新登录
+	loginMutex sync.Mutex
+}
+
+// NewQbittorrentClient 创建 qBittorrent 客户端
+func NewQbittorrentClient(cfg *config.Config) *QbittorrentClient {
+	jar, _ := cookiejar.New(nil)
+	return &QbittorrentClient{
+		baseURL:    strings.TrimRight(cfg.QbittorrentURL, "/"),
+		username:   cfg.QbittorrentUser,
+		password:   cfg.QbittorrentPassword,
+		httpClient: &http.Client{Jar: jar, Timeout: 15 * time.Second},
+	}
+}
+
+// Name 实现 TorrentClient 接口
+func (c *QbittorrentClient) Name() string {
+	return "qBittorrent"
+}
+
+// login 登录并将会话 Cookie 保存在 Cookie Jar 中
+func (c *QbittorrentClient) login() error {
+	c.loginMutex.Lock()
+	defer c.loginMutex.Unlock()
+
+	form := url.Values{}
+	form.Set("username", c.username)
+	form.Set("password", c.password)
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/api/v2/auth/login", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// qBittorrent 会校验 Referer 以防止 CSRF
+	req.Header.Set("Referer", c.baseURL)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || strings.TrimSpace(string(body)) != "Ok." {
+		return fmt.Errorf("qBittorrent login failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// doRequest performs an API request, logging in again when the session has expired
+func (c *QbittorrentClient) doRequest(method, path string, form url.Values) (int, []byte, error) {
+	for attempt := 0; ; attempt++ {
+		var body io.Reader
+		endpoint := c.baseURL + "/api/v2" + path
+		if method == http.MethodGet && len(form) > 0 {
+			endpoint += "?" + form.Encode()
+		} else if form != nil {
+			body = strings.NewReader(form.Encode())
+		}
+
+		req, err := http.NewRequest(method, endpoint, body)
+		if err != nil {
+			return 0, nil, fmt.Errorf("error creating request: %w", err)
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		req.Header.Set("Referer", c.baseURL)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return 0, nil, fmt.Errorf("error making request: %w", err)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return 0, nil, fmt.Errorf("error reading response body: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusForbidden && attempt == 0 {
+			if err := c.login(); err != nil {
+				return 0, nil, err
+			}
+			continue
+		}
+		return resp.StatusCode, respBody, nil
+	}
+}
+
+// post 发送表单请求，非 2xx 状态视为失败
+func (c *QbittorrentClient) post(path string, form url.Values) (int, error) {
+	status, body, err := c.doRequest(http.MethodPost, path, form)
+	if err != nil {
+		return status, err
+	}
+	if status < 200 || status >= 300 {
+		return status, fmt.Errorf("API request failed with status %d: %s", status, string(body))
+	}
+	return status, nil
+}
+
+// GetTorrents 实现 TorrentClient 接口
+func (c *QbittorrentClient) GetTorrents() ([]models.Torrent, error) {
+	status, body, err := c.doRequest(http.MethodGet, "/torrents/info", nil)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d: %s", status, string(body))
+	}
+
+	var items []struct {
+		Hash     string  `json:"hash"`
+		Name     string  `json:"name"`
+		State    string  `json:"state"`
+		Progress float64 `json:"progress"`
+		ETA      int64   `json:"eta"`
+		Ratio    float64 `json:"ratio"`
+		Size     int64   `json:"size"`
+		DlSpeed  int64   `json:"dlspeed"`
+		UpSpeed  int64   `json:"upspeed"`
+		SavePath string  `json:"save_path"`
+	}
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, fmt.Errorf("error unmarshaling torrents: %w", err)
+	}
+
+	torrents := make([]models.Torrent, 0, len(items))
+	for _, item := range items {
+		eta := item.ETA
+		// 8640000 表示无穷大
+		if eta >= 8640000 {
+			eta = -1
+		}
+		torrents = append(torrents, models.Torrent{
+			ID:            item.Hash,
+			Name:          item.Name,
+			State:         item.State,
+			Progress:      item.Progress,
+			ETA:           eta,
+			Ratio:         item.Ratio,
+			Size:          item.Size,
+			DownloadSpeed: item.DlSpeed,
+			UploadSpeed:   item.UpSpeed,
+			SavePath:      item.SavePath,
+			Paused:        qbittorrentPausedStates[item.State],
+			Completed:     item.Progress >= 1,
+		})
+	}
+	return torrents, nil
+}
+
+// PauseTorrent 实现 TorrentClient 接口，5.0 之前的版本使用 pause，之后使用 stop
+func (c *QbittorrentClient) PauseTorrent(id string) error {
+	return c.postWithFallback("/torrents/stop", "/torrents/pause", url.Values{"hashes": {id}})
+}
+
+// ResumeTorrent 实现 TorrentClient 接口，5.0 之前的版本使用 resume，之后使用 start
+func (c *QbittorrentClient) ResumeTorrent(id string) error {
+	return c.postWithFallback("/torrents/start", "/torrents/resume", url.Values{"hashes": {id}})
+}
+
+// DeleteTorrent 实现 TorrentClient 接口
+func (c *QbittorrentClient) DeleteTorrent(id string, deleteFiles bool) error {
+	_, err := c.post("/torrents/delete", url.Values{"hashes": {id}, "deleteFiles": {fmt.Sprintf("%t", deleteFiles)}})
+	return err
+}
+
+// postWithFallback 先调用新版接口，返回 404 时改用旧版接口
+func (c *QbittorrentClient) postWithFallback(path, legacyPath string, form url.Values) error {
+	status, err := c.post(path, form)
+	if status == http.StatusNotFound {
+		_, err = c.post(legacyPath, form)
+	}
+	return err
+}
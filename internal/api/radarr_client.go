@@ -0,0 +1,82 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// RadarrClient Radarr v3 API 客户端
+type RadarrClient struct {
+	*arrClient
+}
+
+// NewRadarrClient 创建 Radarr 客户端
+func NewRadarrClient(cfg *config.Config) *RadarrClient {
+	return &RadarrClient{arrClient: newArrClient("Radarr", cfg.RadarrURL, "v3", cfg.RadarrAPIKey)}
+}
+
+// Kind 实现 MediaAcquirer 接口
+func (c *RadarrClient) Kind() string {
+	return models.MediaKindMovie
+}
+
+// Add 实现 MediaAcquirer 接口，优先按 TMDb ID 查找电影，没有 ID 时按标题和年份查找
+func (c *RadarrClient) Add(metadata *models.MediaMetadata, options models.ArrAddOptions) (*models.ArrItem, error) {
+	movie, err := c.lookupMovie(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.addResource("/movie", movie, map[string]interface{}{
+		"qualityProfileId":    options.QualityProfileID,
+		"rootFolderPath":      options.RootFolderPath,
+		"monitored":           true,
+		"minimumAvailability": "released",
+		"addOptions":          map[string]interface{}{"searchForMovie": true},
+	})
+}
+
+// lookupMovie 按 TMDb ID 查找电影，没有 ID 时依次按标题和原标题查找
+func (c *RadarrClient) lookupMovie(metadata *models.MediaMetadata) (map[string]interface{}, error) {
+	if tmdbID := metadata.ProviderIDs[models.ProviderTMDb]; tmdbID != "" {
+		params := url.Values{}
+		params.Set("tmdbId", tmdbID)
+		var movie map[string]interface{}
+		if err := c.doRequest(http.MethodGet, "/movie/lookup/tmdb", params, nil, &movie); err != nil {
+			return nil, err
+		}
+		return movie, nil
+	}
+
+	terms := []string{metadata.Title}
+	if metadata.OriginalTitle != "" && metadata.OriginalTitle != metadata.Title {
+		terms = append(terms, metadata.OriginalTitle)
+	}
+	for _, term := range terms {
+		params := url.Values{}
+		params.Set("term", term)
+		var results []map[string]interface{}
+		if err := c.doRequest(http.MethodGet, "/movie/lookup", params, nil, &results); err != nil {
+			return nil, err
+		}
+		if movie, ok := pickLookupResult(results, metadata, "tmdbId", ""); ok {
+			return movie, nil
+		}
+	}
+	return nil, fmt.Errorf("movie %q not found in Radarr lookup", metadata.Title)
+}
+
+// GetQueue 实现 MediaAcquirer 接口
+func (c *RadarrClient) GetQueue() ([]models.ArrQueueItem, error) {
+	return c.getQueue(url.Values{"includeMovie": {"false"}}, "movieId")
+}
+
+// GetImportedSince 实现 MediaAcquirer 接口
+func (c *RadarrClient) GetImportedSince(since time.Time) ([]models.ArrImport, error) {
+	return c.getImportedSince(since, "movieId")
+}
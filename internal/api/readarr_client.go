@@ -0,0 +1,244 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+const (
+	// readarrProvider Readarr 作为元数据服务时的名称
+	readarrProvider = "readarr"
+	// readarrAuthorLookupLimit 搜索匹配到作者时最多展开几位作者的书籍
+	readarrAuthorLookupLimit = 3
+)
+
+// ReadarrClient Readarr v1 API 客户端
+type ReadarrClient struct {
+	*arrClient
+}
+
+// NewReadarrClient 创建 Readarr 客户端
+func NewReadarrClient(cfg *config.Config) *ReadarrClient {
+	return &ReadarrClient{arrClient: newArrClient("Readarr", cfg.ReadarrURL, "v1", cfg.ReadarrAPIKey)}
+}
+
+// Kind 实现 MediaAcquirer 接口
+func (c *ReadarrClient) Kind() string {
+	return models.MediaKindBook
+}
+
+// GetMetadataProfiles 实现 MetadataProfileProvider 接口
+func (c *ReadarrClient) GetMetadataProfiles() ([]models.ArrProfile, error) {
+	var profiles []models.ArrProfile
+	if err := c.doRequest(http.MethodGet, "/metadataprofile", nil, nil, &profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// search 按关键词搜索作者和书籍，返回的每一项包含 author 或 book 对象
+func (c *ReadarrClient) search(term string) ([]map[string]interface{}, error) {
+	params := url.Values{}
+	params.Set("term", term)
+	var results []map[string]interface{}
+	if err := c.doRequest(http.MethodGet, "/search", params, nil, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SearchMetadata 实现 MetadataProvider 接口，按书名或作者搜索书籍，匹配到的作者会展开为其书籍
+func (c *ReadarrClient) SearchMetadata(text string) ([]models.MediaMetadata, error) {
+	results, err := c.search(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var books []models.MediaMetadata
+	seen := make(map[string]bool)
+	addBook := func(book map[string]interface{}) {
+		metadata := readarrBookMetadata(book)
+		key := metadata.ID
+		if key == "" {
+			key = metadata.Title + "|" + metadata.Author
+		}
+		if metadata.Title == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		books = append(books, metadata)
+	}
+
+	var authors []string
+	for _, result := range results {
+		if book, ok := result["book"].(map[string]interface{}); ok {
+			addBook(book)
+			continue
+		}
+		author, _ := result["author"].(map[string]interface{})
+		if name := jsonString(author["authorName"]); name != "" && len(authors) < readarrAuthorLookupLimit {
+			authors = append(authors, name)
+		}
+	}
+
+	for _, author := range authors {
+		authorBooks, err := c.lookupAuthorBooks(author)
+		if err != nil {
+			log.Printf("查找 Readarr 作者 %s 的书籍失败: %v", author, err)
+			continue
+		}
+		for _, book := range authorBooks {
+			addBook(book)
+		}
+	}
+	return books, nil
+}
+
+// lookupAuthorBooks 按作者名查找书籍，只返回该作者的书籍
+func (c *ReadarrClient) lookupAuthorBooks(author string) ([]map[string]interface{}, error) {
+	params := url.Values{}
+	params.Set("term", author)
+	var results []map[string]interface{}
+	if err := c.doRequest(http.MethodGet, "/book/lookup", params, nil, &results); err != nil {
+		return nil, err
+	}
+
+	var books []map[string]interface{}
+	for _, book := range results {
+		if bookByAuthor(book, author) {
+			books = append(books, book)
+		}
+	}
+	return books, nil
+}
+
+// readarrBookMetadata 将 Readarr 的书籍转换为请求使用的元数据
+// 同一本书各版本的 ISBN 和 ASIN 不同，不作为外部 ID，匹配时按标题和作者
+func readarrBookMetadata(book map[string]interface{}) models.MediaMetadata {
+	author, _ := book["author"].(map[string]interface{})
+	return models.MediaMetadata{
+		Provider: readarrProvider,
+		ID:       jsonString(book["foreignBookId"]),
+		Kind:     models.MediaKindBook,
+		Title:    jsonString(book["title"]),
+		Year:     yearFromDate(jsonString(book["releaseDate"])),
+		Author:   jsonString(author["authorName"]),
+		Overview: jsonString(book["overview"]),
+	}
+}
+
+// bookByAuthor 判断书籍的作者是否与 author 相符，author 为空时视为相符
+func bookByAuthor(book map[string]interface{}, author string) bool {
+	author = util.NormalizeTitle(author)
+	if author == "" {
+		return true
+	}
+	bookAuthor, _ := book["author"].(map[string]interface{})
+	name := util.NormalizeTitle(jsonString(bookAuthor["authorName"]))
+	return name != "" && (strings.Contains(author, name) || strings.Contains(name, author))
+}
+
+// Add 实现 MediaAcquirer 接口，添加书籍时一并添加作者，但只监控请求的这本书
+func (c *ReadarrClient) Add(metadata *models.MediaMetadata, options models.ArrAddOptions) (*models.ArrItem, error) {
+	book, err := c.lookupBook(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	author, _ := book["author"].(map[string]interface{})
+	if author == nil {
+		return nil, fmt.Errorf("book %q has no author in Readarr lookup", metadata.Title)
+	}
+	if jsonInt(author["id"]) == 0 {
+		author["qualityProfileId"] = options.QualityProfileID
+		author["metadataProfileId"] = options.MetadataProfileID
+		author["rootFolderPath"] = options.RootFolderPath
+		author["monitored"] = true
+		author["addOptions"] = map[string]interface{}{"monitor": "none", "searchForMissingBooks": false}
+	}
+
+	// 至少需要监控一个版本，查找结果中没有监控的版本时使用第一个
+	if editions, ok := book["editions"].([]interface{}); ok && len(editions) > 0 {
+		monitored := false
+		for _, edition := range editions {
+			if e, ok := edition.(map[string]interface{}); ok && e["monitored"] == true {
+				monitored = true
+			}
+		}
+		if e, ok := editions[0].(map[string]interface{}); ok && !monitored {
+			e["monitored"] = true
+		}
+	}
+
+	item, err := c.addResource("/book", book, map[string]interface{}{
+		"author":     author,
+		"monitored":  true,
+		"addOptions": map[string]interface{}{"searchForNewBook": true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if item.Year == 0 {
+		item.Year = yearFromDate(jsonString(book["releaseDate"]))
+	}
+	return item, nil
+}
+
+// lookupBook 依次按 “标题 作者” 和标题搜索书籍，选择标题一致且作者相符的结果
+// 元数据来自 Readarr 时优先选择同一本书
+func (c *ReadarrClient) lookupBook(metadata *models.MediaMetadata) (map[string]interface{}, error) {
+	terms := []string{metadata.Title}
+	if metadata.Author != "" {
+		terms = []string{metadata.Title + " " + metadata.Author, metadata.Title}
+	}
+
+	foreignID := ""
+	if metadata.Provider == readarrProvider {
+		foreignID = metadata.ID
+	}
+	title := util.NormalizeTitle(metadata.Title)
+	for _, term := range terms {
+		results, err := c.search(term)
+		if err != nil {
+			return nil, err
+		}
+
+		var books []map[string]interface{}
+		for _, result := range results {
+			if book, ok := result["book"].(map[string]interface{}); ok {
+				books = append(books, book)
+			}
+		}
+		if foreignID != "" {
+			for _, book := range books {
+				if jsonString(book["foreignBookId"]) == foreignID {
+					return book, nil
+				}
+			}
+		}
+		for _, book := range books {
+			if util.NormalizeTitle(jsonString(book["title"])) == title && bookByAuthor(book, metadata.Author) {
+				return book, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("book %q not found in Readarr search", metadata.Title)
+}
+
+// GetQueue 实现 MediaAcquirer 接口
+func (c *ReadarrClient) GetQueue() ([]models.ArrQueueItem, error) {
+	return c.getQueue(url.Values{"includeBook": {"false"}}, "bookId")
+}
+
+// GetImportedSince 实现 MediaAcquirer 接口
+func (c *ReadarrClient) GetImportedSince(since time.Time) ([]models.ArrImport, error) {
+	return c.getImportedSince(since, "bookId")
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// SonarrClient Sonarr v3 API 客户端
+type SonarrClient struct {
+	*arrClient
+}
+
+// NewSonarrClient 创建 Sonarr 客户端
+func NewSonarrClient(cfg *config.Config) *SonarrClient {
+	return &SonarrClient{arrClient: newArrClient("Sonarr", cfg.SonarrURL, "v3", cfg.SonarrAPIKey)}
+}
+
+// Kind 实现 MediaAcquirer 接口
+func (c *SonarrClient) Kind() string {
+	return models.MediaKindSeries
+}
+
+// Add 实现 MediaAcquirer 接口，添加剧集并搜索所有缺失的单集
+func (c *SonarrClient) Add(metadata *models.MediaMetadata, options models.ArrAddOptions) (*models.ArrItem, error) {
+	series, err := c.lookupSeries(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sonarr v3 添加剧集时必须指定语言配置，使用第一个
+	var languageProfiles []models.ArrProfile
+	if err := c.doRequest(http.MethodGet, "/languageprofile", nil, nil, &languageProfiles); err != nil {
+		return nil, err
+	}
+	if len(languageProfiles) == 0 {
+		return nil, fmt.Errorf("no language profile configured in Sonarr")
+	}
+
+	return c.addResource("/series", series, map[string]interface{}{
+		"qualityProfileId":  options.QualityProfileID,
+		"languageProfileId": languageProfiles[0].ID,
+		"rootFolderPath":    options.RootFolderPath,
+		"monitored":         true,
+		"seasonFolder":      true,
+		"addOptions": map[string]interface{}{
+			"monitor":                  "all",
+			"searchForMissingEpisodes": true,
+		},
+	})
+}
+
+// lookupSeries 依次按 TVDb ID、标题和原标题查找剧集，TMDb 返回的标题可能是译名
+func (c *SonarrClient) lookupSeries(metadata *models.MediaMetadata) (map[string]interface{}, error) {
+	var terms []string
+	if tvdbID := metadata.ProviderIDs[models.ProviderTVDb]; tvdbID != "" {
+		terms = append(terms, "tvdb:"+tvdbID)
+	}
+	terms = append(terms, metadata.Title)
+	if metadata.OriginalTitle != "" && metadata.OriginalTitle != metadata.Title {
+		terms = append(terms, metadata.OriginalTitle)
+	}
+
+	for _, term := range terms {
+		params := url.Values{}
+		params.Set("term", term)
+		var results []map[string]interface{}
+		if err := c.doRequest(http.MethodGet, "/series/lookup", params, nil, &results); err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(term, "tvdb:") && len(results) > 0 {
+			return results[0], nil
+		}
+		if series, ok := pickLookupResult(results, metadata, "tmdbId", metadata.ProviderIDs[models.ProviderTMDb]); ok {
+			return series, nil
+		}
+	}
+	return nil, fmt.Errorf("series %q not found in Sonarr lookup", metadata.Title)
+}
+
+// GetQueue 实现 MediaAcquirer 接口
+func (c *SonarrClient) GetQueue() ([]models.ArrQueueItem, error) {
+	return c.getQueue(url.Values{"includeSeries": {"false"}}, "seriesId")
+}
+
+// GetImportedSince 实现 MediaAcquirer 接口
+func (c *SonarrClient) GetImportedSince(since time.Time) ([]models.ArrImport, error) {
+	return c.getImportedSince(since, "seriesId")
+}
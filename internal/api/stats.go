@@ -0,0 +1,74 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// statsTopLimit 统计中保留的热门项目和分类数量
+const statsTopLimit = 10
+
+// addGenreStat 将播放时长累加到各个分类
+func addGenreStat(genres map[string]*models.GenreStat, itemGenres []string, playTime float64) {
+	for _, genre := range itemGenres {
+		stat, ok := genres[genre]
+		if !ok {
+			stat = &models.GenreStat{Genre: genre}
+			genres[genre] = stat
+		}
+		stat.PlayTime += playTime
+		stat.ItemCount++
+	}
+}
+
+// topGenreStats 返回播放时长最多的分类
+func topGenreStats(genres map[string]*models.GenreStat) []models.GenreStat {
+	result := make([]models.GenreStat, 0, len(genres))
+	for _, stat := range genres {
+		result = append(result, *stat)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].PlayTime != result[j].PlayTime {
+			return result[i].PlayTime > result[j].PlayTime
+		}
+		return result[i].Genre < result[j].Genre
+	})
+
+	if len(result) > statsTopLimit {
+		result = result[:statsTopLimit]
+	}
+	return result
+}
+
+// topStatsItems 按播放时长排序并保留热门项目
+func topStatsItems(items []models.StatsItem) []models.StatsItem {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].PlayTime != items[j].PlayTime {
+			return items[i].PlayTime > items[j].PlayTime
+		}
+		return items[i].PlayCount > items[j].PlayCount
+	})
+
+	if len(items) > statsTopLimit {
+		items = items[:statsTopLimit]
+	}
+	return items
+}
+
+// inProgressStatsItems 将未播放完的项目转换为统计项目
+func inProgressStatsItems(items []models.ResumeItem) []models.StatsItem {
+	result := make([]models.StatsItem, len(items))
+	for i, item := range items {
+		result[i] = models.StatsItem{
+			ID:         item.ID,
+			Title:      item.Title,
+			Author:     item.Author,
+			Type:       item.Type,
+			Progress:   item.Progress,
+			Duration:   item.Duration,
+			LastPlayed: item.LastPlayed,
+		}
+	}
+	return result
+}
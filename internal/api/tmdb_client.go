@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// tmdbBaseURL TMDb API 地址
+const tmdbBaseURL = "https://api.themoviedb.org/3"
+
+// TmdbClient TMDb 电影和剧集元数据客户端
+type TmdbClient struct {
+	apiKey     string
+	language   string
+	httpClient *http.Client
+}
+
+// NewTmdbClient 创建 TMDb 客户端
+func NewTmdbClient(cfg *config.Config) *TmdbClient {
+	return &TmdbClient{
+		apiKey:     cfg.TmdbAPIKey,
+		language:   cfg.TmdbLanguage,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Name 实现 MetadataProvider 接口
+func (c *TmdbClient) Name() string {
+	return models.ProviderTMDb
+}
+
+// SearchMetadata 实现 MetadataProvider 接口，同时搜索电影和剧集
+func (c *TmdbClient) SearchMetadata(text string) ([]models.MediaMetadata, error) {
+	params := url.Values{}
+	params.Add("api_key", c.apiKey)
+	params.Add("query", text)
+	params.Add("language", c.language)
+	params.Add("include_adult", "false")
+
+	resp, err := c.httpClient.Get(tmdbBaseURL + "/search/multi?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Results []struct {
+			ID            int    `json:"id"`
+			MediaType     string `json:"media_type"`
+			Title         string `json:"title"`
+			Name          string `json:"name"`
+			OriginalTitle string `json:"original_title"`
+			OriginalName  string `json:"original_name"`
+			ReleaseDate   string `json:"release_date"`
+			FirstAirDate  string `json:"first_air_date"`
+			Overview      string `json:"overview"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling search results: %w", err)
+	}
+
+	var results []models.MediaMetadata
+	for _, item := range response.Results {
+		metadata := models.MediaMetadata{
+			Provider:    models.ProviderTMDb,
+			ID:          strconv.Itoa(item.ID),
+			Overview:    item.Overview,
+			ProviderIDs: map[string]string{models.ProviderTMDb: strconv.Itoa(item.ID)},
+		}
+		switch item.MediaType {
+		case "movie":
+			metadata.Kind = models.MediaKindMovie
+			metadata.Title = item.Title
+			metadata.OriginalTitle = item.OriginalTitle
+			metadata.Year = yearFromDate(item.ReleaseDate)
+		case "tv":
+			metadata.Kind = models.MediaKindSeries
+			metadata.Title = item.Name
+			metadata.OriginalTitle = item.OriginalName
+			metadata.Year = yearFromDate(item.FirstAirDate)
+		default:
+			// 人物等其他类型不能请求
+			continue
+		}
+		results = append(results, metadata)
+	}
+	return results, nil
+}
+
+// yearFromDate 从 “2006-01-02” 格式的日期中取出年份
+func yearFromDate(date string) int {
+	if len(date) < 4 {
+		return 0
+	}
+	year, _ := strconv.Atoi(date[:4])
+	return year
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+const (
+	// transmissionSessionHeader Transmission 用于防止 CSRF 的会话 ID 请求头
+	transmissionSessionHeader = "X-Transmission-Session-Id"
+	// transmissionStatusStopped Transmission 中已停止的状态
+	transmissionStatusStopped = 0
+)
+
+// transmissionStatusNames Transmission 状态码对应的名称
+var transmissionStatusNames = map[int]string{
+	0: "stopped",
+	1: "checkWait",
+	2: "checking",
+	3: "downloadWait",
+	4: "downloading",
+	5: "seedWait",
+	6: "seeding",
+}
+
+// TransmissionClient Transmission RPC 客户端
+type TransmissionClient struct {
+	rpcURL     string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	sessionID string
+}
+
+// NewTransmissionClient 创建 Transmission 客户端，地址未包含 RPC 路径时使用默认的 /transmission/rpc
+func NewTransmissionClient(cfg *config.Config) *TransmissionClient {
+	rpcURL := strings.TrimRight(cfg.TransmissionURL, "/")
+	if !strings.HasSuffix(rpcURL, "/rpc") {
+		rpcURL += "/transmission/rpc"
+	}
+	return &TransmissionClient{
+		rpcURL:     rpcURL,
+		username:   cfg.TransmissionUser,
+		password:   cfg.TransmissionPassword,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Name 实现 TorrentClient 接口
+func (c *TransmissionClient) Name() string {
+	return "Transmission"
+}
+
+// call 调用 RPC 方法，会话 ID 过期时服务器返回 409 和新的会话 ID，使用新 ID 重试
+func (c *TransmissionClient) call(method string, arguments interface{}, result interface{}) error {
+	payload, err := json.Marshal(map[string]interface{}{"method": method, "arguments": arguments})
+	if err != nil {
+		return fmt.Errorf("error marshaling request body: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(http.MethodPost, c.rpcURL, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("error creating request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if c.username != "" {
+			req.SetBasicAuth(c.username, c.password)
+		}
+		c.mu.Lock()
+		req.Header.Set(transmissionSessionHeader, c.sessionID)
+		c.mu.Unlock()
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("error making request: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("error reading response body: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusConflict && attempt == 0 {
+			c.mu.Lock()
+			c.sessionID = resp.Header.Get(transmissionSessionHeader)
+			c.mu.Unlock()
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("RPC request failed with status %d: %s", resp.StatusCode, string(body))
+		}
+
+		var response struct {
+			Result    string          `json:"result"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			return fmt.Errorf("error unmarshaling %s response: %w", method, err)
+		}
+		if response.Result != "success" {
+			return fmt.Errorf("RPC %s failed: %s", method, response.Result)
+		}
+		if result == nil {
+			return nil
+		}
+		if err := json.Unmarshal(response.Arguments, result); err != nil {
+			return fmt.Errorf("error unmarshaling %s arguments: %w", method, err)
+		}
+		return nil
+	}
+}
+
+// GetTorrents 实现 TorrentClient 接口
+func (c *TransmissionClient) GetTorrents() ([]models.Torrent, error) {
+	arguments := map[string]interface{}{
+		"fields": []string{"hashString", "name", "status", "percentDone", "eta", "uploadRatio",
+			"totalSize", "rateDownload", "rateUpload", "downloadDir"},
+	}
+	var result struct {
+		Torrents []struct {
+			HashString   string  `json:"hashString"`
+			Name         string  `json:"name"`
+			Status       int     `json:"status"`
+			PercentDone  float64 `json:"percentDone"`
+			ETA          int64   `json:"eta"`
+			UploadRatio  float64 `json:"uploadRatio"`
+			TotalSize    int64   `json:"totalSize"`
+			RateDownload int64   `json:"rateDownload"`
+			RateUpload   int64   `json:"rateUpload"`
+			DownloadDir  string  `json:"downloadDir"`
+		} `json:"torrents"`
+	}
+	if err := c.call("torrent-get", arguments, &result); err != nil {
+		return nil, err
+	}
+
+	torrents := make([]models.Torrent, 0, len(result.Torrents))
+	for _, item := range result.Torrents {
+		eta := item.ETA
+		// -1 表示不适用，-2 表示未知
+		if eta < 0 {
+			eta = -1
+		}
+		ratio := item.UploadRatio
+		if ratio < 0 {
+			ratio = 0
+		}
+		torrents = append(torrents, models.Torrent{
+			ID:            item.HashString,
+			Name:          item.Name,
+			State:         transmissionStatusNames[item.Status],
+			Progress:      item.PercentDone,
+			ETA:           eta,
+			Ratio:         ratio,
+			Size:          item.TotalSize,
+			DownloadSpeed: item.RateDownload,
+			UploadSpeed:   item.RateUpload,
+			SavePath:      item.DownloadDir,
+			Paused:        item.Status == transmissionStatusStopped,
+			Completed:     item.PercentDone >= 1,
+		})
+	}
+	return torrents, nil
+}
+
+// PauseTorrent 实现 TorrentClient 接口
+func (c *TransmissionClient) PauseTorrent(id string) error {
+	return c.call("torrent-stop", map[string]interface{}{"ids": []string{id}}, nil)
+}
+
+// ResumeTorrent 实现 TorrentClient 接口
+func (c *TransmissionClient) ResumeTorrent(id string) error {
+	return c.call("torrent-start", map[string]interface{}{"ids": []string{id}}, nil)
+}
+
+// DeleteTorrent 实现 TorrentClient 接口
+func (c *TransmissionClient) DeleteTorrent(id string, deleteFiles bool) error {
+	return c.call("torrent-remove", map[string]interface{}{"ids": []string{id}, "delete-local-data": deleteFiles}, nil)
+}
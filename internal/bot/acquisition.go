@@ -0,0 +1,232 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+// 添加请求到下载管理服务的回调动作
+const (
+	actionAcquireStart   = "arr_add"
+	actionAcquireProfile = "arr_qp"
+	actionAcquireMeta    = "arr_mp"
+	actionAcquireFolder  = "arr_root"
+)
+
+// queueItemLimit 每个服务的下载队列中显示的最大条目数
+const queueItemLimit = 15
+
+// acquireButtons 返回已批准请求的添加按钮，没有处理该类型作品的服务时不显示
+func (bm *Manager) acquireButtons(request *services.MediaRequest) []tgbotapi.InlineKeyboardButton {
+	acquirer, ok := bm.acquisition.Acquirer(request.Metadata.Kind)
+	if !ok {
+		return nil
+	}
+	return tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+		"📥 添加到 "+acquirer.Name(), bm.callbackData(actionAcquireStart, strconv.Itoa(request.ID))))
+}
+
+// handleAcquireAction 依次选择质量配置、元数据配置（仅 Readarr）和根文件夹后将请求的作品添加到下载管理服务
+func (bm *Manager) handleAcquireAction(callback *tgbotapi.CallbackQuery, action string, args []string) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	if !bm.IsUserAdmin(callback.From.ID) {
+		bm.SendMessage(chatID, "🚫 只有管理员可以添加下载")
+		return
+	}
+
+	ids := make([]int, len(args))
+	for i, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			log.Printf("无效的下载参数: %v", args)
+			return
+		}
+		ids[i] = id
+	}
+	if len(ids) < 1 {
+		log.Printf("无效的下载参数: %v", args)
+		return
+	}
+
+	request, ok := bm.requests.Get(ids[0])
+	if !ok {
+		bm.EditMessage(chatID, messageID, fmt.Sprintf("❌ 请求 #%d 不存在", ids[0]))
+		return
+	}
+	acquirer, ok := bm.acquisition.Acquirer(request.Metadata.Kind)
+	if !ok {
+		bm.EditMessage(chatID, messageID, "❌ 未配置处理该类型作品的下载管理服务")
+		return
+	}
+	title := services.FormatMetadataTitle(&request.Metadata)
+
+	switch {
+	case action == actionAcquireStart:
+		profiles, err := acquirer.GetQualityProfiles()
+		if err != nil {
+			bm.EditMessage(chatID, messageID, fmt.Sprintf("❌ 获取 %s 质量配置失败: %v", acquirer.Name(), err))
+			return
+		}
+		var rows [][]tgbotapi.InlineKeyboardButton
+		for _, profile := range profiles {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+				profile.Name, bm.callbackData(actionAcquireProfile, strconv.Itoa(request.ID), strconv.Itoa(profile.ID)))))
+		}
+		bm.editWithButtons(chatID, messageID, fmt.Sprintf("📥 将 %s 添加到 %s\n\n请选择质量配置:", title, acquirer.Name()), rows)
+
+	case action == actionAcquireProfile && len(ids) >= 2:
+		metadataProvider, ok := acquirer.(models.MetadataProfileProvider)
+		if !ok {
+			bm.sendRootFolderPicker(chatID, messageID, acquirer, request, ids[1], 0)
+			return
+		}
+		profiles, err := metadataProvider.GetMetadataProfiles()
+		if err != nil {
+			bm.EditMessage(chatID, messageID, fmt.Sprintf("❌ 获取 %s 元数据配置失败: %v", acquirer.Name(), err))
+			return
+		}
+		var rows [][]tgbotapi.InlineKeyboardButton
+		for _, profile := range profiles {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+				profile.Name, bm.callbackData(actionAcquireMeta, strconv.Itoa(request.ID), strconv.Itoa(ids[1]), strconv.Itoa(profile.ID)))))
+		}
+		bm.editWithButtons(chatID, messageID, fmt.Sprintf("📥 将 %s 添加到 %s\n\n请选择元数据配置:", title, acquirer.Name()), rows)
+
+	case action == actionAcquireMeta && len(ids) >= 3:
+		bm.sendRootFolderPicker(chatID, messageID, acquirer, request, ids[1], ids[2])
+
+	case action == actionAcquireFolder && len(ids) >= 4:
+		bm.EditMessage(chatID, messageID, fmt.Sprintf("📥 正在将 %s 添加到 %s，请稍候...", title, acquirer.Name()))
+		go bm.acquireRequest(chatID, messageID, acquirer, request, models.ArrAddOptions{QualityProfileID: ids[1], MetadataProfileID: ids[3]}, ids[2])
+
+	default:
+		log.Printf("无效的下载参数: %s %v", action, args)
+	}
+}
+
+// sendRootFolderPicker 列出根文件夹供选择，按钮中带上已选择的配置
+func (bm *Manager) sendRootFolderPicker(chatID int64, messageID int, acquirer models.MediaAcquirer, request *services.MediaRequest, qualityProfileID, metadataProfileID int) {
+	folders, err := acquirer.GetRootFolders()
+	if err != nil {
+		bm.EditMessage(chatID, messageID, fmt.Sprintf("❌ 获取 %s 根文件夹失败: %v", acquirer.Name(), err))
+		return
+	}
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, folder := range folders {
+		label := fmt.Sprintf("%s (剩余 %s)", folder.Path, util.FormatBytes(folder.FreeSpace))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(label, bm.callbackData(actionAcquireFolder,
+			strconv.Itoa(request.ID), strconv.Itoa(qualityProfileID), strconv.Itoa(folder.ID), strconv.Itoa(metadataProfileID)))))
+	}
+	title := services.FormatMetadataTitle(&request.Metadata)
+	bm.editWithButtons(chatID, messageID, fmt.Sprintf("📥 将 %s 添加到 %s\n\n请选择根文件夹:", title, acquirer.Name()), rows)
+}
+
+// acquireRequest 按选择的配置和根文件夹添加作品，并通知请求者
+func (bm *Manager) acquireRequest(chatID int64, messageID int, acquirer models.MediaAcquirer, request *services.MediaRequest, options models.ArrAddOptions, folderID int) {
+	folders, err := acquirer.GetRootFolders()
+	if err != nil {
+		bm.EditMessage(chatID, messageID, fmt.Sprintf("❌ 获取 %s 根文件夹失败: %v", acquirer.Name(), err))
+		return
+	}
+	for _, folder := range folders {
+		if folder.ID == folderID {
+			options.RootFolderPath = folder.Path
+		}
+	}
+	if options.RootFolderPath == "" {
+		bm.EditMessage(chatID, messageID, "❌ 根文件夹已不存在，请重新选择")
+		return
+	}
+
+	item, err := bm.acquisition.Add(request.ID, options)
+	if err != nil {
+		log.Printf("添加请求 #%d 失败: %v", request.ID, err)
+		bm.EditMessage(chatID, messageID, "❌ "+err.Error())
+		return
+	}
+
+	title := services.FormatMetadataTitle(&request.Metadata)
+	if item.Existed {
+		bm.EditMessage(chatID, messageID, fmt.Sprintf("ℹ️ %s 已在 %s 中，已关联到请求 #%d", title, acquirer.Name(), request.ID))
+		return
+	}
+	bm.EditMessage(chatID, messageID, fmt.Sprintf("✅ 已将 %s 添加到 %s 并开始搜索下载，使用 /queue 查看下载队列", title, acquirer.Name()))
+	for _, userID := range request.Requesters() {
+		bm.SendMessage(userID, fmt.Sprintf("📥 您请求的 %s 已开始下载，完成后会通知您", title))
+	}
+}
+
+// editWithButtons 编辑纯文本消息并设置按钮
+func (bm *Manager) editWithButtons(chatID int64, messageID int, text string, rows [][]tgbotapi.InlineKeyboardButton) {
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	if len(rows) > 0 {
+		menu := tgbotapi.NewInlineKeyboardMarkup(rows...)
+		edit.ReplyMarkup = &menu
+	}
+	if err := editBotMessage(bm.Bot, edit); err != nil {
+		log.Printf("编辑消息失败: %v", err)
+	}
+}
+
+// SendDownloadQueue 显示所有下载管理服务的下载队列
+func (bm *Manager) SendDownloadQueue(chatID int64, userID int64) {
+	if !bm.IsUserAdmin(userID) {
+		bm.SendMessage(chatID, "🚫 只有管理员可以查看下载队列")
+		return
+	}
+	if !bm.acquisition.HasAcquirers() {
+		bm.SendMessage(chatID, "❌ 未配置 Radarr、Sonarr 或 Readarr")
+		return
+	}
+
+	status, err := bm.Bot.Send(tgbotapi.NewMessage(chatID, "📥 正在获取下载队列，请稍候..."))
+	if err != nil {
+		log.Printf("发送下载队列状态消息失败: %v", err)
+		return
+	}
+
+	go func() {
+		bm.EditMessage(chatID, status.MessageID, formatDownloadQueues(bm.acquisition.Queues()))
+	}()
+}
+
+// formatDownloadQueues 格式化下载队列
+func formatDownloadQueues(queues []services.AcquirerQueue) string {
+	var sb strings.Builder
+	sb.WriteString("📥 下载队列\n")
+	for _, queue := range queues {
+		sb.WriteString(fmt.Sprintf("\n%s:\n", queue.Acquirer))
+		if queue.Err != nil {
+			sb.WriteString("❌ 获取失败: " + queue.Err.Error() + "\n")
+			continue
+		}
+		if len(queue.Items) == 0 {
+			sb.WriteString("暂无下载\n")
+			continue
+		}
+		for i, item := range queue.Items {
+			if i >= queueItemLimit {
+				sb.WriteString(fmt.Sprintf("… 还有 %d 个\n", len(queue.Items)-queueItemLimit))
+				break
+			}
+			line := fmt.Sprintf("• %s\n  %.1f%% · %s", item.Title, item.Progress()*100, item.Status)
+			if item.TimeLeft != "" {
+				line += " · 剩余 " + item.TimeLeft
+			}
+			if item.State != "" && item.State != "downloading" {
+				line += " · " + item.State
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
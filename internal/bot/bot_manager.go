@@ -1,6 +1,7 @@
 package bot
 
 import (
+	"errors"
 	"fmt"
 	"github.com/Heathcliff-third-space/MediaManager/internal/util"
 	"log"
@@ -17,11 +18,38 @@ import (
 	"github.com/Heathcliff-third-space/MediaManager/internal/services"
 )
 
+const (
+	// maxSearchResults 搜索结果消息中显示的最大条目数
+	maxSearchResults = 10
+	// maxSearchOverviewLength 搜索结果中概述的最大字符数
+	maxSearchOverviewLength = 120
+)
+
 // Manager 机器人管理器
 type Manager struct {
 	Bot                *tgbotapi.BotAPI
 	mediaServerManager *services.MediaServerManager
 	allowedUserIDs     map[int64]bool
+	adminUserIDs       map[int64]bool
+	downloadUserIDs    map[int64]bool
+	uploadLimit        int64 // 字节
+	uploadUserIDs      map[int64]bool
+	fileDownloadLimit  int64 // 字节，机器人可从 Telegram 下载的文件大小上限
+	callbacks          *callbackStore
+	pendingInputs      *pendingInputs
+	transcodeMonitor   *services.TranscodeMonitor
+	searchIndex        *services.SearchIndex
+	podcastNotifier    *services.PodcastNotifier
+	progressSync       *services.ProgressSync
+	requests           *services.RequestService
+	acquisition        *services.AcquisitionService
+	torrentMonitor     *services.TorrentMonitor
+	activeScans        sync.Map // 正在跟踪的媒体库扫描任务，避免重复触发
+	activeDownloads    sync.Map // 正在发送文件的聊天，每个聊天同时只处理一个下载
+	pendingUploads     sync.Map // 等待选择上传目标的文件，键为聊天ID
+	activeUploads      sync.Map // 正在上传文件的聊天，每个聊天同时只处理一个上传
+	requestCandidates  sync.Map // 等待用户选择的请求候选作品，键为聊天ID
+	stop               chan struct{}
 }
 
 // NewBotManager 创建新的机器人管理器
@@ -47,11 +75,73 @@ func NewBotManager(cfg *config.Config) (*Manager, error) {
 	}
 	log.Printf("允许访问的用户ID: %v", cfg.AllowedUserIDs)
 
-	return &Manager{
+	// 初始化管理员用户ID映射
+	adminUserIDs := make(map[int64]bool)
+	for _, id := range cfg.AdminUserIDs {
+		adminUserIDs[id] = true
+	}
+	log.Printf("管理员用户ID: %v", cfg.AdminUserIDs)
+	if len(adminUserIDs) == 0 {
+		log.Println("警告: 未设置 ADMIN_USER_IDS，所有管理操作均不可用")
+	}
+
+	// 初始化允许下载文件的用户ID映射
+	downloadUserIDs := make(map[int64]bool)
+	for _, id := range cfg.DownloadUserIDs {
+		downloadUserIDs[id] = true
+	}
+
+	// 初始化允许上传文件的用户ID映射
+	uploadUserIDs := make(map[int64]bool)
+	for _, id := range cfg.UploadUserIDs {
+		uploadUserIDs[id] = true
+	}
+
+	bm := &Manager{
 		Bot:                telegramBot,
 		mediaServerManager: mediaServerManager,
 		allowedUserIDs:     allowedUserIDs,
-	}, nil
+		adminUserIDs:       adminUserIDs,
+		downloadUserIDs:    downloadUserIDs,
+		uploadLimit:        int64(cfg.TelegramUploadLimit) << 20,
+		uploadUserIDs:      uploadUserIDs,
+		fileDownloadLimit:  int64(cfg.TelegramDownloadLimit) << 20,
+		callbacks:          newCallbackStore(),
+		pendingInputs:      newPendingInputs(),
+		stop:               make(chan struct{}),
+	}
+
+	// 初始化转码监控，超出限制时通知管理员
+	bm.transcodeMonitor = services.NewTranscodeMonitor(mediaServerManager, cfg, bm.notifyAdmins)
+	// 初始化本地搜索索引
+	bm.searchIndex = services.NewSearchIndex(mediaServerManager, cfg)
+	// 初始化播客新单集通知，发送给订阅的用户
+	bm.podcastNotifier = services.NewPodcastNotifier(mediaServerManager, cfg, bm.notifyUser)
+	// 初始化播放进度同步，同步结果和冲突通知管理员
+	bm.progressSync = services.NewProgressSync(mediaServerManager, cfg, bm.notifyAdmins)
+	// 初始化媒体请求，请求的作品入库后通知请求者
+	bm.requests = services.NewRequestService(mediaServerManager, cfg, bm.notifyUser)
+	// 初始化下载管理服务，导入完成时通知管理员和请求者
+	bm.acquisition = services.NewAcquisitionService(mediaServerManager, bm.requests, cfg, bm.notifyAdmins, bm.notifyUser)
+	bm.torrentMonitor = services.NewTorrentMonitor(mediaServerManager, cfg, bm.notifyTorrent)
+
+	return bm, nil
+}
+
+// Start 启动后台任务
+func (bm *Manager) Start() {
+	go bm.transcodeMonitor.Run(bm.stop)
+	go bm.searchIndex.Run(bm.stop)
+	go bm.podcastNotifier.Run(bm.stop)
+	go bm.progressSync.Run(bm.stop)
+	go bm.requests.Run(bm.stop)
+	go bm.acquisition.Run(bm.stop)
+	go bm.torrentMonitor.Run(bm.stop)
+}
+
+// Stop 停止后台任务
+func (bm *Manager) Stop() {
+	close(bm.stop)
 }
 
 // IsUserAllowed 检查用户是否有权限使用机器人
@@ -65,6 +155,22 @@ func (bm *Manager) IsUserAllowed(userID int64) bool {
 	return bm.allowedUserIDs[userID]
 }
 
+// IsUserAdmin 检查用户是否有权限执行管理操作
+func (bm *Manager) IsUserAdmin(userID int64) bool {
+	// 没有设置管理员用户ID时不允许任何人执行管理操作
+	return bm.adminUserIDs[userID]
+}
+
+// CanDownload 检查用户是否有权限将媒体文件下载到 Telegram，管理员始终允许
+func (bm *Manager) CanDownload(userID int64) bool {
+	return bm.IsUserAdmin(userID) || bm.downloadUserIDs[userID]
+}
+
+// CanUpload 检查用户是否有权限将文件上传到媒体库，管理员始终允许
+func (bm *Manager) CanUpload(userID int64) bool {
+	return bm.IsUserAdmin(userID) || bm.uploadUserIDs[userID]
+}
+
 // SendAccessDeniedMessage 发送访问拒绝消息
 func (bm *Manager) SendAccessDeniedMessage(chatID int64) {
 	msg := tgbotapi.NewMessage(chatID, "🚫 抱歉，您没有权限使用此机器人。")
@@ -83,7 +189,19 @@ func (bm *Manager) HandleMessage(message *tgbotapi.Message) {
 		return
 	}
 
-	switch strings.ToLower(message.Text) {
+	// 用户发送的文件作为上传到媒体库的请求处理
+	if upload := newPendingUpload(message); upload != nil {
+		bm.HandleUploadFile(message, upload)
+		return
+	}
+
+	// 命令可能带有参数或 @机器人名，只取命令本身进行匹配
+	command := strings.ToLower(message.Text)
+	if message.IsCommand() {
+		command = "/" + strings.ToLower(message.Command())
+	}
+
+	switch command {
 	case "/start", "/help":
 		bm.SendMainMenu(message.Chat.ID, 0)
 	case "/serverinfo":
@@ -93,10 +211,46 @@ func (bm *Manager) HandleMessage(message *tgbotapi.Message) {
 	case "/search":
 		bm.PromptForSearchTerm(message.Chat.ID, 0)
 	case "/libraries":
-		bm.SendLibrariesList(message.Chat.ID, 0)
+		bm.SendLibrariesList(message.Chat.ID, 0, message.From.ID)
 	case "/mystats":
 		bm.SendMyStats(message.Chat.ID, 0)
+	case "/nowplaying":
+		bm.SendNowPlaying(message.Chat.ID, 0, message.From.ID)
+	case "/transcodes":
+		bm.SendTranscodeReport(message.Chat.ID, message.From.ID, message.CommandArguments())
+	case "/continue":
+		bm.SendContinueList(message.Chat.ID, 0)
+	case "/people":
+		bm.SendPeopleSearch(message.Chat.ID, message.CommandArguments())
+	case "/episodes":
+		bm.SendEpisodeSearch(message.Chat.ID, message.CommandArguments())
+	case "/podcasts":
+		bm.SendPodcasts(message.Chat.ID, 0, message.From.ID)
+	case "/addpodcast":
+		if feedURL := message.CommandArguments(); feedURL != "" {
+			bm.AddPodcast(message.Chat.ID, message.From.ID, feedURL)
+		} else {
+			bm.PromptForPodcastFeed(message.Chat.ID, message.From.ID)
+		}
+	case "/collections":
+		bm.SendCollections(message.Chat.ID, 0)
+	case "/request":
+		bm.SendRequestCandidates(message.Chat.ID, message.CommandArguments())
+	case "/requests":
+		bm.SendRequests(message.Chat.ID, message.From.ID)
+	case "/queue":
+		bm.SendDownloadQueue(message.Chat.ID, message.From.ID)
+	case "/downloads":
+		bm.SendDownloads(message.Chat.ID, 0, message.From.ID)
+	case "/syncprogress":
+		bm.SendProgressSync(message.Chat.ID, message.From.ID, message.CommandArguments())
 	default:
+		// 检查是否有等待用户输入的操作
+		if handler, ok := bm.pendingInputs.take(message.Chat.ID); ok {
+			handler(message)
+			return
+		}
+
 		// 检查是否是搜索查询
 		log.Printf("检查是否是搜索查询: ReplyToMessage=%v, Text=%s", message.ReplyToMessage, message.Text)
 		if message.ReplyToMessage != nil {
@@ -132,6 +286,10 @@ func (bm *Manager) HandleCallbackQuery(callback *tgbotapi.CallbackQuery) {
 		})
 	case "search_books":
 		bm.PromptForSearchTerm(callback.Message.Chat.ID, callback.Message.MessageID)
+	case "search_people":
+		bm.PromptForPeopleSearch(callback.Message.Chat.ID)
+	case "search_episodes":
+		bm.PromptForEpisodeSearch(callback.Message.Chat.ID)
 	case "users_list":
 		executeWithLoadingStatus(bm.Bot, callback.Message.Chat.ID, callback.Message.MessageID, "👥 正在获取用户信息，请稍候...", func() {
 			bm.SendUsersInfo(callback.Message.Chat.ID, callback.Message.MessageID)
@@ -142,10 +300,80 @@ func (bm *Manager) HandleCallbackQuery(callback *tgbotapi.CallbackQuery) {
 		})
 	case "libraries_list":
 		executeWithLoadingStatus(bm.Bot, callback.Message.Chat.ID, callback.Message.MessageID, "📚 正在获取媒体库信息，请稍候...", func() {
-			bm.SendLibrariesList(callback.Message.Chat.ID, callback.Message.MessageID)
+			bm.SendLibrariesList(callback.Message.Chat.ID, callback.Message.MessageID, callback.From.ID)
 		})
+	case "now_playing":
+		executeWithLoadingStatus(bm.Bot, callback.Message.Chat.ID, callback.Message.MessageID, "▶️ 正在获取播放会话，请稍候...", func() {
+			bm.SendNowPlaying(callback.Message.Chat.ID, callback.Message.MessageID, callback.From.ID)
+		})
+	case "continue_list":
+		executeWithLoadingStatus(bm.Bot, callback.Message.Chat.ID, callback.Message.MessageID, "⏯ 正在获取继续播放列表，请稍候...", func() {
+			bm.SendContinueList(callback.Message.Chat.ID, callback.Message.MessageID)
+		})
+	case "podcasts_list":
+		executeWithLoadingStatus(bm.Bot, callback.Message.Chat.ID, callback.Message.MessageID, "🎙 正在获取播客列表，请稍候...", func() {
+			bm.SendPodcasts(callback.Message.Chat.ID, callback.Message.MessageID, callback.From.ID)
+		})
+	case "podcast_recent":
+		executeWithLoadingStatus(bm.Bot, callback.Message.Chat.ID, callback.Message.MessageID, "🆕 正在获取最新单集，请稍候...", func() {
+			bm.SendRecentEpisodes(callback.Message.Chat.ID, callback.Message.MessageID)
+		})
+	case "collections_list":
+		executeWithLoadingStatus(bm.Bot, callback.Message.Chat.ID, callback.Message.MessageID, "🗂 正在获取合集列表，请稍候...", func() {
+			bm.SendCollections(callback.Message.Chat.ID, callback.Message.MessageID)
+		})
+	case "downloads_list":
+		executeWithLoadingStatus(bm.Bot, callback.Message.Chat.ID, callback.Message.MessageID, "⬇️ 正在获取下载客户端中的种子，请稍候...", func() {
+			bm.SendDownloads(callback.Message.Chat.ID, callback.Message.MessageID, callback.From.ID)
+		})
+	case "podcast_add":
+		bm.PromptForPodcastFeed(callback.Message.Chat.ID, callback.From.ID)
 	case "help":
 		bm.EditHelpMessage(callback.Message.Chat.ID, callback.Message.MessageID)
+	default:
+		bm.handleActionCallback(callback)
+	}
+}
+
+// handleActionCallback 处理带参数的回调查询
+func (bm *Manager) handleActionCallback(callback *tgbotapi.CallbackQuery) {
+	action, args := bm.parseCallbackData(callback.Data)
+
+	switch action {
+	case actionStopSession, actionMessageSession:
+		bm.handleSessionAction(callback, action, args)
+	case actionItemDetails:
+		bm.handleItemAction(callback, args)
+	case actionScanLibrary, actionRefreshLibrary:
+		bm.handleLibraryScanAction(callback, action, args)
+	case actionBrowseLibrary, actionBrowseGenre:
+		bm.handleBrowseAction(callback, action, args)
+	case actionEntityItems:
+		bm.handleEntityAction(callback, args)
+	case actionSeriesSeasons, actionSeasonEpisodes:
+		bm.handleSeriesAction(callback, action, args)
+	case actionDownloadItem:
+		bm.handleDownloadAction(callback, args)
+	case actionPodcastDetails, actionPodcastSubscribe, actionPodcastUnsubscribe,
+		actionPodcastCheckNew, actionPodcastFeed, actionPodcastDownload:
+		bm.handlePodcastAction(callback, action, args)
+	case actionUploadTarget, actionUploadMeta, actionUploadCancel:
+		bm.handleUploadAction(callback, action, args)
+	case actionMarkPlayed, actionMarkUnplayed, actionSetPosition:
+		bm.handlePlayStateAction(callback, action, args)
+	case actionCollectionItems, actionCollectionShare, actionCollectionPicker,
+		actionCollectionAdd, actionCollectionCreate:
+		bm.handleCollectionAction(callback, action, args)
+	case actionRequestPick, actionRequestApprove, actionRequestDeny:
+		bm.handleRequestAction(callback, action, args)
+	case actionAcquireStart, actionAcquireProfile, actionAcquireMeta, actionAcquireFolder:
+		bm.handleAcquireAction(callback, action, args)
+	case actionTorrentDetails, actionTorrentPause, actionTorrentResume, actionTorrentDelete, actionTorrentDeleteConfirm:
+		bm.handleTorrentAction(callback, action, args)
+	case actionProgressSyncApply:
+		bm.handleProgressSyncAction(callback)
+	default:
+		log.Printf("未知的回调数据: %s", callback.Data)
 	}
 }
 
@@ -198,6 +426,7 @@ func (bm *Manager) SendServerInfo(chatID int64, messageID int) {
 			text += fmt.Sprintf("⚙️ 架构: `%s`\n", info.Arch)
 			text += "\n"
 		}
+		text += formatSearchIndexStatus(bm.searchIndex.Status(), time.Now())
 	}
 
 	if messageID > 0 {
@@ -226,9 +455,10 @@ func (bm *Manager) EditServerInfo(chatID int64, messageID int) {
 }
 
 // SendLibrariesList 发送媒体库列表
-func (bm *Manager) SendLibrariesList(chatID int64, messageID int) {
+func (bm *Manager) SendLibrariesList(chatID int64, messageID int, userID int64) {
 	allServers := bm.mediaServerManager.GetAllServers()
 	var text string
+	libraryResults := make(map[services.MediaServerType][]models.LibraryInfo)
 
 	if len(allServers) == 0 {
 		text = "📭 没有找到媒体服务器"
@@ -267,6 +497,7 @@ func (bm *Manager) SendLibrariesList(chatID int64, messageID int) {
 		}
 
 		wg.Wait()
+		libraryResults = results
 
 		// 按照服务器类型顺序输出结果
 		serverTypes := bm.mediaServerManager.GetServerTypes()
@@ -297,10 +528,13 @@ func (bm *Manager) SendLibrariesList(chatID int64, messageID int) {
 		}
 	}
 
+	// 每个媒体库可以打开浏览，管理员还可以触发扫描和元数据刷新
+	menu := CreateLibrariesMenu()
+	menu.InlineKeyboard = append(bm.libraryButtons(libraryResults, bm.IsUserAdmin(userID)), menu.InlineKeyboard...)
+
 	if messageID > 0 {
 		edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
 		edit.ParseMode = "Markdown"
-		menu := CreateLibrariesMenu()
 		edit.ReplyMarkup = &menu
 		err := editBotMessage(bm.Bot, edit)
 		if err != nil {
@@ -309,24 +543,34 @@ func (bm *Manager) SendLibrariesList(chatID int64, messageID int) {
 	} else {
 		msg := tgbotapi.NewMessage(chatID, text)
 		msg.ParseMode = "Markdown"
-		msg.ReplyMarkup = CreateLibrariesMenu()
+		msg.ReplyMarkup = menu
 		err := sendBotMessage(bm.Bot, msg)
 		if err != nil {
 			log.Printf("发送媒体库列表消息失败: %v", err)
 		}
 	}
+
+	// 只在通过命令查看时发送各服务器的媒体库饼图，按钮刷新和返回时不重复发送
+	if messageID > 0 {
+		return
+	}
+	for _, serverType := range bm.mediaServerManager.GetServerTypes() {
+		if libraries, ok := libraryResults[serverType]; ok {
+			bm.sendLibraryChart(chatID, serverType, libraries)
+		}
+	}
 }
 
 // EditLibrariesList 编辑媒体库列表
-func (bm *Manager) EditLibrariesList(chatID int64, messageID int) {
-	bm.SendLibrariesList(chatID, messageID)
+func (bm *Manager) EditLibrariesList(chatID int64, messageID int, userID int64) {
+	bm.SendLibrariesList(chatID, messageID, userID)
 }
 
 // PromptForSearchTerm 提示用户输入搜索词
 func (bm *Manager) PromptForSearchTerm(chatID int64, messageID int) {
 	// 如果已经有消息ID，则编辑现有消息
 	if messageID > 0 {
-		edit := tgbotapi.NewEditMessageText(chatID, messageID, "🔍 请输入您要搜索的媒体名称、作者或其他关键词：")
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, "🔍 请输入您要搜索的媒体名称、作者或其他关键词：\n\n"+services.SearchSyntaxHelp)
 		menu := CreateSearchMenu()
 		edit.ReplyMarkup = &menu
 		err := editBotMessage(bm.Bot, edit)
@@ -335,7 +579,7 @@ func (bm *Manager) PromptForSearchTerm(chatID int64, messageID int) {
 		}
 	} else {
 		// 否则发送新消息
-		msg := tgbotapi.NewMessage(chatID, "🔍 请输入您要搜索的媒体名称、作者或其他关键词：")
+		msg := tgbotapi.NewMessage(chatID, "🔍 请输入您要搜索的媒体名称、作者或其他关键词：\n\n"+services.SearchSyntaxHelp)
 		menu := CreateSearchMenu()
 		msg.ReplyMarkup = &menu
 		err := sendBotMessage(bm.Bot, msg)
@@ -350,22 +594,27 @@ func (bm *Manager) PerformBookSearch(chatID int64, searchTerm string) {
 	// 添加调试日志
 	log.Printf("执行媒体搜索: %s", searchTerm)
 
-	// 在所有服务器中搜索
-	searchResults, err := bm.mediaServerManager.SearchAcrossServers(searchTerm)
+	// 解析搜索语法中的过滤条件
+	query, err := services.ParseSearchQuery(searchTerm)
 	if err != nil {
-		log.Printf("搜索出错: %v", err)
-		response := fmt.Sprintf("❌ 搜索出错: %v", err)
-		msg := tgbotapi.NewMessage(chatID, response)
-		msg.ReplyMarkup = CreateMainMenu()
-		err := sendBotMessage(bm.Bot, msg)
-		if err != nil {
-			log.Printf("发送搜索错误消息失败: %v", err)
+		response := fmt.Sprintf("❌ 搜索语法错误: %v", err)
+		var queryErr *services.SearchQueryError
+		if errors.As(err, &queryErr) && queryErr.Hint != "" {
+			response += "\n💡 " + queryErr.Hint
 		}
+		response += "\n\n" + services.SearchSyntaxHelp
+		bm.SendMessage(chatID, response)
 		return
 	}
 
+	// 优先使用本地索引搜索，索引过期的服务器使用实时搜索
+	searchResults, errs := bm.searchIndex.Search(query)
+	for serverType, err := range errs {
+		log.Printf("搜索 %s 出错: %v", serverType, err)
+	}
+
 	// 格式化搜索结果
-	response := bm.FormatSearchResults(searchTerm, searchResults)
+	response := bm.FormatSearchResults(searchTerm, searchResults) + formatSearchErrors(errs)
 
 	// 发送或编辑消息
 	msg := tgbotapi.NewMessage(chatID, response)
@@ -377,55 +626,71 @@ func (bm *Manager) PerformBookSearch(chatID int64, searchTerm string) {
 	}
 }
 
-// FormatSearchResults 格式化搜索结果
+// FormatSearchResults 格式化搜索结果，合并各服务器上的相同项目并按相关度排序
 func (bm *Manager) FormatSearchResults(searchTerm string, searchResults map[services.MediaServerType][]models.SearchResult) string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("🔎 搜索 \"%s\" 的结果:\n\n", searchTerm))
 
-	if len(searchResults) == 0 {
+	// 过滤条件不参与标题相似度计算
+	searchText := searchTerm
+	if query, err := services.ParseSearchQuery(searchTerm); err == nil {
+		searchText = query.Text
+	}
+	merged := services.MergeSearchResults(searchText, searchResults)
+
+	if len(merged) == 0 {
 		sb.WriteString("未找到相关媒体。\n")
 		return sb.String()
 	}
 
-	for serverType, results := range searchResults {
-		if len(results) > 0 {
-			sb.WriteString(fmt.Sprintf("*%s 服务器*:\n", strings.Title(string(serverType))))
-			for i, result := range results {
-				if i >= 5 { // 限制每个服务器显示前5个结果
-					sb.WriteString(fmt.Sprintf("\n+ 还有 %d 个更多结果...\n", len(results)-5))
-					break
-				}
-				sb.WriteString(fmt.Sprintf("• **%s**\n", result.Title))
-				// 根据媒体类型添加图标
-				mediaTypeIcon := util.GetMediaTypeIcon(result.Type)
-				sb.WriteString(fmt.Sprintf("  %s 类型: %s\n", mediaTypeIcon, result.Type))
-				sb.WriteString(fmt.Sprintf("  📁 媒体库: %s\n", result.Library))
-				// 添加年份信息
-				if result.Year > 0 {
-					sb.WriteString(fmt.Sprintf("  📅 年份: %d\n", result.Year))
-				}
-				// 添加分类信息
-				if len(result.Genres) > 0 {
-					sb.WriteString(fmt.Sprintf("  🏷️ 分类: %s\n", strings.Join(result.Genres, ", ")))
-				}
-				// 添加概述信息
-				if result.Overview != "" {
-					sb.WriteString(fmt.Sprintf("  📝 概述: %s\n", result.Overview))
-				}
-				// 添加大小信息
-				if result.Size > 0 {
-					sb.WriteString(fmt.Sprintf("  💾 大小: %s\n", util.FormatBytes(result.Size)))
-				}
-				// 添加添加时间信息
-				if result.AddedAt > 0 {
-					// 将毫秒时间戳转换为可读格式
-					addedAtTime := time.Unix(result.AddedAt/1000, 0)
-					sb.WriteString(fmt.Sprintf("  ⏰ 添加时间: %s\n", addedAtTime.Format("2006-01-02 15:04:05")))
-				}
-				sb.WriteString("\n")
+	for i, item := range merged {
+		if i >= maxSearchResults {
+			sb.WriteString(fmt.Sprintf("+ 还有 %d 个更多结果...\n", len(merged)-maxSearchResults))
+			break
+		}
+		result := item.Result
+		sb.WriteString(fmt.Sprintf("%d. *%s*\n", i+1, util.EscapeMarkdown(result.Title)))
+		if result.Subtitle != "" {
+			sb.WriteString(fmt.Sprintf("  _%s_\n", util.EscapeMarkdown(result.Subtitle)))
+		}
+		// 根据媒体类型添加图标
+		mediaTypeIcon := util.GetMediaTypeIcon(result.Type)
+		sb.WriteString(fmt.Sprintf("  %s 类型: %s\n", mediaTypeIcon, result.Type))
+		// 有声书的作者、演播者、系列和时长
+		sb.WriteString(formatBookFields(&result, "  "))
+		// 列出拥有该项目的服务器及所在媒体库
+		locations := make([]string, 0, len(item.Entries))
+		for _, entry := range item.Entries {
+			location := strings.Title(string(entry.Server))
+			if entry.Result.Library != "" {
+				location += "/" + entry.Result.Library
 			}
-			sb.WriteString("\n")
+			locations = append(locations, util.EscapeMarkdown(location))
+		}
+		sb.WriteString(fmt.Sprintf("  🖥 位置: %s\n", strings.Join(locations, ", ")))
+		// 添加年份信息
+		if result.Year > 0 {
+			sb.WriteString(fmt.Sprintf("  📅 年份: %d\n", result.Year))
+		}
+		// 添加分类信息
+		if len(result.Genres) > 0 {
+			sb.WriteString(fmt.Sprintf("  🏷️ 分类: %s\n", util.EscapeMarkdown(strings.Join(result.Genres, ", "))))
+		}
+		// 添加概述信息
+		if result.Overview != "" {
+			sb.WriteString(fmt.Sprintf("  📝 概述: %s\n", util.EscapeMarkdown(truncateLabel(result.Overview, maxSearchOverviewLength))))
+		}
+		// 添加大小信息
+		if result.Size > 0 {
+			sb.WriteString(fmt.Sprintf("  💾 大小: %s\n", util.FormatBytes(result.Size)))
 		}
+		// 添加添加时间信息
+		if result.AddedAt > 0 {
+			// 将毫秒时间戳转换为可读格式
+			addedAtTime := time.Unix(result.AddedAt/1000, 0)
+			sb.WriteString(fmt.Sprintf("  ⏰ 添加时间: %s\n", addedAtTime.Format("2006-01-02 15:04:05")))
+		}
+		sb.WriteString("\n")
 	}
 
 	return sb.String()
@@ -499,19 +764,22 @@ func (bm *Manager) SendUsersInfo(chatID int64, messageID int) {
 func (bm *Manager) SendMyStats(chatID int64, messageID int) {
 	allServers := bm.mediaServerManager.GetAllServers()
 	var text string
+	now := time.Now()
+	serverStats := make(map[services.MediaServerType]*models.PlaybackStats)
 
 	if len(allServers) == 0 {
 		text = "📭 没有找到媒体服务器"
 	} else {
 		text = "*📈 个人统计信息*:\n\n"
-		for serverType, server := range allServers {
+		for _, serverType := range bm.mediaServerManager.GetServerTypes() {
+			server := allServers[serverType]
 			user, err := server.GetCurrentUser()
 			if err != nil {
 				text += fmt.Sprintf("*%s 服务器*:\n❌ 获取个人信息失败\n\n", strings.Title(string(serverType)))
 				continue
 			}
 
-			stats, err := server.GetListeningStats()
+			stats, err := server.GetPlaybackStats()
 			if err != nil {
 				text += fmt.Sprintf("*%s 服务器*:\n❌ 获取统计信息失败\n\n", strings.Title(string(serverType)))
 				continue
@@ -536,10 +804,9 @@ func (bm *Manager) SendMyStats(chatID int64, messageID int) {
 			text += fmt.Sprintf("   👀 最后在线: %s\n", lastSeen)
 
 			// 显示收听/观看统计
-			if totalTime, ok := stats["TotalRecordCount"]; ok {
-				text += fmt.Sprintf("   📊 统计: %v 个项目\n", totalTime)
-			}
+			text += formatPlaybackStats(stats, now)
 			text += "\n"
+			serverStats[serverType] = stats
 		}
 	}
 
@@ -561,6 +828,16 @@ func (bm *Manager) SendMyStats(chatID int64, messageID int) {
 			log.Printf("发送个人统计消息失败: %v", err)
 		}
 	}
+
+	// 只在通过命令查看时发送统计图表，按钮刷新时不重复发送
+	if messageID > 0 {
+		return
+	}
+	for _, serverType := range bm.mediaServerManager.GetServerTypes() {
+		if stats, ok := serverStats[serverType]; ok {
+			bm.sendStatsCharts(chatID, serverType, stats, now)
+		}
+	}
 }
 
 // EditHelpMessage 编辑帮助信息
@@ -574,8 +851,22 @@ func (bm *Manager) EditHelpMessage(chatID int64, messageID int) {
 • /libraries - 获取所有服务器的媒体库列表
 • /search - 搜索所有服务器的媒体
 • /mystats - 获取所有服务器的个人统计信息
+• /nowplaying - 查看所有服务器正在播放的会话
+• /transcodes [天数] - 查看转码负载报告（管理员）
+• /continue - 继续观看/收听未播放完的项目
+• /people <名称> - 按人物、作者、演播者、系列或标签搜索
+• /episodes <名称> - 只搜索剧集单集，按所属剧集分组
+• /podcasts - 查看播客和最新单集，订阅新单集通知
+• /addpodcast <RSS地址> - 通过 RSS 地址添加播客（管理员）
+• /collections - 查看合集和播放列表，以消息分享
+• /syncprogress [apply] - 在服务器之间同步同一作品的收听进度，默认只试运行（管理员）
+• /request [名称] - 请求添加电影、剧集或书籍，已入库时直接显示
+• /requests - 查看我的请求，管理员可查看所有请求
+• /queue - 查看 Radarr、Sonarr 和 Readarr 的下载队列（管理员）
+• /downloads - 查看下载客户端中的种子，暂停、继续或删除（管理员）
 • /help - 显示此帮助信息
 
+直接发送文件可将其上传到媒体库（需要上传权限）。
 或者使用下方的菜单按钮进行操作。
 `
 	edit := tgbotapi.NewEditMessageText(chatID, messageID, helpText)
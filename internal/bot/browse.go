@@ -0,0 +1,262 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+// 媒体库浏览相关的回调动作
+const (
+	actionBrowseLibrary = "lb"
+	actionBrowseGenre   = "lb_genre"
+)
+
+const (
+	// browsePageSize 浏览媒体库时每页显示的项目数量
+	browsePageSize = 10
+	// browseButtonsPerRow 每行显示的项目按钮数量
+	browseButtonsPerRow = 5
+)
+
+// browseSortNames 排序方式的显示名称
+var browseSortNames = map[string]string{
+	models.ItemSortDateAdded: "📅 添加时间",
+	models.ItemSortName:      "🔤 名称",
+	models.ItemSortYear:      "🗓 年份",
+}
+
+// browseFilterNames 过滤方式的显示名称
+var browseFilterNames = map[string]string{
+	models.ItemFilterNone:       "全部",
+	models.ItemFilterUnplayed:   "👀 未看",
+	models.ItemFilterInProgress: "⏯ 未完成",
+	models.ItemFilterGenre:      "🏷 分类",
+}
+
+// defaultItemQuery 返回默认的浏览条件：按添加时间倒序
+func defaultItemQuery() models.ItemQuery {
+	return models.ItemQuery{
+		PageSize:   browsePageSize,
+		SortBy:     models.ItemSortDateAdded,
+		Descending: true,
+	}
+}
+
+// browseArgs 构造浏览媒体库的回调参数，分类放在最后以免其中的分隔符影响解析
+func browseArgs(serverType services.MediaServerType, libraryID string, query models.ItemQuery) []string {
+	desc := "0"
+	if query.Descending {
+		desc = "1"
+	}
+	return []string{string(serverType), libraryID, strconv.Itoa(query.Page), query.SortBy, desc, query.Filter, query.Genre}
+}
+
+// browseCallbackData 构造浏览媒体库的回调数据
+func (bm *Manager) browseCallbackData(serverType services.MediaServerType, libraryID string, query models.ItemQuery) string {
+	return bm.callbackData(actionBrowseLibrary, browseArgs(serverType, libraryID, query)...)
+}
+
+// parseBrowseArgs 解析浏览媒体库的回调参数
+func parseBrowseArgs(args []string) (services.MediaServerType, string, models.ItemQuery, bool) {
+	if len(args) < 6 {
+		return "", "", models.ItemQuery{}, false
+	}
+	page, err := strconv.Atoi(args[2])
+	if err != nil || page < 0 {
+		return "", "", models.ItemQuery{}, false
+	}
+	query := models.ItemQuery{
+		Page:       page,
+		PageSize:   browsePageSize,
+		SortBy:     args[3],
+		Descending: args[4] == "1",
+		Filter:     args[5],
+	}
+	if len(args) > 6 {
+		query.Genre = strings.Join(args[6:], callbackSeparator)
+	}
+	return services.MediaServerType(args[0]), args[1], query, true
+}
+
+// handleBrowseAction 处理浏览媒体库和选择分类的回调
+func (bm *Manager) handleBrowseAction(callback *tgbotapi.CallbackQuery, action string, args []string) {
+	chatID := callback.Message.Chat.ID
+	serverType, libraryID, query, ok := parseBrowseArgs(args)
+	if !ok {
+		log.Printf("无效的媒体库浏览参数: %v", args)
+		return
+	}
+
+	if action == actionBrowseGenre {
+		bm.promptForInput(chatID, "🏷 请输入要筛选的分类名称：", func(message *tgbotapi.Message) {
+			query.Page = 0
+			query.Filter = models.ItemFilterGenre
+			query.Genre = strings.TrimSpace(message.Text)
+			bm.SendLibraryBrowser(chatID, 0, serverType, libraryID, query)
+		})
+		return
+	}
+
+	bm.SendLibraryBrowser(chatID, callback.Message.MessageID, serverType, libraryID, query)
+}
+
+// SendLibraryBrowser 发送媒体库的分页浏览视图
+func (bm *Manager) SendLibraryBrowser(chatID int64, messageID int, serverType services.MediaServerType, libraryID string, query models.ItemQuery) {
+	server, err := bm.mediaServerManager.GetServer(serverType)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+
+	page, err := server.ListItems(libraryID, query)
+	if err != nil {
+		log.Printf("获取 %s 媒体库 %s 项目失败: %v", serverType, libraryID, err)
+		bm.SendMessage(chatID, "❌ 获取媒体库项目失败: "+err.Error())
+		return
+	}
+
+	libraryName := libraryID
+	if libraries, err := server.GetLibraries(); err == nil {
+		for _, lib := range libraries {
+			if lib.ID == libraryID {
+				libraryName = lib.Name
+				break
+			}
+		}
+	}
+
+	totalPages := (page.Total + query.PageSize - 1) / query.PageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📂 *%s · %s*\n", strings.Title(string(serverType)), util.EscapeMarkdown(libraryName)))
+
+	direction := "↑"
+	if query.Descending {
+		direction = "↓"
+	}
+	filterName := browseFilterNames[query.Filter]
+	if query.Filter == models.ItemFilterGenre {
+		filterName = fmt.Sprintf("%s: %s", filterName, util.EscapeMarkdown(query.Genre))
+	}
+	sb.WriteString(fmt.Sprintf("排序: %s %s · 过滤: %s\n", browseSortNames[query.SortBy], direction, filterName))
+	sb.WriteString(fmt.Sprintf("第 %d/%d 页，共 %d 项\n\n", query.Page+1, totalPages, page.Total))
+
+	if len(page.Items) == 0 {
+		sb.WriteString("📭 没有符合条件的项目\n")
+	}
+
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, item := range page.Items {
+		index := query.Page*query.PageSize + i + 1
+		sb.WriteString(fmt.Sprintf("%d. %s %s", index, util.GetMediaTypeIcon(item.Type), util.EscapeMarkdown(item.Title)))
+		if item.Year > 0 {
+			sb.WriteString(fmt.Sprintf(" (%d)", item.Year))
+		}
+		if item.Author != "" {
+			sb.WriteString(fmt.Sprintf(" - %s", util.EscapeMarkdown(item.Author)))
+		}
+		sb.WriteString("\n")
+
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(strconv.Itoa(index),
+			bm.callbackData(actionItemDetails, string(serverType), item.ID)))
+		if len(row) == browseButtonsPerRow {
+			buttons = append(buttons, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		buttons = append(buttons, row)
+	}
+
+	// 排序按钮，再次点击当前排序方式时切换升降序
+	var sortRow []tgbotapi.InlineKeyboardButton
+	for _, sortBy := range []string{models.ItemSortDateAdded, models.ItemSortName, models.ItemSortYear} {
+		next := query
+		next.Page = 0
+		next.SortBy = sortBy
+		label := browseSortNames[sortBy]
+		if sortBy == query.SortBy {
+			next.Descending = !query.Descending
+			label += " " + direction
+		} else {
+			// 名称默认升序，其他默认降序
+			next.Descending = sortBy != models.ItemSortName
+		}
+		sortRow = append(sortRow, tgbotapi.NewInlineKeyboardButtonData(label, bm.browseCallbackData(serverType, libraryID, next)))
+	}
+	buttons = append(buttons, sortRow)
+
+	// 过滤按钮
+	var filterRow []tgbotapi.InlineKeyboardButton
+	for _, filter := range []string{models.ItemFilterNone, models.ItemFilterUnplayed, models.ItemFilterInProgress} {
+		next := query
+		next.Page = 0
+		next.Filter = filter
+		next.Genre = ""
+		label := browseFilterNames[filter]
+		if filter == query.Filter {
+			label = "✅ " + label
+		}
+		filterRow = append(filterRow, tgbotapi.NewInlineKeyboardButtonData(label, bm.browseCallbackData(serverType, libraryID, next)))
+	}
+	genreLabel := browseFilterNames[models.ItemFilterGenre]
+	if query.Filter == models.ItemFilterGenre {
+		genreLabel = "✅ " + genreLabel
+	}
+	genreQuery := query
+	genreQuery.Genre = ""
+	filterRow = append(filterRow, tgbotapi.NewInlineKeyboardButtonData(genreLabel,
+		bm.callbackData(actionBrowseGenre, browseArgs(serverType, libraryID, genreQuery)...)))
+	buttons = append(buttons, filterRow)
+
+	// 翻页按钮
+	var navRow []tgbotapi.InlineKeyboardButton
+	if query.Page > 0 {
+		prev := query
+		prev.Page--
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("⬅ 上一页", bm.browseCallbackData(serverType, libraryID, prev)))
+	}
+	if query.Page+1 < totalPages {
+		next := query
+		next.Page++
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("下一页 ➡", bm.browseCallbackData(serverType, libraryID, next)))
+	}
+	if len(navRow) > 0 {
+		buttons = append(buttons, navRow)
+	}
+
+	buttons = append(buttons, []tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回媒体库", "libraries_list"),
+	})
+	menu := tgbotapi.NewInlineKeyboardMarkup(buttons...)
+
+	if messageID > 0 {
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, sb.String())
+		edit.ParseMode = "Markdown"
+		edit.ReplyMarkup = &menu
+		err := editBotMessage(bm.Bot, edit)
+		if err != nil {
+			log.Printf("编辑媒体库浏览消息失败: %v", err)
+		}
+	} else {
+		msg := tgbotapi.NewMessage(chatID, sb.String())
+		msg.ParseMode = "Markdown"
+		msg.ReplyMarkup = menu
+		err := sendBotMessage(bm.Bot, msg)
+		if err != nil {
+			log.Printf("发送媒体库浏览消息失败: %v", err)
+		}
+	}
+}
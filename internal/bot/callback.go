@@ -0,0 +1,82 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	// callbackSeparator 回调数据中动作与参数之间的分隔符
+	callbackSeparator = ":"
+	// maxCallbackDataLen Telegram 回调数据的最大长度
+	maxCallbackDataLen = 64
+	// callbackTokenPrefix 超长回调数据被替换成的令牌前缀
+	callbackTokenPrefix = "~"
+	// maxStoredCallbacks 最多保存的超长回调数据数量
+	maxStoredCallbacks = 1024
+)
+
+// callbackStore 保存超出 Telegram 长度限制的回调数据
+type callbackStore struct {
+	mu    sync.Mutex
+	next  int
+	data  map[string]string
+	order []string
+}
+
+// newCallbackStore 创建回调数据存储
+func newCallbackStore() *callbackStore {
+	return &callbackStore{
+		data: make(map[string]string),
+	}
+}
+
+// put 保存回调数据并返回短令牌
+func (s *callbackStore) put(data string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.next++
+	token := fmt.Sprintf("%s%d", callbackTokenPrefix, s.next)
+	s.data[token] = data
+	s.order = append(s.order, token)
+
+	// 超出容量时淘汰最早的数据
+	if len(s.order) > maxStoredCallbacks {
+		delete(s.data, s.order[0])
+		s.order = s.order[1:]
+	}
+
+	return token
+}
+
+// get 根据短令牌取回回调数据
+func (s *callbackStore) get(token string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.data[token]
+	return data, ok
+}
+
+// callbackData 构造带参数的回调数据，超长时自动替换为短令牌
+func (bm *Manager) callbackData(action string, args ...string) string {
+	data := strings.Join(append([]string{action}, args...), callbackSeparator)
+	if len(data) <= maxCallbackDataLen {
+		return data
+	}
+	return bm.callbacks.put(data)
+}
+
+// parseCallbackData 解析回调数据，返回动作和参数
+func (bm *Manager) parseCallbackData(data string) (string, []string) {
+	if strings.HasPrefix(data, callbackTokenPrefix) {
+		if stored, ok := bm.callbacks.get(data); ok {
+			data = stored
+		}
+	}
+
+	parts := strings.Split(data, callbackSeparator)
+	return parts[0], parts[1:]
+}
@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/chart"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+)
+
+// chartDays 柱状图中显示的天数
+const chartDays = 14
+
+// heatmapWeekdays 热力图的行顺序，从周一开始，值为 time.Weekday
+var heatmapWeekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+// sendPhoto 发送 PNG 图片
+func (bm *Manager) sendPhoto(chatID int64, fileName string, data []byte, caption string) {
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{Name: fileName, Bytes: data})
+	photo.Caption = caption
+	err := sendBotMessage(bm.Bot, photo)
+	if err != nil {
+		log.Printf("发送图片 %s 失败: %v", fileName, err)
+	}
+}
+
+// sendStatsCharts 发送播放统计的每日柱状图和星期/小时热力图
+func (bm *Manager) sendStatsCharts(chatID int64, serverType services.MediaServerType, stats *models.PlaybackStats, now time.Time) {
+	serverName := strings.Title(string(serverType))
+
+	dayTotals := stats.RecentDays(now, chartDays)
+	labels := make([]string, len(dayTotals))
+	values := make([]float64, len(dayTotals))
+	var total float64
+	for i, day := range dayTotals {
+		labels[i] = day.Date[5:]
+		values[i] = day.Seconds / 3600
+		total += day.Seconds
+	}
+	if total > 0 {
+		data, err := chart.BarChart("Hours per day", labels, values)
+		if err != nil {
+			log.Printf("绘制 %s 每日柱状图失败: %v", serverType, err)
+		} else {
+			bm.sendPhoto(chatID, fmt.Sprintf("%s_days.png", serverType), data,
+				fmt.Sprintf("📊 %s · 最近 %d 天每天的播放时长（小时）", serverName, chartDays))
+		}
+	}
+
+	rowLabels := make([]string, len(heatmapWeekdays))
+	cells := make([][]float64, len(heatmapWeekdays))
+	total = 0
+	for i, weekday := range heatmapWeekdays {
+		rowLabels[i] = weekday.String()[:3]
+		cells[i] = make([]float64, 24)
+		for hour := 0; hour < 24; hour++ {
+			cells[i][hour] = stats.WeekdayHours[weekday][hour]
+			total += cells[i][hour]
+		}
+	}
+	if total > 0 {
+		colLabels := make([]string, 24)
+		for hour := 0; hour < 24; hour += 3 {
+			colLabels[hour] = fmt.Sprintf("%02d", hour)
+		}
+		data, err := chart.Heatmap("Activity by weekday and hour", rowLabels, colLabels, cells)
+		if err != nil {
+			log.Printf("绘制 %s 热力图失败: %v", serverType, err)
+		} else {
+			bm.sendPhoto(chatID, fmt.Sprintf("%s_heatmap.png", serverType), data,
+				fmt.Sprintf("🗓 %s · 按星期和时段的播放分布", serverName))
+		}
+	}
+}
+
+// sendLibraryChart 发送媒体库项目数量饼图，图例通过颜色 Emoji 写在说明中
+func (bm *Manager) sendLibraryChart(chatID int64, serverType services.MediaServerType, libraries []models.LibraryInfo) {
+	slices := make([]chart.Slice, 0, len(libraries))
+	for _, lib := range libraries {
+		slices = append(slices, chart.Slice{Label: lib.Name, Value: float64(lib.ItemCount)})
+	}
+	slices = chart.MergeSmallSlices(slices)
+	if len(slices) == 0 {
+		return
+	}
+
+	data, err := chart.PieChart("Items per library", slices)
+	if err != nil {
+		log.Printf("绘制 %s 媒体库饼图失败: %v", serverType, err)
+		return
+	}
+
+	var caption strings.Builder
+	caption.WriteString(fmt.Sprintf("📚 %s · 各媒体库项目数量\n", strings.Title(string(serverType))))
+	for i, s := range slices {
+		label := s.Label
+		if label == "Other" {
+			label = "其他"
+		}
+		caption.WriteString(fmt.Sprintf("%s %s: %.0f\n", chart.PaletteEmoji(i), label, s.Value))
+	}
+
+	bm.sendPhoto(chatID, fmt.Sprintf("%s_libraries.png", serverType), data, caption.String())
+}
@@ -0,0 +1,382 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+// 合集和播放列表相关的回调动作
+const (
+	actionCollectionItems  = "col"
+	actionCollectionShare  = "col_share"
+	actionCollectionPicker = "col_pick"
+	actionCollectionAdd    = "col_add"
+	actionCollectionCreate = "col_new"
+)
+
+const (
+	// collectionListLimit 合集列表中显示的最大数量
+	collectionListLimit = 30
+	// collectionItemsLimit 合集详情中显示的最大项目数
+	collectionItemsLimit = 30
+	// collectionShareLimit 以消息分享时列出的最大项目数，超出消息长度限制时拆分为多条消息
+	collectionShareLimit = 100
+	// telegramMessageLimit Telegram 单条消息的最大长度
+	telegramMessageLimit = 4096
+)
+
+// collectionKindNames 合集类型的显示名称
+var collectionKindNames = map[string]string{
+	models.CollectionKindCollection: "合集",
+	models.CollectionKindPlaylist:   "播放列表",
+}
+
+// collectionKindIcons 合集类型的图标
+var collectionKindIcons = map[string]string{
+	models.CollectionKindCollection: "🗂",
+	models.CollectionKindPlaylist:   "🎵",
+}
+
+// serverCollection 某个服务器上的合集或播放列表
+type serverCollection struct {
+	server     services.MediaServerType
+	collection models.Collection
+}
+
+// collectionManager 返回支持合集管理的服务器
+func (bm *Manager) collectionManager(serverType services.MediaServerType) (models.CollectionManager, error) {
+	server, err := bm.mediaServerManager.GetServer(serverType)
+	if err != nil {
+		return nil, err
+	}
+	collectionManager, ok := server.(models.CollectionManager)
+	if !ok {
+		return nil, fmt.Errorf("%s 服务器不支持合集和播放列表", strings.Title(string(serverType)))
+	}
+	return collectionManager, nil
+}
+
+// findCollection 在服务器的合集列表中查找合集，用于显示名称和项目数
+func findCollection(collectionManager models.CollectionManager, kind, collectionID string) (*models.Collection, error) {
+	collections, err := collectionManager.GetCollections()
+	if err != nil {
+		return nil, err
+	}
+	for i := range collections {
+		if collections[i].Kind == kind && collections[i].ID == collectionID {
+			return &collections[i], nil
+		}
+	}
+	return nil, fmt.Errorf("未找到%s", collectionKindNames[kind])
+}
+
+// SendCollections 列出所有服务器上的合集和播放列表
+func (bm *Manager) SendCollections(chatID int64, messageID int) {
+	var collections []serverCollection
+	var sb strings.Builder
+	sb.WriteString("🗂 *合集和播放列表*\n\n")
+
+	supported := false
+	for _, serverType := range bm.mediaServerManager.GetServerTypes() {
+		collectionManager, err := bm.collectionManager(serverType)
+		if err != nil {
+			continue
+		}
+		supported = true
+		serverCollections, err := collectionManager.GetCollections()
+		if err != nil {
+			log.Printf("获取 %s 合集列表失败: %v", serverType, err)
+			sb.WriteString(fmt.Sprintf("❌ %s 服务器获取失败\n", strings.Title(string(serverType))))
+			continue
+		}
+		for _, collection := range serverCollections {
+			collections = append(collections, serverCollection{server: serverType, collection: collection})
+		}
+	}
+
+	if !supported {
+		sb.WriteString("没有支持合集的服务器\n")
+	} else if len(collections) == 0 {
+		sb.WriteString("📭 还没有合集或播放列表，可以在项目详情中新建\n")
+	}
+
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, entry := range collections {
+		if i >= collectionListLimit {
+			sb.WriteString(fmt.Sprintf("+ 还有 %d 个...\n", len(collections)-collectionListLimit))
+			break
+		}
+
+		collection := entry.collection
+		sb.WriteString(fmt.Sprintf("%d. %s *%s* · %d 个项目 · %s\n", i+1, collectionKindIcons[collection.Kind],
+			util.EscapeMarkdown(collection.Name), collection.ItemCount, strings.Title(string(entry.server))))
+
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d", i+1),
+			bm.callbackData(actionCollectionItems, string(entry.server), collection.Kind, collection.ID)))
+		if len(row) == entityButtonsPerRow {
+			buttons = append(buttons, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		buttons = append(buttons, row)
+	}
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回主菜单", "main_menu")))
+
+	bm.sendOrEditMessage(chatID, messageID, sb.String(), tgbotapi.NewInlineKeyboardMarkup(buttons...))
+}
+
+// SendCollectionItems 显示合集或播放列表中的项目
+func (bm *Manager) SendCollectionItems(chatID int64, messageID int, serverType services.MediaServerType, kind, collectionID string) {
+	collectionManager, err := bm.collectionManager(serverType)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+	collection, err := findCollection(collectionManager, kind, collectionID)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+	items, err := collectionManager.GetCollectionItems(kind, collectionID)
+	if err != nil {
+		log.Printf("获取 %s 合集 %s 的项目失败: %v", serverType, collectionID, err)
+		bm.SendMessage(chatID, "❌ 获取项目失败: "+err.Error())
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s *%s*\n", collectionKindIcons[kind], util.EscapeMarkdown(collection.Name)))
+	sb.WriteString(fmt.Sprintf("%s · %s · %d 个项目\n\n", strings.Title(string(serverType)), collectionKindNames[kind], len(items)))
+	if len(items) == 0 {
+		sb.WriteString("📭 没有项目\n")
+	}
+
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, item := range items {
+		if i >= collectionItemsLimit {
+			sb.WriteString(fmt.Sprintf("+ 还有 %d 个项目...\n", len(items)-collectionItemsLimit))
+			break
+		}
+
+		sb.WriteString(fmt.Sprintf("%d. %s *%s*", i+1, util.GetMediaTypeIcon(item.Type), util.EscapeMarkdown(item.Title)))
+		if item.Year > 0 {
+			sb.WriteString(fmt.Sprintf(" (%d)", item.Year))
+		}
+		if item.Author != "" {
+			sb.WriteString(" · " + util.EscapeMarkdown(item.Author))
+		}
+		sb.WriteString("\n")
+
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("ℹ️ %d", i+1),
+			bm.callbackData(actionItemDetails, string(serverType), item.ID)))
+		if len(row) == entityButtonsPerRow {
+			buttons = append(buttons, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		buttons = append(buttons, row)
+	}
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("📤 以消息分享", bm.callbackData(actionCollectionShare, string(serverType), kind, collectionID)),
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回", "collections_list"),
+	))
+
+	bm.sendOrEditMessage(chatID, messageID, sb.String(), tgbotapi.NewInlineKeyboardMarkup(buttons...))
+}
+
+// ShareCollection 将合集或播放列表的内容作为纯文本消息发送，方便转发给其他人
+func (bm *Manager) ShareCollection(chatID int64, serverType services.MediaServerType, kind, collectionID string) {
+	collectionManager, err := bm.collectionManager(serverType)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+	collection, err := findCollection(collectionManager, kind, collectionID)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+	items, err := collectionManager.GetCollectionItems(kind, collectionID)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ 获取项目失败: "+err.Error())
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s（%d 个项目）\n\n", collectionKindIcons[kind], collection.Name, len(items)))
+	var total float64
+	for i, item := range items {
+		total += item.Duration
+		if i >= collectionShareLimit {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%d. %s", i+1, item.Title))
+		if item.Year > 0 {
+			sb.WriteString(fmt.Sprintf(" (%d)", item.Year))
+		}
+		if item.Author != "" {
+			sb.WriteString(" — " + item.Author)
+		}
+		if item.Duration > 0 {
+			sb.WriteString(" · " + util.FormatListeningTime(item.Duration))
+		}
+		sb.WriteString("\n")
+	}
+	if len(items) > collectionShareLimit {
+		sb.WriteString(fmt.Sprintf("……还有 %d 个项目\n", len(items)-collectionShareLimit))
+	}
+	if total > 0 {
+		sb.WriteString(fmt.Sprintf("\n总时长: %s\n", util.FormatListeningTime(total)))
+	}
+
+	for _, chunk := range util.SplitMessage(sb.String(), telegramMessageLimit) {
+		bm.SendMessage(chatID, chunk)
+	}
+}
+
+// collectionButtons 返回项目详情中加入合集的按钮
+func (bm *Manager) collectionButtons(serverType services.MediaServerType, item *models.SearchResult) [][]tgbotapi.InlineKeyboardButton {
+	if _, err := bm.collectionManager(serverType); err != nil {
+		return nil
+	}
+	return [][]tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🗂 加入合集/播放列表", bm.callbackData(actionCollectionPicker, string(serverType), item.ID)))}
+}
+
+// SendCollectionPicker 列出可以加入项目的合集和播放列表，只包含项目所在媒体库的合集
+func (bm *Manager) SendCollectionPicker(chatID int64, serverType services.MediaServerType, itemID string) {
+	server, err := bm.mediaServerManager.GetServer(serverType)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+	collectionManager, err := bm.collectionManager(serverType)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+	item, err := server.GetItem(itemID)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ 获取项目详情失败: "+err.Error())
+		return
+	}
+	collections, err := collectionManager.GetCollections()
+	if err != nil {
+		bm.SendMessage(chatID, "❌ 获取合集列表失败: "+err.Error())
+		return
+	}
+
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	for _, collection := range collections {
+		if collection.LibraryID != "" && collection.LibraryID != item.LibraryID {
+			continue
+		}
+		if len(buttons) >= collectionListLimit {
+			break
+		}
+		label := fmt.Sprintf("%s %s", collectionKindIcons[collection.Kind], collection.Name)
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(label,
+			bm.callbackData(actionCollectionAdd, string(serverType), collection.Kind, collection.ID, itemID))))
+	}
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("➕ 新建合集", bm.callbackData(actionCollectionCreate, string(serverType), models.CollectionKindCollection, itemID)),
+		tgbotapi.NewInlineKeyboardButtonData("➕ 新建播放列表", bm.callbackData(actionCollectionCreate, string(serverType), models.CollectionKindPlaylist, itemID)),
+	))
+
+	text := fmt.Sprintf("🗂 将 *%s* 加入:", util.EscapeMarkdown(item.Title))
+	bm.sendOrEditMessage(chatID, 0, text, tgbotapi.NewInlineKeyboardMarkup(buttons...))
+}
+
+// handleCollectionAction 处理合集相关的回调
+func (bm *Manager) handleCollectionAction(callback *tgbotapi.CallbackQuery, action string, args []string) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	if len(args) < 2 {
+		log.Printf("无效的合集参数: %v", args)
+		return
+	}
+	serverType := services.MediaServerType(args[0])
+
+	switch action {
+	case actionCollectionPicker:
+		bm.SendCollectionPicker(chatID, serverType, args[1])
+
+	case actionCollectionItems, actionCollectionShare:
+		if len(args) < 3 {
+			log.Printf("无效的合集参数: %v", args)
+			return
+		}
+		if action == actionCollectionShare {
+			bm.ShareCollection(chatID, serverType, args[1], args[2])
+			return
+		}
+		executeWithLoadingStatus(bm.Bot, chatID, messageID, "🗂 正在获取项目，请稍候...", func() {
+			bm.SendCollectionItems(chatID, messageID, serverType, args[1], args[2])
+		})
+
+	case actionCollectionAdd:
+		if len(args) < 4 {
+			log.Printf("无效的合集参数: %v", args)
+			return
+		}
+		kind := args[1]
+		collectionManager, err := bm.collectionManager(serverType)
+		if err != nil {
+			bm.SendMessage(chatID, "❌ "+err.Error())
+			return
+		}
+		if err := collectionManager.AddToCollection(kind, args[2], args[3]); err != nil {
+			log.Printf("加入%s失败: %v", collectionKindNames[kind], err)
+			bm.EditMessage(chatID, messageID, fmt.Sprintf("❌ 加入%s失败: %s", collectionKindNames[kind], err.Error()))
+			return
+		}
+		bm.EditMessage(chatID, messageID, fmt.Sprintf("✅ 已加入%s", collectionKindNames[kind]))
+
+	case actionCollectionCreate:
+		if len(args) < 3 {
+			log.Printf("无效的合集参数: %v", args)
+			return
+		}
+		kind, itemID := args[1], args[2]
+		bm.promptForInput(chatID, fmt.Sprintf("请输入新%s的名称:", collectionKindNames[kind]), func(message *tgbotapi.Message) {
+			bm.createCollection(chatID, serverType, kind, strings.TrimSpace(message.Text), itemID)
+		})
+	}
+}
+
+// createCollection 创建包含项目的合集或播放列表
+func (bm *Manager) createCollection(chatID int64, serverType services.MediaServerType, kind, name, itemID string) {
+	if name == "" {
+		bm.SendMessage(chatID, "❌ 名称不能为空")
+		return
+	}
+	collectionManager, err := bm.collectionManager(serverType)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+
+	collection, err := collectionManager.CreateCollection(kind, name, itemID)
+	if err != nil {
+		log.Printf("创建%s %s 失败: %v", collectionKindNames[kind], name, err)
+		bm.SendMessage(chatID, fmt.Sprintf("❌ 创建%s失败: %s", collectionKindNames[kind], err.Error()))
+		return
+	}
+
+	text := fmt.Sprintf("✅ 已创建%s *%s* 并加入该项目", collectionKindNames[kind], util.EscapeMarkdown(collection.Name))
+	menu := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🗂 查看", bm.callbackData(actionCollectionItems, string(serverType), kind, collection.ID))))
+	bm.sendOrEditMessage(chatID, 0, text, menu)
+}
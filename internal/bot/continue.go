@@ -0,0 +1,122 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+const (
+	// continueListLimit 继续播放列表最多显示的项目数量
+	continueListLimit = 15
+	// continueButtonsPerRow 每行显示的详情按钮数量
+	continueButtonsPerRow = 5
+)
+
+// serverResumeItem 带服务器类型的继续播放项目
+type serverResumeItem struct {
+	server services.MediaServerType
+	item   models.ResumeItem
+}
+
+// SendContinueList 发送所有服务器未播放完的项目，按最后播放时间排序
+func (bm *Manager) SendContinueList(chatID int64, messageID int) {
+	resumeItems, errs := bm.mediaServerManager.GetResumeItemsAcrossServers()
+
+	var merged []serverResumeItem
+	for serverType, items := range resumeItems {
+		for _, item := range items {
+			merged = append(merged, serverResumeItem{server: serverType, item: item})
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].item.LastPlayed > merged[j].item.LastPlayed
+	})
+
+	var sb strings.Builder
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	sb.WriteString("⏯ *继续观看 / 收听*:\n\n")
+
+	for _, serverType := range bm.mediaServerManager.GetServerTypes() {
+		if err, exists := errs[serverType]; exists {
+			log.Printf("获取 %s 继续播放项目失败: %v", serverType, err)
+			sb.WriteString(fmt.Sprintf("❌ %s 服务器获取失败\n", strings.Title(string(serverType))))
+		}
+	}
+	if len(errs) > 0 {
+		sb.WriteString("\n")
+	}
+
+	if len(merged) == 0 {
+		sb.WriteString("📭 没有未播放完的项目\n")
+	}
+
+	for i, entry := range merged {
+		if i >= continueListLimit {
+			sb.WriteString(fmt.Sprintf("+ 还有 %d 个项目...\n", len(merged)-continueListLimit))
+			break
+		}
+		sb.WriteString(formatResumeItem(i+1, entry.server, entry.item))
+
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("ℹ️ %d", i+1),
+			bm.callbackData(actionItemDetails, string(entry.server), entry.item.ID)))
+		if len(row) == continueButtonsPerRow {
+			buttons = append(buttons, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		buttons = append(buttons, row)
+	}
+
+	buttons = append(buttons, []tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardButtonData("🔄 刷新", "continue_list"),
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回主菜单", "main_menu"),
+	})
+	menu := tgbotapi.NewInlineKeyboardMarkup(buttons...)
+
+	if messageID > 0 {
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, sb.String())
+		edit.ParseMode = "Markdown"
+		edit.ReplyMarkup = &menu
+		err := editBotMessage(bm.Bot, edit)
+		if err != nil {
+			log.Printf("编辑继续播放消息失败: %v", err)
+		}
+	} else {
+		msg := tgbotapi.NewMessage(chatID, sb.String())
+		msg.ParseMode = "Markdown"
+		msg.ReplyMarkup = menu
+		err := sendBotMessage(bm.Bot, msg)
+		if err != nil {
+			log.Printf("发送继续播放消息失败: %v", err)
+		}
+	}
+}
+
+// formatResumeItem 格式化单个继续播放项目
+func formatResumeItem(index int, serverType services.MediaServerType, item models.ResumeItem) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%d. %s *%s*", index, util.GetMediaTypeIcon(item.Type), util.EscapeMarkdown(item.Title)))
+	if item.Author != "" {
+		sb.WriteString(fmt.Sprintf(" - %s", util.EscapeMarkdown(item.Author)))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(fmt.Sprintf("   %s %d%%", util.ProgressBar(item.Progress, 10), int(item.Progress*100)))
+	if remaining := item.Remaining(); remaining > 0 {
+		sb.WriteString(fmt.Sprintf(" · 剩余 %s", util.FormatListeningTime(remaining)))
+	}
+	sb.WriteString(fmt.Sprintf(" · %s\n", strings.Title(string(serverType))))
+
+	return sb.String()
+}
@@ -0,0 +1,179 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+// actionDownloadItem 将项目文件发送到 Telegram 的回调动作
+const actionDownloadItem = "dl"
+
+// downloadMaxParts 一次下载最多发送的文件数量，避免误点整部剧集
+const downloadMaxParts = 30
+
+// telegramAudioExts Telegram 可以作为音频播放的格式，其他格式作为文件发送
+var telegramAudioExts = map[string]bool{
+	".mp3": true,
+	".m4a": true,
+}
+
+// fileDownloader 返回支持下载文件的服务器
+func (bm *Manager) fileDownloader(serverType services.MediaServerType) (models.FileDownloader, error) {
+	server, err := bm.mediaServerManager.GetServer(serverType)
+	if err != nil {
+		return nil, err
+	}
+	downloader, ok := server.(models.FileDownloader)
+	if !ok {
+		return nil, fmt.Errorf("%s 服务器不支持下载文件", strings.Title(string(serverType)))
+	}
+	return downloader, nil
+}
+
+// downloadButtons 返回项目详情中的下载按钮，用户没有下载权限时不显示
+func (bm *Manager) downloadButtons(serverType services.MediaServerType, item *models.SearchResult, userID int64) [][]tgbotapi.InlineKeyboardButton {
+	if !bm.CanDownload(userID) {
+		return nil
+	}
+	if _, err := bm.fileDownloader(serverType); err != nil {
+		return nil
+	}
+	return [][]tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬇️ 下载到 Telegram", bm.callbackData(actionDownloadItem, string(serverType), item.ID)))}
+}
+
+// handleDownloadAction 检查权限后在后台将项目文件发送到聊天中
+func (bm *Manager) handleDownloadAction(callback *tgbotapi.CallbackQuery, args []string) {
+	chatID := callback.Message.Chat.ID
+	if !bm.CanDownload(callback.From.ID) {
+		bm.SendMessage(chatID, "🚫 您没有下载文件的权限")
+		return
+	}
+	if len(args) < 2 {
+		log.Printf("无效的下载参数: %v", args)
+		return
+	}
+
+	serverType := services.MediaServerType(args[0])
+	itemID := args[1]
+	downloader, err := bm.fileDownloader(serverType)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+
+	if _, running := bm.activeDownloads.LoadOrStore(chatID, true); running {
+		bm.SendMessage(chatID, "⏳ 已有文件正在发送，请等待完成后再试")
+		return
+	}
+
+	status, err := bm.Bot.Send(tgbotapi.NewMessage(chatID, "⬇️ 正在准备文件..."))
+	if err != nil {
+		log.Printf("发送下载状态消息失败: %v", err)
+		bm.activeDownloads.Delete(chatID)
+		return
+	}
+
+	go func() {
+		defer bm.activeDownloads.Delete(chatID)
+		bm.uploadItemFiles(chatID, status.MessageID, downloader, itemID)
+	}()
+}
+
+// uploadItemFiles 从服务器读取项目文件并逐个上传，超出大小限制的文件按章节拆分
+func (bm *Manager) uploadItemFiles(chatID int64, statusMessageID int, downloader models.FileDownloader, itemID string) {
+	files, err := downloader.GetDownloadFiles(itemID)
+	if err != nil {
+		log.Printf("获取项目 %s 的文件失败: %v", itemID, err)
+		bm.EditMessage(chatID, statusMessageID, "❌ 获取文件列表失败: "+err.Error())
+		return
+	}
+	if len(files) == 0 {
+		bm.EditMessage(chatID, statusMessageID, "📭 该项目没有可下载的文件")
+		return
+	}
+
+	plan := services.PlanUploads(files, bm.uploadLimit)
+	if len(plan.Parts) > downloadMaxParts {
+		bm.EditMessage(chatID, statusMessageID, fmt.Sprintf("❌ 需要发送 %d 个文件，超过单次下载上限 %d，请选择单集或单曲下载", len(plan.Parts), downloadMaxParts))
+		return
+	}
+
+	sent := 0
+	var failed []string
+	for i, part := range plan.Parts {
+		progress := fmt.Sprintf("⬆️ 正在发送 %d/%d: %s", i+1, len(plan.Parts), part.Name)
+		if part.End > 0 {
+			progress += fmt.Sprintf(" (%s)", util.FormatBytes(part.End-part.Start))
+		} else if part.File.Size > 0 {
+			progress += fmt.Sprintf(" (%s)", util.FormatBytes(part.File.Size))
+		}
+		bm.EditMessage(chatID, statusMessageID, progress)
+
+		if err := bm.uploadPart(chatID, downloader, itemID, part); err != nil {
+			log.Printf("发送文件 %s 失败: %v", part.Name, err)
+			failed = append(failed, part.Name)
+			continue
+		}
+		sent++
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("✅ 已发送 %d 个文件", sent))
+	if len(failed) > 0 {
+		sb.WriteString(fmt.Sprintf("\n\n❌ 以下 %d 个文件发送失败:\n", len(failed)))
+		for _, name := range failed {
+			sb.WriteString("• " + name + "\n")
+		}
+	}
+	if len(plan.Skipped) > 0 {
+		sb.WriteString(fmt.Sprintf("\n\n⚠️ 以下文件超过 %s 且无法拆分，未发送:\n", util.FormatBytes(bm.uploadLimit)))
+		for _, skipped := range plan.Skipped {
+			sb.WriteString(fmt.Sprintf("• %s (%s): %s\n", skipped.File.Name, util.FormatBytes(skipped.File.Size), skipped.Reason))
+		}
+	}
+	bm.EditMessage(chatID, statusMessageID, sb.String())
+}
+
+// uploadPart 以流的方式将文件或文件的一部分上传到 Telegram，MP3 和 M4A 作为音频发送
+func (bm *Manager) uploadPart(chatID int64, downloader models.FileDownloader, itemID string, part services.UploadPart) error {
+	reader, err := downloader.OpenFile(itemID, part.File.ID, part.Start, part.End)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	file := tgbotapi.FileReader{Name: part.Name, Reader: reader}
+	caption := part.Chapters
+	if part.Count > 1 {
+		caption = strings.TrimSpace(fmt.Sprintf("%d/%d %s", part.Index, part.Count, caption))
+	}
+
+	var message tgbotapi.Chattable
+	if telegramAudioExts[strings.ToLower(path.Ext(part.Name))] {
+		audio := tgbotapi.NewAudio(chatID, file)
+		audio.Title = part.File.Title
+		if part.Count > 1 {
+			audio.Title = fmt.Sprintf("%s (%d/%d)", part.File.Title, part.Index, part.Count)
+		}
+		audio.Performer = part.File.Artist
+		audio.Duration = int(part.Duration)
+		audio.Caption = caption
+		message = audio
+	} else {
+		document := tgbotapi.NewDocument(chatID, file)
+		document.Caption = caption
+		message = document
+	}
+
+	_, err = bm.Bot.Send(message)
+	return err
+}
@@ -0,0 +1,219 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+// actionEntityItems 列出人物、系列等实体关联项目的回调动作
+const actionEntityItems = "ent"
+
+const (
+	// entityListLimit 实体搜索结果最多显示的数量
+	entityListLimit = 15
+	// entityItemsLimit 实体关联项目最多显示的数量
+	entityItemsLimit = 20
+	// entityButtonsPerRow 每行显示的项目详情按钮数量
+	entityButtonsPerRow = 5
+	// maxEntityLabelLength 实体按钮上名称的最大字符数
+	maxEntityLabelLength = 24
+)
+
+// entityKindNames 实体类型的显示名称
+var entityKindNames = map[string]string{
+	models.EntityPerson:   "👤 人物",
+	models.EntityAuthor:   "✍️ 作者",
+	models.EntityNarrator: "🎙 演播",
+	models.EntitySeries:   "📚 系列",
+	models.EntityTag:      "🏷 标签",
+}
+
+// entityKindIcon 返回实体类型的图标
+func entityKindIcon(kind string) string {
+	name, ok := entityKindNames[kind]
+	if !ok {
+		return "🔖"
+	}
+	return strings.Fields(name)[0]
+}
+
+// mergedEntity 合并后的实体，同名同类型的实体在多个服务器上只出现一次
+type mergedEntity struct {
+	entity  models.Entity
+	servers []services.MediaServerType
+	score   float64
+}
+
+// PromptForPeopleSearch 提示用户输入人物、系列等名称
+func (bm *Manager) PromptForPeopleSearch(chatID int64) {
+	bm.promptForInput(chatID, "👤 请输入人物、作者、演播者、系列或标签名称:", func(message *tgbotapi.Message) {
+		bm.SendPeopleSearch(message.Chat.ID, message.Text)
+	})
+}
+
+// SendPeopleSearch 搜索人物、系列等实体，点击实体可以列出所有服务器上的相关项目
+func (bm *Manager) SendPeopleSearch(chatID int64, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		bm.PromptForPeopleSearch(chatID)
+		return
+	}
+
+	results, errs := bm.mediaServerManager.SearchEntitiesAcrossServers(text)
+
+	var merged []*mergedEntity
+	index := make(map[string]*mergedEntity)
+	for _, serverType := range bm.mediaServerManager.GetServerTypes() {
+		for _, entity := range results[serverType] {
+			key := entity.Kind + ":" + util.NormalizeTitle(entity.Name)
+			if existing, ok := index[key]; ok {
+				existing.servers = append(existing.servers, serverType)
+				existing.entity.ItemCount += entity.ItemCount
+				continue
+			}
+			m := &mergedEntity{
+				entity:  entity,
+				servers: []services.MediaServerType{serverType},
+				score:   services.TitleSimilarity(text, entity.Name),
+			}
+			index[key] = m
+			merged = append(merged, m)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].score != merged[j].score {
+			return merged[i].score > merged[j].score
+		}
+		return merged[i].entity.ItemCount > merged[j].entity.ItemCount
+	})
+
+	var sb strings.Builder
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	sb.WriteString(fmt.Sprintf("👤 搜索 \"%s\" 的人物和系列:\n\n", util.EscapeMarkdown(text)))
+
+	for _, serverType := range bm.mediaServerManager.GetServerTypes() {
+		if err, exists := errs[serverType]; exists {
+			log.Printf("在 %s 中搜索人物失败: %v", serverType, err)
+			sb.WriteString(fmt.Sprintf("❌ %s 服务器搜索失败\n", strings.Title(string(serverType))))
+		}
+	}
+
+	if len(merged) == 0 {
+		sb.WriteString("未找到相关的人物、系列或标签。\n")
+	}
+
+	for i, m := range merged {
+		if i >= entityListLimit {
+			sb.WriteString(fmt.Sprintf("+ 还有 %d 个结果...\n", len(merged)-entityListLimit))
+			break
+		}
+
+		servers := make([]string, len(m.servers))
+		for j, serverType := range m.servers {
+			servers[j] = strings.Title(string(serverType))
+		}
+		sb.WriteString(fmt.Sprintf("%d. %s *%s*", i+1, entityKindNames[m.entity.Kind], util.EscapeMarkdown(m.entity.Name)))
+		if m.entity.ItemCount > 0 {
+			sb.WriteString(fmt.Sprintf(" (%d)", m.entity.ItemCount))
+		}
+		sb.WriteString(fmt.Sprintf(" · %s\n", strings.Join(servers, ", ")))
+
+		label := fmt.Sprintf("%d. %s %s", i+1, entityKindIcon(m.entity.Kind), truncateLabel(m.entity.Name, maxEntityLabelLength))
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, bm.callbackData(actionEntityItems, m.entity.Kind, m.entity.Name))))
+	}
+
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回主菜单", "main_menu")))
+
+	msg := tgbotapi.NewMessage(chatID, sb.String())
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(buttons...)
+	err := sendBotMessage(bm.Bot, msg)
+	if err != nil {
+		log.Printf("发送人物搜索结果失败: %v", err)
+	}
+}
+
+// SendEntityItems 列出所有服务器上与实体相关的项目，人物按名称在各服务器间匹配
+func (bm *Manager) SendEntityItems(chatID int64, kind, name string) {
+	results, errs := bm.mediaServerManager.GetEntityItemsAcrossServers(kind, name)
+	merged := services.MergeSearchResults("", results)
+
+	var sb strings.Builder
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	kindName, ok := entityKindNames[kind]
+	if !ok {
+		kindName = kind
+	}
+	sb.WriteString(fmt.Sprintf("%s *%s* 的相关项目:\n\n", kindName, util.EscapeMarkdown(name)))
+
+	for _, serverType := range bm.mediaServerManager.GetServerTypes() {
+		if err, exists := errs[serverType]; exists {
+			log.Printf("获取 %s 中 %s 的相关项目失败: %v", serverType, name, err)
+			sb.WriteString(fmt.Sprintf("❌ %s 服务器获取失败\n", strings.Title(string(serverType))))
+		}
+	}
+
+	if len(merged) == 0 {
+		sb.WriteString("📭 没有找到相关项目\n")
+	}
+
+	for i, item := range merged {
+		if i >= entityItemsLimit {
+			sb.WriteString(fmt.Sprintf("+ 还有 %d 个项目...\n", len(merged)-entityItemsLimit))
+			break
+		}
+
+		result := item.Result
+		sb.WriteString(fmt.Sprintf("%d. %s *%s*", i+1, util.GetMediaTypeIcon(result.Type), util.EscapeMarkdown(result.Title)))
+		if result.Year > 0 {
+			sb.WriteString(fmt.Sprintf(" (%d)", result.Year))
+		}
+		servers := make([]string, 0, len(item.Entries))
+		for _, serverType := range item.Servers() {
+			servers = append(servers, strings.Title(string(serverType)))
+		}
+		sb.WriteString(fmt.Sprintf(" · %s\n", strings.Join(servers, ", ")))
+
+		entry := item.Entries[0]
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("ℹ️ %d", i+1),
+			bm.callbackData(actionItemDetails, string(entry.Server), entry.Result.ID)))
+		if len(row) == entityButtonsPerRow {
+			buttons = append(buttons, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		buttons = append(buttons, row)
+	}
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回主菜单", "main_menu")))
+
+	msg := tgbotapi.NewMessage(chatID, sb.String())
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(buttons...)
+	err := sendBotMessage(bm.Bot, msg)
+	if err != nil {
+		log.Printf("发送实体相关项目失败: %v", err)
+	}
+}
+
+// handleEntityAction 处理点击实体的回调
+func (bm *Manager) handleEntityAction(callback *tgbotapi.CallbackQuery, args []string) {
+	if len(args) < 2 {
+		log.Printf("无效的实体参数: %v", args)
+		return
+	}
+	// 名称中可能包含分隔符，其余参数重新拼接
+	bm.SendEntityItems(callback.Message.Chat.ID, args[0], strings.Join(args[1:], ":"))
+}
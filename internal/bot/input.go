@@ -0,0 +1,51 @@
+package bot
+
+import (
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// inputHandler 处理用户在提示后输入的文本
+type inputHandler func(message *tgbotapi.Message)
+
+// pendingInputs 记录每个聊天中等待用户输入的操作
+type pendingInputs struct {
+	mu       sync.Mutex
+	handlers map[int64]inputHandler
+}
+
+// newPendingInputs 创建等待输入记录
+func newPendingInputs() *pendingInputs {
+	return &pendingInputs{
+		handlers: make(map[int64]inputHandler),
+	}
+}
+
+// set 设置聊天中下一条消息的处理函数
+func (p *pendingInputs) set(chatID int64, handler inputHandler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers[chatID] = handler
+}
+
+// take 取出并清除聊天中等待的处理函数
+func (p *pendingInputs) take(chatID int64) (inputHandler, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	handler, ok := p.handlers[chatID]
+	delete(p.handlers, chatID)
+	return handler, ok
+}
+
+// promptForInput 发送提示消息，并将用户的下一条消息交给处理函数
+func (bm *Manager) promptForInput(chatID int64, prompt string, handler inputHandler) {
+	bm.pendingInputs.set(chatID, handler)
+
+	msg := tgbotapi.NewMessage(chatID, prompt)
+	msg.ReplyMarkup = tgbotapi.ForceReply{ForceReply: true, Selective: true}
+	err := sendBotMessage(bm.Bot, msg)
+	if err != nil {
+		bm.pendingInputs.take(chatID)
+	}
+}
@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+// actionItemDetails 查看项目详情的回调动作
+const actionItemDetails = "item"
+
+// maxOverviewLength 项目详情中概述的最大字符数
+const maxOverviewLength = 500
+
+// SendItemDetails 发送单个项目的详情卡片
+func (bm *Manager) SendItemDetails(chatID int64, serverType services.MediaServerType, itemID string) {
+	server, err := bm.mediaServerManager.GetServer(serverType)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+
+	item, err := server.GetItem(itemID)
+	if err != nil {
+		log.Printf("获取 %s 项目 %s 详情失败: %v", serverType, itemID, err)
+		bm.SendMessage(chatID, "❌ 获取项目详情失败: "+err.Error())
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, formatItemDetails(serverType, item))
+	msg.ParseMode = "Markdown"
+	buttons := bm.seriesButtons(serverType, item)
+	buttons = append(buttons, bm.playStateButtons(serverType, item)...)
+	buttons = append(buttons, bm.collectionButtons(serverType, item)...)
+	// 机器人只处理私聊消息，聊天 ID 即为用户 ID
+	buttons = append(buttons, bm.downloadButtons(serverType, item, chatID)...)
+	if len(buttons) > 0 {
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(buttons...)
+	}
+	err = sendBotMessage(bm.Bot, msg)
+	if err != nil {
+		log.Printf("发送项目详情消息失败: %v", err)
+	}
+}
+
+// formatItemDetails 格式化项目详情卡片
+func formatItemDetails(serverType services.MediaServerType, item *models.SearchResult) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s *%s*\n", util.GetMediaTypeIcon(item.Type), util.EscapeMarkdown(item.Title)))
+	if item.Subtitle != "" {
+		sb.WriteString(fmt.Sprintf("_%s_\n", util.EscapeMarkdown(item.Subtitle)))
+	}
+	sb.WriteString("\n")
+	if item.Author != "" {
+		sb.WriteString(fmt.Sprintf("👤 作者: %s\n", util.EscapeMarkdown(item.Author)))
+	}
+	if len(item.Narrators) > 0 {
+		sb.WriteString(fmt.Sprintf("🎙 演播: %s\n", util.EscapeMarkdown(strings.Join(item.Narrators, ", "))))
+	}
+	if len(item.Series) > 0 {
+		sb.WriteString(fmt.Sprintf("📚 系列: %s\n", util.EscapeMarkdown(formatSeries(item.Series))))
+	}
+	sb.WriteString(fmt.Sprintf("🖥 服务器: %s\n", strings.Title(string(serverType))))
+	if item.Library != "" {
+		sb.WriteString(fmt.Sprintf("📁 媒体库: %s\n", util.EscapeMarkdown(item.Library)))
+	}
+	if item.Type != "" {
+		sb.WriteString(fmt.Sprintf("🏷 类型: %s\n", util.EscapeMarkdown(item.Type)))
+	}
+	if item.Year > 0 {
+		sb.WriteString(fmt.Sprintf("📅 年份: %d\n", item.Year))
+	}
+	if item.Duration > 0 {
+		sb.WriteString(fmt.Sprintf("⏱ 时长: %s\n", util.FormatListeningTime(item.Duration)))
+	}
+	if len(item.Genres) > 0 {
+		sb.WriteString(fmt.Sprintf("🏷️ 分类: %s\n", util.EscapeMarkdown(strings.Join(item.Genres, ", "))))
+	}
+	if item.Size > 0 {
+		sb.WriteString(fmt.Sprintf("💾 大小: %s\n", util.FormatBytes(item.Size)))
+	}
+	if item.AddedAt > 0 {
+		sb.WriteString(fmt.Sprintf("⏰ 添加时间: %s\n", time.UnixMilli(item.AddedAt).Format("2006-01-02 15:04")))
+	}
+
+	if item.Overview != "" {
+		overview := item.Overview
+		if utf8.RuneCountInString(overview) > maxOverviewLength {
+			overview = string([]rune(overview)[:maxOverviewLength]) + "..."
+		}
+		sb.WriteString(fmt.Sprintf("\n📝 %s\n", util.EscapeMarkdown(overview)))
+	}
+
+	return sb.String()
+}
+
+// formatSeries 将系列列表格式化为 “名称 #序号” 并以逗号分隔
+func formatSeries(series []models.SeriesEntry) string {
+	names := make([]string, len(series))
+	for i, s := range series {
+		names[i] = s.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatBookFields 格式化搜索结果中的作者、演播者、系列和时长，每行带有 indent 缩进
+func formatBookFields(item *models.SearchResult, indent string) string {
+	var sb strings.Builder
+	if item.Author != "" {
+		sb.WriteString(fmt.Sprintf("%s👤 作者: %s\n", indent, util.EscapeMarkdown(item.Author)))
+	}
+	if len(item.Narrators) > 0 {
+		sb.WriteString(fmt.Sprintf("%s🎙 演播: %s\n", indent, util.EscapeMarkdown(strings.Join(item.Narrators, ", "))))
+	}
+	if len(item.Series) > 0 {
+		sb.WriteString(fmt.Sprintf("%s📚 系列: %s\n", indent, util.EscapeMarkdown(formatSeries(item.Series))))
+	}
+	if item.Duration > 0 {
+		sb.WriteString(fmt.Sprintf("%s⏱ 时长: %s\n", indent, util.FormatListeningTime(item.Duration)))
+	}
+	return sb.String()
+}
+
+// handleItemAction 处理项目详情的回调
+func (bm *Manager) handleItemAction(callback *tgbotapi.CallbackQuery, args []string) {
+	if len(args) < 2 {
+		log.Printf("无效的项目详情参数: %v", args)
+		return
+	}
+	bm.SendItemDetails(callback.Message.Chat.ID, services.MediaServerType(args[0]), args[1])
+}
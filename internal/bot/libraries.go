@@ -0,0 +1,173 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+// 媒体库管理相关的回调动作
+const (
+	actionScanLibrary    = "lib_scan"
+	actionRefreshLibrary = "lib_refresh"
+)
+
+// maxButtonLabelLength 按钮中媒体库名称的最大字符数
+const maxButtonLabelLength = 12
+
+// libraryButtons 为每个媒体库生成浏览按钮，管理员还会看到扫描和刷新按钮
+func (bm *Manager) libraryButtons(libraries map[services.MediaServerType][]models.LibraryInfo, isAdmin bool) [][]tgbotapi.InlineKeyboardButton {
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	for _, serverType := range bm.mediaServerManager.GetServerTypes() {
+		server, err := bm.mediaServerManager.GetServer(serverType)
+		if err != nil {
+			continue
+		}
+		_, canScan := server.(models.LibraryScanner)
+
+		for _, lib := range libraries[serverType] {
+			row := []tgbotapi.InlineKeyboardButton{
+				tgbotapi.NewInlineKeyboardButtonData(
+					fmt.Sprintf("%s %s", util.GetMediaTypeIcon(lib.MediaType), truncateLabel(lib.Name, maxButtonLabelLength)),
+					bm.browseCallbackData(serverType, lib.ID, defaultItemQuery())),
+			}
+			if isAdmin && canScan {
+				row = append(row,
+					tgbotapi.NewInlineKeyboardButtonData("🔄 扫描",
+						bm.callbackData(actionScanLibrary, string(serverType), lib.ID)),
+					tgbotapi.NewInlineKeyboardButtonData("🏷 刷新元数据",
+						bm.callbackData(actionRefreshLibrary, string(serverType), lib.ID)))
+			}
+			buttons = append(buttons, row)
+		}
+	}
+	return buttons
+}
+
+// truncateLabel 截断过长的按钮文字
+func truncateLabel(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+	return string(runes[:maxLength-1]) + "…"
+}
+
+// handleLibraryScanAction 触发媒体库扫描或元数据刷新，并在后台跟踪完成情况
+func (bm *Manager) handleLibraryScanAction(callback *tgbotapi.CallbackQuery, action string, args []string) {
+	chatID := callback.Message.Chat.ID
+	if !bm.IsUserAdmin(callback.From.ID) {
+		bm.SendMessage(chatID, "🚫 只有管理员可以执行此操作")
+		return
+	}
+	if len(args) < 2 {
+		log.Printf("无效的媒体库操作参数: %v", args)
+		return
+	}
+
+	serverType := services.MediaServerType(args[0])
+	libraryID := args[1]
+	server, err := bm.mediaServerManager.GetServer(serverType)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+	scanner, ok := server.(models.LibraryScanner)
+	if !ok {
+		bm.SendMessage(chatID, "❌ 该服务器不支持扫描媒体库")
+		return
+	}
+
+	scanKey := fmt.Sprintf("%s:%s", serverType, libraryID)
+	if _, running := bm.activeScans.LoadOrStore(scanKey, true); running {
+		bm.SendMessage(chatID, "⏳ 该媒体库已有扫描任务在进行中")
+		return
+	}
+
+	libraryName := libraryID
+	if libraries, err := server.GetLibraries(); err == nil {
+		for _, lib := range libraries {
+			if lib.ID == libraryID {
+				libraryName = lib.Name
+				break
+			}
+		}
+	}
+
+	operation := "扫描"
+	trigger := scanner.ScanLibrary
+	if action == actionRefreshLibrary {
+		operation = "刷新元数据"
+		trigger = scanner.RefreshLibraryMetadata
+	}
+	title := fmt.Sprintf("%s · %s", strings.Title(string(serverType)), libraryName)
+
+	startedAt := time.Now()
+	if err := trigger(libraryID); err != nil {
+		bm.activeScans.Delete(scanKey)
+		log.Printf("%s 媒体库 %s 失败: %v", operation, scanKey, err)
+		bm.SendMessage(chatID, fmt.Sprintf("❌ %s %s 失败: %v", operation, title, err))
+		return
+	}
+
+	if reporter, ok := server.(models.MetadataRefreshReporter); ok && action == actionRefreshLibrary && !reporter.ReportsMetadataRefresh() {
+		bm.activeScans.Delete(scanKey)
+		bm.SendMessage(chatID, fmt.Sprintf("ℹ️ 已提交%s %s 的请求，服务器不报告元数据刷新进度，请稍后在服务器中查看结果", operation, title))
+		return
+	}
+
+	sent, err := bm.Bot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("⏳ 正在%s %s...", operation, title)))
+	if err != nil {
+		bm.activeScans.Delete(scanKey)
+		log.Printf("发送扫描状态消息失败: %v", err)
+		return
+	}
+
+	go func() {
+		defer bm.activeScans.Delete(scanKey)
+		bm.trackLibraryScan(chatID, sent.MessageID, scanner, libraryID, operation, title, startedAt)
+	}()
+}
+
+// trackLibraryScan 轮询扫描任务并编辑状态消息
+func (bm *Manager) trackLibraryScan(chatID int64, messageID int, scanner models.LibraryScanner, libraryID, operation, title string, startedAt time.Time) {
+	lastProgress := -1
+	status, err := services.WaitForScan(scanner, libraryID, startedAt, bm.stop, func(status *models.ScanStatus) {
+		// 进度变化较大时才编辑消息，避免触发频率限制
+		progress := int(status.Progress)
+		if status.Progress < 0 {
+			progress = 0
+		}
+		if lastProgress >= 0 && progress-lastProgress < 10 {
+			return
+		}
+		lastProgress = progress
+
+		text := fmt.Sprintf("⏳ 正在%s %s，任务运行中...", operation, title)
+		if status.Progress >= 0 {
+			text = fmt.Sprintf("⏳ 正在%s %s\n%s %d%%", operation, title, util.ProgressBar(status.Progress/100, 10), progress)
+		}
+		bm.EditMessage(chatID, messageID, text)
+	})
+
+	elapsed := util.FormatDuration(time.Since(startedAt).Round(time.Second))
+	switch {
+	case errors.Is(err, services.ErrScanNotTracked):
+		bm.EditMessage(chatID, messageID, fmt.Sprintf("ℹ️ 已提交%s %s 的请求，但服务器未报告任务进度，请稍后在服务器中查看结果", operation, title))
+	case err != nil:
+		log.Printf("跟踪媒体库 %s 扫描失败: %v", libraryID, err)
+		bm.EditMessage(chatID, messageID, fmt.Sprintf("⚠️ %s %s 的结果未知: %v", operation, title, err))
+	case status.Failed:
+		bm.EditMessage(chatID, messageID, fmt.Sprintf("❌ %s %s 失败: %s", operation, title, status.Message))
+	default:
+		bm.EditMessage(chatID, messageID, fmt.Sprintf("✅ %s %s 完成，用时 %s", operation, title, elapsed))
+	}
+}
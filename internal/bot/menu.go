@@ -13,6 +13,19 @@ func RegisterCommands(bot *tgbotapi.BotAPI) error {
 		{Command: "libraries", Description: "获取所有服务器的媒体库列表"},
 		{Command: "search", Description: "搜索所有服务器的媒体"},
 		{Command: "mystats", Description: "获取所有服务器的个人统计信息"},
+		{Command: "nowplaying", Description: "查看所有服务器正在播放的会话"},
+		{Command: "transcodes", Description: "查看转码负载报告"},
+		{Command: "continue", Description: "继续观看/收听未播放完的项目"},
+		{Command: "people", Description: "按人物、演播者、系列或标签搜索"},
+		{Command: "episodes", Description: "搜索剧集单集"},
+		{Command: "podcasts", Description: "查看和管理播客"},
+		{Command: "addpodcast", Description: "通过 RSS 地址添加播客"},
+		{Command: "collections", Description: "查看合集和播放列表"},
+		{Command: "syncprogress", Description: "在服务器之间同步收听进度"},
+		{Command: "request", Description: "请求添加电影、剧集或书籍"},
+		{Command: "requests", Description: "查看媒体请求"},
+		{Command: "queue", Description: "查看下载队列"},
+		{Command: "downloads", Description: "查看下载客户端"},
 		{Command: "help", Description: "显示帮助信息"},
 	}
 
@@ -35,6 +48,14 @@ func CreateMainMenu() tgbotapi.InlineKeyboardMarkup {
 			tgbotapi.NewInlineKeyboardButtonData("🔍 搜索媒体", "search_books"),
 			tgbotapi.NewInlineKeyboardButtonData("📈 我的统计", "my_stats"),
 		},
+		{
+			tgbotapi.NewInlineKeyboardButtonData("▶️ 正在播放", "now_playing"),
+			tgbotapi.NewInlineKeyboardButtonData("⏯ 继续播放", "continue_list"),
+		},
+		{
+			tgbotapi.NewInlineKeyboardButtonData("🎙 播客", "podcasts_list"),
+			tgbotapi.NewInlineKeyboardButtonData("🗂 合集", "collections_list"),
+		},
 		{
 			tgbotapi.NewInlineKeyboardButtonData("❓ 帮助", "help"),
 		},
@@ -79,6 +100,10 @@ func CreateLibrariesMenu() tgbotapi.InlineKeyboardMarkup {
 // CreateSearchMenu 创建搜索菜单
 func CreateSearchMenu() tgbotapi.InlineKeyboardMarkup {
 	buttons := [][]tgbotapi.InlineKeyboardButton{
+		{
+			tgbotapi.NewInlineKeyboardButtonData("👤 搜索人物/系列", "search_people"),
+			tgbotapi.NewInlineKeyboardButtonData("🎞 搜索单集", "search_episodes"),
+		},
 		{
 			tgbotapi.NewInlineKeyboardButtonData("⬅ 返回主菜单", "main_menu"),
 		},
@@ -0,0 +1,219 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+// 播放会话相关的回调动作
+const (
+	actionStopSession    = "np_stop"
+	actionMessageSession = "np_msg"
+)
+
+// SendNowPlaying 发送所有服务器正在播放的会话
+func (bm *Manager) SendNowPlaying(chatID int64, messageID int, userID int64) {
+	sessions, errs := bm.mediaServerManager.GetSessionsAcrossServers()
+	isAdmin := bm.IsUserAdmin(userID)
+
+	var sb strings.Builder
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	sb.WriteString("▶️ *正在播放*:\n\n")
+
+	total := 0
+	for _, serverType := range bm.mediaServerManager.GetServerTypes() {
+		if err, exists := errs[serverType]; exists {
+			log.Printf("获取 %s 播放会话失败: %v", serverType, err)
+			sb.WriteString(fmt.Sprintf("*%s 服务器*:\n❌ 获取播放会话失败\n\n", strings.Title(string(serverType))))
+			continue
+		}
+
+		serverSessions := sessions[serverType]
+		if len(serverSessions) == 0 {
+			continue
+		}
+
+		server, _ := bm.mediaServerManager.GetServer(serverType)
+		_, canStop := server.(models.SessionController)
+		_, canMessage := server.(models.SessionMessenger)
+
+		sb.WriteString(fmt.Sprintf("*%s 服务器*:\n", strings.Title(string(serverType))))
+		for _, session := range serverSessions {
+			total++
+			sb.WriteString(formatPlaybackSession(total, session))
+
+			if !isAdmin {
+				continue
+			}
+			var row []tgbotapi.InlineKeyboardButton
+			if canStop {
+				row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("⏹ 停止 #%d", total),
+					bm.callbackData(actionStopSession, string(serverType), session.ID)))
+			}
+			if canMessage {
+				row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("💬 消息 #%d", total),
+					bm.callbackData(actionMessageSession, string(serverType), session.ID)))
+			}
+			if len(row) > 0 {
+				buttons = append(buttons, row)
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if total == 0 {
+		sb.WriteString("📭 当前没有正在播放的内容\n")
+	}
+
+	buttons = append(buttons, []tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardButtonData("🔄 刷新", "now_playing"),
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回主菜单", "main_menu"),
+	})
+	menu := tgbotapi.NewInlineKeyboardMarkup(buttons...)
+
+	if messageID > 0 {
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, sb.String())
+		edit.ParseMode = "Markdown"
+		edit.ReplyMarkup = &menu
+		err := editBotMessage(bm.Bot, edit)
+		if err != nil {
+			log.Printf("编辑播放会话消息失败: %v", err)
+		}
+	} else {
+		msg := tgbotapi.NewMessage(chatID, sb.String())
+		msg.ParseMode = "Markdown"
+		msg.ReplyMarkup = menu
+		err := sendBotMessage(bm.Bot, msg)
+		if err != nil {
+			log.Printf("发送播放会话消息失败: %v", err)
+		}
+	}
+}
+
+// formatPlaybackSession 格式化单个播放会话
+func formatPlaybackSession(index int, session models.PlaybackSession) string {
+	var sb strings.Builder
+
+	stateIcon := "▶️"
+	if session.IsPaused {
+		stateIcon = "⏸"
+	}
+	sb.WriteString(fmt.Sprintf("%d. %s *%s*\n", index, stateIcon, util.EscapeMarkdown(session.ItemTitle)))
+
+	sb.WriteString(fmt.Sprintf("   👤 %s · 📱 %s", util.EscapeMarkdown(session.UserName), util.EscapeMarkdown(session.DeviceName)))
+	if session.Client != "" {
+		sb.WriteString(fmt.Sprintf(" (%s)", util.EscapeMarkdown(session.Client)))
+	}
+	sb.WriteString("\n")
+
+	if session.Duration > 0 {
+		fraction := math.Min(math.Max(float64(session.Position)/float64(session.Duration), 0), 1)
+		sb.WriteString(fmt.Sprintf("   %s %d%% %s / %s\n",
+			util.ProgressBar(fraction, 10),
+			int(fraction*100),
+			util.FormatClock(time.Duration(session.Position)*time.Millisecond),
+			util.FormatClock(time.Duration(session.Duration)*time.Millisecond)))
+	}
+
+	var method string
+	switch session.PlayMethod {
+	case models.PlayMethodTranscode:
+		method = "🔁 转码"
+		if t := session.Transcoding; t != nil {
+			var codecs []string
+			for _, codec := range []string{t.VideoCodec, t.AudioCodec} {
+				if codec != "" {
+					codecs = append(codecs, codec)
+				}
+			}
+			if len(codecs) > 0 {
+				method += fmt.Sprintf(" (%s)", strings.Join(codecs, "/"))
+			}
+		}
+	case models.PlayMethodDirectStream:
+		method = "🔀 直接串流"
+	default:
+		method = "✅ 直接播放"
+	}
+	if session.Bitrate > 0 {
+		method += " · " + util.FormatBitrate(session.Bitrate)
+	}
+	sb.WriteString(fmt.Sprintf("   %s\n", method))
+
+	if t := session.Transcoding; t != nil && session.IsTranscoding() {
+		hwAccel := "软件"
+		if t.HardwareAcceleration != "" {
+			hwAccel = t.HardwareAcceleration
+		}
+		sb.WriteString(fmt.Sprintf("   ⚙️ 加速: %s\n", util.EscapeMarkdown(hwAccel)))
+		if len(t.Reasons) > 0 {
+			reasons := make([]string, len(t.Reasons))
+			for i, reason := range t.Reasons {
+				reasons[i] = transcodeReasonName(reason)
+			}
+			sb.WriteString(fmt.Sprintf("   ❓ 原因: %s\n", util.EscapeMarkdown(strings.Join(reasons, ", "))))
+		}
+	}
+
+	return sb.String()
+}
+
+// handleSessionAction 处理停止会话和发送消息的操作
+func (bm *Manager) handleSessionAction(callback *tgbotapi.CallbackQuery, action string, args []string) {
+	chatID := callback.Message.Chat.ID
+	if !bm.IsUserAdmin(callback.From.ID) {
+		bm.SendMessage(chatID, "🚫 只有管理员可以执行此操作")
+		return
+	}
+	if len(args) < 2 {
+		log.Printf("无效的会话操作参数: %v", args)
+		return
+	}
+
+	serverType := services.MediaServerType(args[0])
+	sessionID := args[1]
+	server, err := bm.mediaServerManager.GetServer(serverType)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+
+	switch action {
+	case actionStopSession:
+		controller, ok := server.(models.SessionController)
+		if !ok {
+			bm.SendMessage(chatID, "❌ 该服务器不支持停止播放")
+			return
+		}
+		if err := controller.StopSession(sessionID); err != nil {
+			log.Printf("停止会话 %s 失败: %v", sessionID, err)
+			bm.SendMessage(chatID, "❌ 停止播放失败: "+err.Error())
+			return
+		}
+		bm.SendNowPlaying(chatID, callback.Message.MessageID, callback.From.ID)
+
+	case actionMessageSession:
+		messenger, ok := server.(models.SessionMessenger)
+		if !ok {
+			bm.SendMessage(chatID, "❌ 该服务器不支持发送消息")
+			return
+		}
+		bm.promptForInput(chatID, "💬 请输入要发送到客户端的消息：", func(message *tgbotapi.Message) {
+			if err := messenger.SendSessionMessage(sessionID, message.Text); err != nil {
+				log.Printf("向会话 %s 发送消息失败: %v", sessionID, err)
+				bm.SendMessage(chatID, "❌ 发送消息失败: "+err.Error())
+				return
+			}
+			bm.SendMessage(chatID, "✅ 消息已发送")
+		})
+	}
+}
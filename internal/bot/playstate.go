@@ -0,0 +1,112 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+// 修改播放状态的回调动作
+const (
+	actionMarkPlayed   = "played"
+	actionMarkUnplayed = "unplayed"
+	actionSetPosition  = "pos"
+)
+
+// progressTracker 返回支持修改播放进度的服务器
+func (bm *Manager) progressTracker(serverType services.MediaServerType) (models.MediaServer, models.ProgressTracker, error) {
+	server, err := bm.mediaServerManager.GetServer(serverType)
+	if err != nil {
+		return nil, nil, err
+	}
+	tracker, ok := server.(models.ProgressTracker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s 服务器不支持修改播放进度", strings.Title(string(serverType)))
+	}
+	return server, tracker, nil
+}
+
+// playStateButtons 返回项目详情中标记播放状态和设置播放位置的按钮
+func (bm *Manager) playStateButtons(serverType services.MediaServerType, item *models.SearchResult) [][]tgbotapi.InlineKeyboardButton {
+	if _, _, err := bm.progressTracker(serverType); err != nil {
+		return nil
+	}
+	row := tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✅ 标记已播放", bm.callbackData(actionMarkPlayed, string(serverType), item.ID)),
+		tgbotapi.NewInlineKeyboardButtonData("↩️ 标记未播放", bm.callbackData(actionMarkUnplayed, string(serverType), item.ID)),
+	)
+	// 剧集等包含多个项目的条目没有自己的播放位置
+	if item.Duration > 0 && item.Type != "series" {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("⏱ 设置进度", bm.callbackData(actionSetPosition, string(serverType), item.ID)))
+	}
+	return [][]tgbotapi.InlineKeyboardButton{row}
+}
+
+// handlePlayStateAction 处理项目详情中修改播放状态的按钮，修改的是机器人关联的服务器账户
+func (bm *Manager) handlePlayStateAction(callback *tgbotapi.CallbackQuery, action string, args []string) {
+	chatID := callback.Message.Chat.ID
+	if len(args) < 2 {
+		log.Printf("无效的播放状态参数: %v", args)
+		return
+	}
+
+	serverType := services.MediaServerType(args[0])
+	itemID := args[1]
+	server, tracker, err := bm.progressTracker(serverType)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+
+	switch action {
+	case actionMarkPlayed, actionMarkUnplayed:
+		played := action == actionMarkPlayed
+		if err := tracker.SetPlayed(itemID, played); err != nil {
+			log.Printf("修改 %s 项目 %s 的播放状态失败: %v", serverType, itemID, err)
+			bm.SendMessage(chatID, "❌ 修改播放状态失败: "+err.Error())
+			return
+		}
+		if played {
+			bm.SendMessage(chatID, "✅ 已标记为已播放")
+		} else {
+			bm.SendMessage(chatID, "↩️ 已标记为未播放")
+		}
+
+	case actionSetPosition:
+		bm.promptForInput(chatID, "请输入播放位置，例如 1h23m、83:00 或 1:23:00（纯数字按分钟计算）:", func(message *tgbotapi.Message) {
+			bm.setPlaybackPosition(chatID, server, tracker, itemID, message.Text)
+		})
+	}
+}
+
+// setPlaybackPosition 解析用户输入的时间并设置播放位置，超出项目时长时提示错误
+func (bm *Manager) setPlaybackPosition(chatID int64, server models.MediaServer, tracker models.ProgressTracker, itemID, input string) {
+	position, err := util.ParseTimestamp(input)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+
+	item, err := server.GetItem(itemID)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ 获取项目详情失败: "+err.Error())
+		return
+	}
+	if item.Duration > 0 && position > item.Duration {
+		bm.SendMessage(chatID, fmt.Sprintf("❌ 播放位置 %s 超出项目时长 %s", util.FormatTimestamp(position), util.FormatTimestamp(item.Duration)))
+		return
+	}
+
+	if err := tracker.SetProgress(itemID, position, false); err != nil {
+		log.Printf("设置项目 %s 的播放位置失败: %v", itemID, err)
+		bm.SendMessage(chatID, "❌ 设置播放位置失败: "+err.Error())
+		return
+	}
+	bm.SendMessage(chatID, fmt.Sprintf("⏱ %s 的播放位置已设置为 %s", item.Title, util.FormatTimestamp(position)))
+}
@@ -0,0 +1,490 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+const (
+	actionPodcastDetails     = "pod"
+	actionPodcastSubscribe   = "pod_sub"
+	actionPodcastUnsubscribe = "pod_unsub"
+	actionPodcastCheckNew    = "pod_check"
+	actionPodcastFeed        = "pod_feed"
+	actionPodcastDownload    = "pod_dl"
+)
+
+const (
+	// podcastListLimit 播客列表最多显示的数量
+	podcastListLimit = 30
+	// podcastRecentLimit 最新单集列表显示的数量
+	podcastRecentLimit = 15
+	// podcastFeedLimit 可下载单集列表显示的数量
+	podcastFeedLimit = 10
+	// podcastButtonsPerRow 每行显示的播客按钮数量
+	podcastButtonsPerRow = 2
+)
+
+// serverPodcast 带服务器类型的播客
+type serverPodcast struct {
+	server  services.MediaServerType
+	podcast models.Podcast
+}
+
+// podcastManagers 返回所有支持播客管理的服务器
+func (bm *Manager) podcastManagers() map[services.MediaServerType]models.PodcastManager {
+	managers := make(map[services.MediaServerType]models.PodcastManager)
+	for serverType, server := range bm.mediaServerManager.GetAllServers() {
+		if podcastManager, ok := server.(models.PodcastManager); ok {
+			managers[serverType] = podcastManager
+		}
+	}
+	return managers
+}
+
+// podcastManager 返回指定服务器的播客管理接口
+func (bm *Manager) podcastManager(serverType services.MediaServerType) (models.PodcastManager, error) {
+	server, err := bm.mediaServerManager.GetServer(serverType)
+	if err != nil {
+		return nil, err
+	}
+	podcastManager, ok := server.(models.PodcastManager)
+	if !ok {
+		return nil, fmt.Errorf("%s 服务器不支持播客管理", strings.Title(string(serverType)))
+	}
+	return podcastManager, nil
+}
+
+// sendOrEditMessage messageID 大于 0 时编辑消息，否则发送新消息
+func (bm *Manager) sendOrEditMessage(chatID int64, messageID int, text string, menu tgbotapi.InlineKeyboardMarkup) {
+	if messageID > 0 {
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+		edit.ParseMode = "Markdown"
+		edit.ReplyMarkup = &menu
+		err := editBotMessage(bm.Bot, edit)
+		if err != nil {
+			log.Printf("编辑消息失败: %v", err)
+		}
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = menu
+	err := sendBotMessage(bm.Bot, msg)
+	if err != nil {
+		log.Printf("发送消息失败: %v", err)
+	}
+}
+
+// SendPodcasts 列出所有播客媒体库中的播客
+func (bm *Manager) SendPodcasts(chatID int64, messageID int, userID int64) {
+	var podcasts []serverPodcast
+	var sb strings.Builder
+	sb.WriteString("🎙 *播客*\n\n")
+
+	managers := bm.podcastManagers()
+	for _, serverType := range bm.mediaServerManager.GetServerTypes() {
+		podcastManager, ok := managers[serverType]
+		if !ok {
+			continue
+		}
+		serverPodcasts, err := podcastManager.GetPodcasts()
+		if err != nil {
+			log.Printf("获取 %s 播客列表失败: %v", serverType, err)
+			sb.WriteString(fmt.Sprintf("❌ %s 服务器获取失败\n", strings.Title(string(serverType))))
+			continue
+		}
+		for _, podcast := range serverPodcasts {
+			podcasts = append(podcasts, serverPodcast{server: serverType, podcast: podcast})
+		}
+	}
+
+	if len(managers) == 0 {
+		sb.WriteString("没有支持播客的服务器\n")
+	} else if len(podcasts) == 0 {
+		sb.WriteString("📭 播客媒体库中还没有播客\n")
+	}
+
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, entry := range podcasts {
+		if i >= podcastListLimit {
+			sb.WriteString(fmt.Sprintf("+ 还有 %d 个播客...\n", len(podcasts)-podcastListLimit))
+			break
+		}
+
+		podcast := entry.podcast
+		subscribed := ""
+		if bm.podcastNotifier.IsSubscribed(entry.server, podcast.ID, userID) {
+			subscribed = " 🔔"
+		}
+		sb.WriteString(fmt.Sprintf("%d. *%s*%s\n", i+1, util.EscapeMarkdown(podcast.Title), subscribed))
+		if podcast.Author != "" {
+			sb.WriteString(fmt.Sprintf("   👤 %s\n", util.EscapeMarkdown(podcast.Author)))
+		}
+		sb.WriteString(fmt.Sprintf("   🎧 %d 集 · %s\n", podcast.EpisodeCount, util.EscapeMarkdown(podcast.Library)))
+
+		label := fmt.Sprintf("%d. %s", i+1, truncateLabel(podcast.Title, maxEntityLabelLength))
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label,
+			bm.callbackData(actionPodcastDetails, string(entry.server), podcast.ID)))
+		if len(row) == podcastButtonsPerRow {
+			buttons = append(buttons, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		buttons = append(buttons, row)
+	}
+
+	actions := []tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardButtonData("🆕 最新单集", "podcast_recent"),
+	}
+	if bm.IsUserAdmin(userID) && len(managers) > 0 {
+		actions = append(actions, tgbotapi.NewInlineKeyboardButtonData("➕ 添加播客", "podcast_add"))
+	}
+	buttons = append(buttons, actions, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回主菜单", "main_menu")))
+
+	bm.sendOrEditMessage(chatID, messageID, sb.String(), tgbotapi.NewInlineKeyboardMarkup(buttons...))
+}
+
+// SendRecentEpisodes 列出所有服务器最近加入的播客单集
+func (bm *Manager) SendRecentEpisodes(chatID int64, messageID int) {
+	type serverEpisode struct {
+		server  services.MediaServerType
+		episode models.PodcastEpisode
+	}
+
+	var episodes []serverEpisode
+	var sb strings.Builder
+	sb.WriteString("🆕 *最新播客单集*\n\n")
+
+	managers := bm.podcastManagers()
+	for _, serverType := range bm.mediaServerManager.GetServerTypes() {
+		podcastManager, ok := managers[serverType]
+		if !ok {
+			continue
+		}
+		serverEpisodes, err := podcastManager.GetRecentEpisodes(podcastRecentLimit)
+		if err != nil {
+			log.Printf("获取 %s 最新单集失败: %v", serverType, err)
+			sb.WriteString(fmt.Sprintf("❌ %s 服务器获取失败\n", strings.Title(string(serverType))))
+			continue
+		}
+		for _, episode := range serverEpisodes {
+			episodes = append(episodes, serverEpisode{server: serverType, episode: episode})
+		}
+	}
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return episodes[i].episode.AddedAt > episodes[j].episode.AddedAt
+	})
+
+	if len(episodes) == 0 {
+		sb.WriteString("📭 没有播客单集\n")
+	}
+
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, entry := range episodes {
+		if i >= podcastRecentLimit {
+			break
+		}
+
+		episode := entry.episode
+		sb.WriteString(fmt.Sprintf("%d. *%s*\n", i+1, util.EscapeMarkdown(episode.Title)))
+		details := []string{"🎙 " + util.EscapeMarkdown(episode.PodcastTitle)}
+		if episode.PublishedAt > 0 {
+			details = append(details, "📅 "+time.UnixMilli(episode.PublishedAt).Format("2006-01-02"))
+		}
+		if episode.Duration > 0 {
+			details = append(details, "⏱ "+util.FormatListeningTime(episode.Duration))
+		}
+		sb.WriteString("   " + strings.Join(details, " · ") + "\n")
+
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🎙 %d", i+1),
+			bm.callbackData(actionPodcastDetails, string(entry.server), episode.PodcastID)))
+		if len(row) == entityButtonsPerRow {
+			buttons = append(buttons, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		buttons = append(buttons, row)
+	}
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回播客列表", "podcasts_list"),
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回主菜单", "main_menu")))
+
+	bm.sendOrEditMessage(chatID, messageID, sb.String(), tgbotapi.NewInlineKeyboardMarkup(buttons...))
+}
+
+// SendPodcastDetails 显示播客信息和可执行的操作
+func (bm *Manager) SendPodcastDetails(chatID int64, messageID int, userID int64, serverType services.MediaServerType, podcastID string) {
+	server, err := bm.mediaServerManager.GetServer(serverType)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+	item, err := server.GetItem(podcastID)
+	if err != nil {
+		log.Printf("获取 %s 播客 %s 失败: %v", serverType, podcastID, err)
+		bm.SendMessage(chatID, "❌ 获取播客信息失败: "+err.Error())
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🎙 *%s*\n\n", util.EscapeMarkdown(item.Title)))
+	if item.Author != "" {
+		sb.WriteString(fmt.Sprintf("👤 作者: %s\n", util.EscapeMarkdown(item.Author)))
+	}
+	if item.Library != "" {
+		sb.WriteString(fmt.Sprintf("📁 媒体库: %s\n", util.EscapeMarkdown(item.Library)))
+	}
+	subscribed := bm.podcastNotifier.IsSubscribed(serverType, podcastID, userID)
+	if subscribed {
+		sb.WriteString("🔔 已订阅新单集通知\n")
+	}
+	if item.Overview != "" {
+		sb.WriteString(fmt.Sprintf("\n📝 %s\n", util.EscapeMarkdown(truncateLabel(item.Overview, maxSearchOverviewLength))))
+	}
+
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	if subscribed {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("🔕 取消订阅",
+			bm.callbackData(actionPodcastUnsubscribe, string(serverType), podcastID))))
+	} else {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("🔔 订阅新单集",
+			bm.callbackData(actionPodcastSubscribe, string(serverType), podcastID))))
+	}
+	if bm.IsUserAdmin(userID) {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔄 检查新单集", bm.callbackData(actionPodcastCheckNew, string(serverType), podcastID)),
+			tgbotapi.NewInlineKeyboardButtonData("⬇️ 下载单集", bm.callbackData(actionPodcastFeed, string(serverType), podcastID))))
+	}
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回播客列表", "podcasts_list")))
+
+	bm.sendOrEditMessage(chatID, messageID, sb.String(), tgbotapi.NewInlineKeyboardMarkup(buttons...))
+}
+
+// SendPodcastFeed 列出订阅源中尚未下载的单集，点击加入下载队列
+func (bm *Manager) SendPodcastFeed(chatID int64, serverType services.MediaServerType, podcastID string) {
+	podcastManager, err := bm.podcastManager(serverType)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+
+	episodes, err := podcastManager.GetFeedEpisodes(podcastID)
+	if err != nil {
+		log.Printf("获取 %s 播客 %s 的订阅源失败: %v", serverType, podcastID, err)
+		bm.SendMessage(chatID, "❌ 获取订阅源失败: "+err.Error())
+		return
+	}
+
+	var pending []models.PodcastFeedEpisode
+	for _, episode := range episodes {
+		if !episode.Downloaded {
+			pending = append(pending, episode)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].PublishedAt > pending[j].PublishedAt
+	})
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("⬇️ *可下载的单集* (订阅源共 %d 集，已下载 %d 集)\n\n", len(episodes), len(episodes)-len(pending)))
+	if len(pending) == 0 {
+		sb.WriteString("✅ 所有单集都已下载\n")
+	}
+
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, episode := range pending {
+		if i >= podcastFeedLimit {
+			sb.WriteString(fmt.Sprintf("+ 还有 %d 集...\n", len(pending)-podcastFeedLimit))
+			break
+		}
+
+		sb.WriteString(fmt.Sprintf("%d. %s", i+1, util.EscapeMarkdown(episode.Title)))
+		if episode.PublishedAt > 0 {
+			sb.WriteString(" · " + time.UnixMilli(episode.PublishedAt).Format("2006-01-02"))
+		}
+		if episode.Duration != "" {
+			sb.WriteString(" · " + util.EscapeMarkdown(episode.Duration))
+		}
+		sb.WriteString("\n")
+
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("⬇️ %d", i+1),
+			bm.callbackData(actionPodcastDownload, string(serverType), podcastID, episode.Key)))
+		if len(row) == entityButtonsPerRow {
+			buttons = append(buttons, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		buttons = append(buttons, row)
+	}
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回播客", bm.callbackData(actionPodcastDetails, string(serverType), podcastID))))
+
+	bm.sendOrEditMessage(chatID, 0, sb.String(), tgbotapi.NewInlineKeyboardMarkup(buttons...))
+}
+
+// PromptForPodcastFeed 提示管理员输入要添加的播客 RSS 地址
+func (bm *Manager) PromptForPodcastFeed(chatID int64, userID int64) {
+	if !bm.IsUserAdmin(userID) {
+		bm.SendMessage(chatID, "🚫 只有管理员可以执行此操作")
+		return
+	}
+	bm.promptForInput(chatID, "➕ 请输入播客的 RSS 地址:", func(message *tgbotapi.Message) {
+		bm.AddPodcast(message.Chat.ID, message.From.ID, message.Text)
+	})
+}
+
+// AddPodcast 通过 RSS 地址添加播客到第一个支持播客的服务器
+func (bm *Manager) AddPodcast(chatID int64, userID int64, feedURL string) {
+	if !bm.IsUserAdmin(userID) {
+		bm.SendMessage(chatID, "🚫 只有管理员可以执行此操作")
+		return
+	}
+
+	feedURL = strings.TrimSpace(feedURL)
+	if u, err := url.Parse(feedURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		bm.SendMessage(chatID, "❌ 请输入有效的 http 或 https 地址")
+		return
+	}
+
+	managers := bm.podcastManagers()
+	for _, serverType := range bm.mediaServerManager.GetServerTypes() {
+		podcastManager, ok := managers[serverType]
+		if !ok {
+			continue
+		}
+
+		podcast, err := podcastManager.AddPodcast(feedURL)
+		if err != nil {
+			log.Printf("在 %s 中添加播客 %s 失败: %v", serverType, feedURL, err)
+			bm.SendMessage(chatID, "❌ 添加播客失败: "+err.Error())
+			return
+		}
+
+		text := fmt.Sprintf("✅ 已添加播客 *%s* 到 %s/%s", util.EscapeMarkdown(podcast.Title),
+			strings.Title(string(serverType)), util.EscapeMarkdown(podcast.Library))
+		bm.sendOrEditMessage(chatID, 0, text, tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🎙 查看播客", bm.callbackData(actionPodcastDetails, string(serverType), podcast.ID)))))
+		return
+	}
+
+	bm.SendMessage(chatID, "❌ 没有支持播客的服务器")
+}
+
+// handlePodcastAction 处理播客相关的回调
+func (bm *Manager) handlePodcastAction(callback *tgbotapi.CallbackQuery, action string, args []string) {
+	chatID := callback.Message.Chat.ID
+	userID := callback.From.ID
+	if len(args) < 2 {
+		log.Printf("无效的播客参数: %s %v", action, args)
+		return
+	}
+	serverType := services.MediaServerType(args[0])
+	podcastID := args[1]
+
+	switch action {
+	case actionPodcastDetails:
+		bm.SendPodcastDetails(chatID, 0, userID, serverType, podcastID)
+		return
+	case actionPodcastSubscribe, actionPodcastUnsubscribe:
+		bm.togglePodcastSubscription(callback, action, serverType, podcastID)
+		return
+	}
+
+	if !bm.IsUserAdmin(userID) {
+		bm.SendMessage(chatID, "🚫 只有管理员可以执行此操作")
+		return
+	}
+
+	switch action {
+	case actionPodcastFeed:
+		bm.SendPodcastFeed(chatID, serverType, podcastID)
+	case actionPodcastCheckNew:
+		podcastManager, err := bm.podcastManager(serverType)
+		if err != nil {
+			bm.SendMessage(chatID, "❌ "+err.Error())
+			return
+		}
+		episodes, err := podcastManager.CheckNewEpisodes(podcastID)
+		if err != nil {
+			log.Printf("检查 %s 播客 %s 的新单集失败: %v", serverType, podcastID, err)
+			bm.SendMessage(chatID, "❌ 检查新单集失败: "+err.Error())
+			return
+		}
+		if len(episodes) == 0 {
+			bm.SendMessage(chatID, "✅ 没有新单集")
+			return
+		}
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("🔄 找到 %d 个新单集，已加入下载队列:\n", len(episodes)))
+		for _, episode := range episodes {
+			sb.WriteString("• " + episode.Title + "\n")
+		}
+		bm.SendMessage(chatID, sb.String())
+	case actionPodcastDownload:
+		if len(args) < 3 {
+			log.Printf("无效的播客下载参数: %v", args)
+			return
+		}
+		podcastManager, err := bm.podcastManager(serverType)
+		if err != nil {
+			bm.SendMessage(chatID, "❌ "+err.Error())
+			return
+		}
+		// 单集标识可能是包含分隔符的地址，其余参数重新拼接
+		err = podcastManager.DownloadEpisode(podcastID, strings.Join(args[2:], ":"))
+		if err != nil {
+			log.Printf("下载 %s 播客 %s 的单集失败: %v", serverType, podcastID, err)
+			bm.SendMessage(chatID, "❌ 加入下载队列失败: "+err.Error())
+			return
+		}
+		bm.SendMessage(chatID, "✅ 已加入下载队列，下载完成后会出现在播客中")
+	default:
+		log.Printf("未知的播客操作: %s", action)
+	}
+}
+
+// togglePodcastSubscription 订阅或取消订阅播客的新单集通知，并刷新播客信息
+func (bm *Manager) togglePodcastSubscription(callback *tgbotapi.CallbackQuery, action string, serverType services.MediaServerType, podcastID string) {
+	chatID := callback.Message.Chat.ID
+	userID := callback.From.ID
+
+	var err error
+	if action == actionPodcastSubscribe {
+		title := ""
+		if server, serverErr := bm.mediaServerManager.GetServer(serverType); serverErr == nil {
+			if item, itemErr := server.GetItem(podcastID); itemErr == nil {
+				title = item.Title
+			}
+		}
+		err = bm.podcastNotifier.Subscribe(serverType, podcastID, title, userID)
+	} else {
+		err = bm.podcastNotifier.Unsubscribe(serverType, podcastID, userID)
+	}
+	if err != nil {
+		log.Printf("更新播客订阅失败: %v", err)
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+
+	bm.SendPodcastDetails(chatID, callback.Message.MessageID, userID, serverType, podcastID)
+}
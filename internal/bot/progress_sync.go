@@ -0,0 +1,59 @@
+package bot
+
+import (
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// actionProgressSyncApply 确认执行试运行中列出的进度同步
+const actionProgressSyncApply = "psync"
+
+// SendProgressSync 比较各服务器上同一作品的收听进度，默认只试运行并列出将要进行的更新，参数为 apply 时直接写入
+func (bm *Manager) SendProgressSync(chatID int64, userID int64, args string) {
+	if !bm.IsUserAdmin(userID) {
+		bm.SendMessage(chatID, "🚫 只有管理员可以同步播放进度")
+		return
+	}
+
+	status, err := bm.Bot.Send(tgbotapi.NewMessage(chatID, "🔄 正在比较各服务器的播放进度，请稍候..."))
+	if err != nil {
+		log.Printf("发送进度同步状态消息失败: %v", err)
+		return
+	}
+
+	dryRun := strings.TrimSpace(strings.ToLower(args)) != "apply"
+	go bm.runProgressSync(chatID, status.MessageID, dryRun)
+}
+
+// handleProgressSyncAction 处理试运行报告中的执行按钮
+func (bm *Manager) handleProgressSyncAction(callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	if !bm.IsUserAdmin(callback.From.ID) {
+		bm.SendMessage(chatID, "🚫 只有管理员可以同步播放进度")
+		return
+	}
+
+	bm.EditMessage(chatID, callback.Message.MessageID, "🔄 正在同步播放进度，请稍候...")
+	go bm.runProgressSync(chatID, callback.Message.MessageID, false)
+}
+
+// runProgressSync 执行同步并将报告显示在状态消息中，试运行有待更新项目时附带执行按钮
+func (bm *Manager) runProgressSync(chatID int64, messageID int, dryRun bool) {
+	report, err := bm.progressSync.Sync(dryRun)
+	if err != nil {
+		bm.EditMessage(chatID, messageID, "❌ 同步播放进度失败: "+err.Error())
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, report.Format())
+	if dryRun && len(report.Updates) > 0 {
+		menu := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ 执行同步", actionProgressSyncApply)))
+		edit.ReplyMarkup = &menu
+	}
+	if err := editBotMessage(bm.Bot, edit); err != nil {
+		log.Printf("编辑进度同步报告失败: %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+)
+
+// 媒体请求相关的回调动作
+const (
+	actionRequestPick    = "req_pick"
+	actionRequestApprove = "req_ok"
+	actionRequestDeny    = "req_no"
+)
+
+const (
+	// requestCandidateLimit 搜索元数据后列出的最大候选数量
+	requestCandidateLimit = 8
+	// requestListLimit 请求列表中显示的最大数量
+	requestListLimit = 20
+	// requestOverviewLength 候选简介的最大长度
+	requestOverviewLength = 80
+)
+
+// requestKindNames 请求作品类型的显示名称
+var requestKindNames = map[string]string{
+	models.MediaKindMovie:  "🎬 电影",
+	models.MediaKindSeries: "📺 剧集",
+	models.MediaKindBook:   "📖 书籍",
+}
+
+// PromptForRequest 提示用户输入要请求的作品名称
+func (bm *Manager) PromptForRequest(chatID int64) {
+	bm.promptForInput(chatID, "📝 请输入您想要请求的电影、剧集或书籍名称:", func(message *tgbotapi.Message) {
+		bm.SendRequestCandidates(message.Chat.ID, message.Text)
+	})
+}
+
+// SendRequestCandidates 在元数据服务中搜索作品，列出候选供用户选择
+func (bm *Manager) SendRequestCandidates(chatID int64, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		bm.PromptForRequest(chatID)
+		return
+	}
+	if !bm.requests.HasProviders() {
+		bm.SendMessage(chatID, "❌ 未配置元数据服务，无法提交请求")
+		return
+	}
+
+	status, err := bm.Bot.Send(tgbotapi.NewMessage(chatID, "🔎 正在查找作品信息，请稍候..."))
+	if err != nil {
+		log.Printf("发送请求状态消息失败: %v", err)
+		return
+	}
+
+	go func() {
+		candidates, err := bm.requests.LookupMetadata(text)
+		if err != nil {
+			bm.EditMessage(chatID, status.MessageID, "❌ 查找作品信息失败: "+err.Error())
+			return
+		}
+		if len(candidates) == 0 {
+			bm.EditMessage(chatID, status.MessageID, fmt.Sprintf("📭 没有找到与 %q 相关的作品", text))
+			return
+		}
+		if len(candidates) > requestCandidateLimit {
+			candidates = candidates[:requestCandidateLimit]
+		}
+		bm.requestCandidates.Store(chatID, candidates)
+
+		var sb strings.Builder
+		sb.WriteString("📝 请选择您要请求的作品:\n\n")
+		var buttons []tgbotapi.InlineKeyboardButton
+		for i := range candidates {
+			candidate := &candidates[i]
+			sb.WriteString(fmt.Sprintf("%d. %s %s\n", i+1, requestKindNames[candidate.Kind], services.FormatMetadataTitle(candidate)))
+			if overview := []rune(candidate.Overview); len(overview) > 0 {
+				if len(overview) > requestOverviewLength {
+					overview = append(overview[:requestOverviewLength], '…')
+				}
+				sb.WriteString("   " + string(overview) + "\n")
+			}
+			buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(
+				strconv.Itoa(i+1), bm.callbackData(actionRequestPick, strconv.Itoa(i))))
+		}
+
+		var rows [][]tgbotapi.InlineKeyboardButton
+		for i := 0; i < len(buttons); i += entityButtonsPerRow {
+			end := i + entityButtonsPerRow
+			if end > len(buttons) {
+				end = len(buttons)
+			}
+			rows = append(rows, buttons[i:end])
+		}
+
+		edit := tgbotapi.NewEditMessageText(chatID, status.MessageID, sb.String())
+		menu := tgbotapi.NewInlineKeyboardMarkup(rows...)
+		edit.ReplyMarkup = &menu
+		if err := editBotMessage(bm.Bot, edit); err != nil {
+			log.Printf("编辑请求候选消息失败: %v", err)
+		}
+	}()
+}
+
+// handleRequestAction 处理选择候选和审核请求的按钮
+func (bm *Manager) handleRequestAction(callback *tgbotapi.CallbackQuery, action string, args []string) {
+	if len(args) < 1 {
+		log.Printf("无效的请求参数: %v", args)
+		return
+	}
+
+	switch action {
+	case actionRequestPick:
+		index, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Printf("无效的候选序号: %v", args)
+			return
+		}
+		bm.submitRequest(callback, index)
+	case actionRequestApprove, actionRequestDeny:
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Printf("无效的请求 ID: %v", args)
+			return
+		}
+		bm.reviewRequest(callback, action, id)
+	}
+}
+
+// submitRequest 检查选中的作品是否已入库，未入库时记录请求并通知管理员审核
+func (bm *Manager) submitRequest(callback *tgbotapi.CallbackQuery, index int) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	value, ok := bm.requestCandidates.Load(chatID)
+	candidates, _ := value.([]models.MediaMetadata)
+	if !ok || index < 0 || index >= len(candidates) {
+		bm.EditMessage(chatID, messageID, "⌛ 候选列表已过期，请重新使用 /request 搜索")
+		return
+	}
+	bm.requestCandidates.Delete(chatID)
+	metadata := candidates[index]
+	title := services.FormatMetadataTitle(&metadata)
+
+	bm.EditMessage(chatID, messageID, fmt.Sprintf("🔎 正在检查服务器中是否已有 %s，请稍候...", title))
+	go func() {
+		if serverType, item, found := bm.requests.FindAvailable(&metadata); found {
+			edit := tgbotapi.NewEditMessageText(chatID, messageID,
+				fmt.Sprintf("✅ %s 已在 %s 中，无需请求", title, strings.Title(string(serverType))))
+			menu := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("ℹ️ 查看详情", bm.callbackData(actionItemDetails, string(serverType), item.ID))))
+			edit.ReplyMarkup = &menu
+			if err := editBotMessage(bm.Bot, edit); err != nil {
+				log.Printf("编辑请求结果失败: %v", err)
+			}
+			return
+		}
+
+		request, created, err := bm.requests.Create(callback.From.ID, requestUserName(callback.From), metadata)
+		if err != nil {
+			log.Printf("记录媒体请求失败: %v", err)
+			bm.EditMessage(chatID, messageID, "❌ "+err.Error())
+			return
+		}
+		if !created {
+			bm.EditMessage(chatID, messageID, fmt.Sprintf("ℹ️ %s 已有请求 #%d，当前状态: %s\n\n您已关注该请求，审核结果和入库时会通知您", title, request.ID, request.Status.Label()))
+			return
+		}
+
+		bm.EditMessage(chatID, messageID, fmt.Sprintf("📝 已提交请求 #%d: %s\n\n管理员审核后会通知您，入库后也会自动通知", request.ID, title))
+
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("📝 新的媒体请求 #%d\n\n", request.ID))
+		sb.WriteString(fmt.Sprintf("%s %s\n", requestKindNames[metadata.Kind], title))
+		sb.WriteString(fmt.Sprintf("来源: %s %s\n", metadata.Provider, metadata.ID))
+		sb.WriteString(fmt.Sprintf("请求者: %s", request.UserName))
+		menu := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ 批准", bm.callbackData(actionRequestApprove, strconv.Itoa(request.ID))),
+			tgbotapi.NewInlineKeyboardButtonData("🚫 拒绝", bm.callbackData(actionRequestDeny, strconv.Itoa(request.ID)))))
+		bm.notifyAdminsWithMenu(sb.String(), menu)
+	}()
+}
+
+// reviewRequest 管理员批准或拒绝请求，并通知请求者
+func (bm *Manager) reviewRequest(callback *tgbotapi.CallbackQuery, action string, id int) {
+	chatID := callback.Message.Chat.ID
+	if !bm.IsUserAdmin(callback.From.ID) {
+		bm.SendMessage(chatID, "🚫 只有管理员可以审核请求")
+		return
+	}
+
+	status := services.RequestApproved
+	if action == actionRequestDeny {
+		status = services.RequestDenied
+	}
+	request, err := bm.requests.SetStatus(id, status)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+
+	title := services.FormatMetadataTitle(&request.Metadata)
+	text := fmt.Sprintf("%s\n\n%s（%s）", callback.Message.Text, request.Status.Label(), requestUserName(callback.From))
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if status == services.RequestApproved {
+		if row := bm.acquireButtons(request); row != nil {
+			rows = append(rows, row)
+		}
+	}
+	bm.editWithButtons(chatID, callback.Message.MessageID, text, rows)
+
+	notice := fmt.Sprintf("👍 您的请求 #%d %s 已批准，入库后会通知您", request.ID, title)
+	if status == services.RequestDenied {
+		notice = fmt.Sprintf("🚫 您的请求 #%d %s 未被批准", request.ID, title)
+	}
+	for _, userID := range request.Requesters() {
+		bm.SendMessage(userID, notice)
+	}
+}
+
+// SendRequests 列出请求，管理员可以看到所有用户的请求
+func (bm *Manager) SendRequests(chatID int64, userID int64) {
+	owner := userID
+	if bm.IsUserAdmin(userID) {
+		owner = 0
+	}
+	requests := bm.requests.List(owner)
+	if len(requests) == 0 {
+		bm.SendMessage(chatID, "📭 暂无媒体请求，使用 /request 提交新的请求")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📝 媒体请求 (%d)\n\n", len(requests)))
+	for i, request := range requests {
+		if i >= requestListLimit {
+			sb.WriteString(fmt.Sprintf("\n… 还有 %d 个较早的请求", len(requests)-requestListLimit))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("#%d %s %s\n", request.ID, request.Status.Label(), services.FormatMetadataTitle(&request.Metadata)))
+		if owner == 0 {
+			requester := request.UserName
+			if len(request.Subscribers) > 0 {
+				requester += fmt.Sprintf(" 等 %d 人", len(request.Subscribers)+1)
+			}
+			sb.WriteString(fmt.Sprintf("   请求者: %s，%s\n", requester, request.CreatedAt.Format("2006-01-02")))
+		}
+	}
+	bm.SendMessage(chatID, sb.String())
+}
+
+// requestUserName 返回用户的显示名称
+func requestUserName(user *tgbotapi.User) string {
+	if user.UserName != "" {
+		return "@" + user.UserName
+	}
+	return strings.TrimSpace(user.FirstName + " " + user.LastName)
+}
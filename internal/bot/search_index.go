@@ -0,0 +1,72 @@
+package bot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+// formatSearchIndexStatus 格式化搜索索引状态，用于服务器信息
+func formatSearchIndexStatus(status services.SearchIndexStatus, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString("🔍 *搜索索引*:\n")
+
+	if !status.Enabled {
+		sb.WriteString("已关闭，使用实时搜索\n")
+		return sb.String()
+	}
+
+	for _, server := range status.Servers {
+		name := strings.Title(string(server.Server))
+		switch {
+		case server.SyncedAt.IsZero() && server.Error == "":
+			sb.WriteString(fmt.Sprintf("⏳ %s: 尚未同步，使用实时搜索\n", name))
+			continue
+		case server.SyncedAt.IsZero():
+			sb.WriteString(fmt.Sprintf("❌ %s: 同步失败，使用实时搜索\n", name))
+		case server.Fresh:
+			sb.WriteString(fmt.Sprintf("✅ %s: %d 个项目，%s同步\n", name, server.Items, formatAgo(now.Sub(server.SyncedAt))))
+		default:
+			sb.WriteString(fmt.Sprintf("⚠️ %s: %d 个项目，%s同步，已过期，使用实时搜索\n", name, server.Items, formatAgo(now.Sub(server.SyncedAt))))
+		}
+		if server.Error != "" {
+			sb.WriteString(fmt.Sprintf("   最近一次同步失败: %s\n", util.EscapeMarkdown(server.Error)))
+		}
+	}
+	if status.Syncing {
+		sb.WriteString("🔄 正在同步索引...\n")
+	}
+
+	return sb.String()
+}
+
+// formatSearchErrors 列出索引已过期且实时搜索失败的服务器，这些服务器的结果不完整
+func formatSearchErrors(errs map[services.MediaServerType]error) string {
+	if len(errs) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(errs))
+	for serverType := range errs {
+		names = append(names, strings.Title(string(serverType)))
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("⚠️ %s 搜索失败，结果中不包含这些服务器\n", strings.Join(names, "、"))
+}
+
+// formatAgo 将时间间隔格式化为 N分钟前 之类的相对时间
+func formatAgo(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "刚刚"
+	case d < time.Hour:
+		return fmt.Sprintf("%d分钟前", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%d小时前", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%d天前", int(d.Hours())/24)
+	}
+}
@@ -0,0 +1,333 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+const (
+	actionSeriesSeasons  = "ser"
+	actionSeasonEpisodes = "ser_ep"
+)
+
+const (
+	// seasonButtonsPerRow 每行显示的季按钮数量
+	seasonButtonsPerRow = 3
+	// episodeListLimit 一季最多列出的单集数量
+	episodeListLimit = 40
+	// episodeSearchSeriesLimit 单集搜索最多显示的剧集数量
+	episodeSearchSeriesLimit = 10
+	// episodeSearchPerSeries 单集搜索中每部剧集最多显示的单集数量
+	episodeSearchPerSeries = 5
+)
+
+// seriesBrowser 返回支持按季浏览剧集的服务器
+func (bm *Manager) seriesBrowser(serverType services.MediaServerType) (models.SeriesBrowser, error) {
+	server, err := bm.mediaServerManager.GetServer(serverType)
+	if err != nil {
+		return nil, err
+	}
+	browser, ok := server.(models.SeriesBrowser)
+	if !ok {
+		return nil, fmt.Errorf("%s 服务器不支持浏览剧集", strings.Title(string(serverType)))
+	}
+	return browser, nil
+}
+
+// seasonMarker 根据已观看的单集数量返回季的观看状态图标
+func seasonMarker(season models.Season) string {
+	switch {
+	case season.Played || (season.EpisodeCount > 0 && season.UnplayedCount == 0):
+		return "✅"
+	case season.UnplayedCount < season.EpisodeCount:
+		return "▶️"
+	default:
+		return "⬜"
+	}
+}
+
+// episodeMarker 返回单集的观看状态图标
+func episodeMarker(episode models.Episode) string {
+	switch {
+	case episode.Played:
+		return "✅"
+	case episode.Progress > 0:
+		return "▶️"
+	default:
+		return "⬜"
+	}
+}
+
+// episodeCode 返回 S01E02 格式的季号和集号，缺少集号时返回空字符串
+func episodeCode(seasonNumber, episodeNumber int) string {
+	if episodeNumber == 0 {
+		return ""
+	}
+	return fmt.Sprintf("S%02dE%02d", seasonNumber, episodeNumber)
+}
+
+// SendSeasons 列出剧集的所有季及关联用户的观看进度
+func (bm *Manager) SendSeasons(chatID int64, serverType services.MediaServerType, seriesID string) {
+	browser, err := bm.seriesBrowser(serverType)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+
+	seasons, err := browser.GetSeasons(seriesID)
+	if err != nil {
+		log.Printf("获取 %s 剧集 %s 的季失败: %v", serverType, seriesID, err)
+		bm.SendMessage(chatID, "❌ 获取剧集的季失败: "+err.Error())
+		return
+	}
+
+	var sb strings.Builder
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	sb.WriteString("📺 *剧集的季*\n\n")
+	if len(seasons) == 0 {
+		sb.WriteString("📭 没有找到任何季\n")
+	}
+
+	for _, season := range seasons {
+		sb.WriteString(fmt.Sprintf("%s *%s*", seasonMarker(season), util.EscapeMarkdown(season.Name)))
+		if season.EpisodeCount > 0 {
+			watched := season.EpisodeCount - season.UnplayedCount
+			sb.WriteString(fmt.Sprintf(" · 已看 %d/%d 集", watched, season.EpisodeCount))
+		}
+		sb.WriteString("\n")
+
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			truncateLabel(season.Name, maxButtonLabelLength),
+			bm.callbackData(actionSeasonEpisodes, string(serverType), seriesID, season.ID)))
+		if len(row) == seasonButtonsPerRow {
+			buttons = append(buttons, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		buttons = append(buttons, row)
+	}
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("ℹ️ 剧集详情", bm.callbackData(actionItemDetails, string(serverType), seriesID)),
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回主菜单", "main_menu")))
+
+	msg := tgbotapi.NewMessage(chatID, sb.String())
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(buttons...)
+	err = sendBotMessage(bm.Bot, msg)
+	if err != nil {
+		log.Printf("发送剧集季列表失败: %v", err)
+	}
+}
+
+// SendEpisodes 列出一季的所有单集及关联用户的观看状态
+func (bm *Manager) SendEpisodes(chatID int64, serverType services.MediaServerType, seriesID, seasonID string) {
+	browser, err := bm.seriesBrowser(serverType)
+	if err != nil {
+		bm.SendMessage(chatID, "❌ "+err.Error())
+		return
+	}
+
+	episodes, err := browser.GetEpisodes(seriesID, seasonID)
+	if err != nil {
+		log.Printf("获取 %s 剧集 %s 的单集失败: %v", serverType, seriesID, err)
+		bm.SendMessage(chatID, "❌ 获取单集列表失败: "+err.Error())
+		return
+	}
+
+	var sb strings.Builder
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	sb.WriteString("🎞 *单集列表*\n\n")
+	if len(episodes) == 0 {
+		sb.WriteString("📭 这一季没有单集\n")
+	}
+
+	for i, episode := range episodes {
+		if i >= episodeListLimit {
+			sb.WriteString(fmt.Sprintf("+ 还有 %d 集...\n", len(episodes)-episodeListLimit))
+			break
+		}
+
+		sb.WriteString(fmt.Sprintf("%d. %s ", i+1, episodeMarker(episode)))
+		if code := episodeCode(episode.SeasonNumber, episode.EpisodeNumber); code != "" {
+			sb.WriteString(code + " ")
+		}
+		sb.WriteString(util.EscapeMarkdown(episode.Name))
+		if !episode.Played && episode.Progress > 0 {
+			sb.WriteString(fmt.Sprintf(" (%.0f%%)", episode.Progress*100))
+		}
+		if episode.Duration > 0 {
+			sb.WriteString(" · " + util.FormatListeningTime(episode.Duration))
+		}
+		sb.WriteString("\n")
+
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("ℹ️ %d", i+1),
+			bm.callbackData(actionItemDetails, string(serverType), episode.ID)))
+		if len(row) == entityButtonsPerRow {
+			buttons = append(buttons, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		buttons = append(buttons, row)
+	}
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回季列表", bm.callbackData(actionSeriesSeasons, string(serverType), seriesID)),
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回主菜单", "main_menu")))
+
+	msg := tgbotapi.NewMessage(chatID, sb.String())
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(buttons...)
+	err = sendBotMessage(bm.Bot, msg)
+	if err != nil {
+		log.Printf("发送单集列表失败: %v", err)
+	}
+}
+
+// seriesButtons 返回项目详情中浏览剧集的按钮，剧集列出所有季，单集返回所属剧集
+func (bm *Manager) seriesButtons(serverType services.MediaServerType, item *models.SearchResult) [][]tgbotapi.InlineKeyboardButton {
+	if _, err := bm.seriesBrowser(serverType); err != nil {
+		return nil
+	}
+
+	switch {
+	case item.Type == models.SearchTypeSeries:
+		return [][]tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📂 查看季和剧集", bm.callbackData(actionSeriesSeasons, string(serverType), item.ID)))}
+	case item.SeriesID != "":
+		return [][]tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📺 所属剧集", bm.callbackData(actionSeriesSeasons, string(serverType), item.SeriesID)))}
+	}
+	return nil
+}
+
+// handleSeriesAction 处理浏览季和单集的回调
+func (bm *Manager) handleSeriesAction(callback *tgbotapi.CallbackQuery, action string, args []string) {
+	chatID := callback.Message.Chat.ID
+	switch {
+	case action == actionSeriesSeasons && len(args) >= 2:
+		bm.SendSeasons(chatID, services.MediaServerType(args[0]), args[1])
+	case action == actionSeasonEpisodes && len(args) >= 3:
+		bm.SendEpisodes(chatID, services.MediaServerType(args[0]), args[1], args[2])
+	default:
+		log.Printf("无效的剧集参数: %s %v", action, args)
+	}
+}
+
+// PromptForEpisodeSearch 提示用户输入要搜索的单集名称
+func (bm *Manager) PromptForEpisodeSearch(chatID int64) {
+	bm.promptForInput(chatID, "🎞 请输入要搜索的单集名称，可以使用与普通搜索相同的过滤条件:", func(message *tgbotapi.Message) {
+		bm.SendEpisodeSearch(message.Chat.ID, message.Text)
+	})
+}
+
+// episodeGroup 单集搜索结果中同一剧集下的单集
+type episodeGroup struct {
+	server   services.MediaServerType
+	seriesID string
+	name     string
+	episodes []models.SearchResult
+}
+
+// groupEpisodes 将单集按服务器和所属剧集分组，保持各服务器的结果顺序
+func (bm *Manager) groupEpisodes(results map[services.MediaServerType][]models.SearchResult) []*episodeGroup {
+	var groups []*episodeGroup
+	index := make(map[string]*episodeGroup)
+	for _, serverType := range bm.mediaServerManager.GetServerTypes() {
+		for _, result := range results[serverType] {
+			key := string(serverType) + ":" + result.SeriesID
+			if result.SeriesID == "" {
+				key = string(serverType) + ":name:" + result.SeriesName
+			}
+			group, ok := index[key]
+			if !ok {
+				name := result.SeriesName
+				if name == "" {
+					name = "未知剧集"
+				}
+				group = &episodeGroup{server: serverType, seriesID: result.SeriesID, name: name}
+				index[key] = group
+				groups = append(groups, group)
+			}
+			group.episodes = append(group.episodes, result)
+		}
+	}
+	return groups
+}
+
+// SendEpisodeSearch 只搜索单集，结果按所属剧集分组显示
+func (bm *Manager) SendEpisodeSearch(chatID int64, searchTerm string) {
+	searchTerm = strings.TrimSpace(searchTerm)
+	if searchTerm == "" {
+		bm.PromptForEpisodeSearch(chatID)
+		return
+	}
+
+	query, err := services.ParseSearchQuery(searchTerm)
+	if err != nil {
+		bm.SendMessage(chatID, fmt.Sprintf("❌ 搜索语法错误: %v\n\n%s", err, services.SearchSyntaxHelp))
+		return
+	}
+	query.Types = []string{models.SearchTypeEpisode}
+
+	results, errs := bm.searchIndex.Search(query)
+	for serverType, err := range errs {
+		log.Printf("搜索 %s 单集出错: %v", serverType, err)
+	}
+	groups := bm.groupEpisodes(results)
+
+	var sb strings.Builder
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	sb.WriteString(fmt.Sprintf("🎞 搜索单集 \"%s\" 的结果:\n\n", util.EscapeMarkdown(searchTerm)))
+	if len(groups) == 0 {
+		sb.WriteString("未找到相关单集。\n")
+	}
+
+	for i, group := range groups {
+		if i >= episodeSearchSeriesLimit {
+			sb.WriteString(fmt.Sprintf("+ 还有 %d 部剧集...\n", len(groups)-episodeSearchSeriesLimit))
+			break
+		}
+
+		sb.WriteString(fmt.Sprintf("%d. 📺 *%s* · %s (%d 集)\n", i+1, util.EscapeMarkdown(group.name),
+			strings.Title(string(group.server)), len(group.episodes)))
+		for j, episode := range group.episodes {
+			if j >= episodeSearchPerSeries {
+				sb.WriteString(fmt.Sprintf("   + 还有 %d 集...\n", len(group.episodes)-episodeSearchPerSeries))
+				break
+			}
+			sb.WriteString("   • ")
+			if code := episodeCode(episode.SeasonNumber, episode.EpisodeNumber); code != "" {
+				sb.WriteString(code + " ")
+			}
+			sb.WriteString(util.EscapeMarkdown(episode.Title) + "\n")
+		}
+		sb.WriteString("\n")
+
+		if group.seriesID != "" {
+			label := fmt.Sprintf("%d. 📂 %s", i+1, truncateLabel(group.name, maxEntityLabelLength))
+			buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(label, bm.callbackData(actionSeriesSeasons, string(group.server), group.seriesID))))
+		}
+	}
+	sb.WriteString(formatSearchErrors(errs))
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回主菜单", "main_menu")))
+
+	msg := tgbotapi.NewMessage(chatID, sb.String())
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(buttons...)
+	err = sendBotMessage(bm.Bot, msg)
+	if err != nil {
+		log.Printf("发送单集搜索结果失败: %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+const (
+	// statsRecentDays 个人统计中按天显示的天数
+	statsRecentDays = 7
+	// statsTopItems 个人统计中显示的热门项目数量
+	statsTopItems = 5
+	// statsTopGenres 个人统计中显示的热门分类数量
+	statsTopGenres = 5
+	// statsInProgressItems 个人统计中显示的进行中项目数量
+	statsInProgressItems = 3
+	// statsRecentSessions 个人统计中显示的最近会话数量
+	statsRecentSessions = 5
+)
+
+// formatPlaybackStats 格式化播放统计
+func formatPlaybackStats(stats *models.PlaybackStats, now time.Time) string {
+	var sb strings.Builder
+
+	totalLabel := "总播放时长"
+	if stats.Source == models.StatsSourceUserData {
+		// 用户数据只能按时长和次数估算
+		totalLabel = "估算播放时长"
+	}
+	sb.WriteString(fmt.Sprintf("   ⏱ %s: %.1f 小时\n", totalLabel, stats.TotalTime/3600))
+	sb.WriteString(fmt.Sprintf("   🎞 播放过的项目: %d\n", stats.ItemsPlayed))
+	sb.WriteString(fmt.Sprintf("   📅 今日: %s\n", util.FormatListeningTime(stats.Today(now))))
+	sb.WriteString(fmt.Sprintf("   🔥 连续天数: %d 天\n", stats.Streak(now)))
+
+	// 最近几天的播放时长，以最长的一天为基准绘制进度条
+	dayTotals := stats.RecentDays(now, statsRecentDays)
+	var maxSeconds float64
+	for _, day := range dayTotals {
+		if day.Seconds > maxSeconds {
+			maxSeconds = day.Seconds
+		}
+	}
+	if maxSeconds > 0 {
+		sb.WriteString(fmt.Sprintf("\n   *最近 %d 天*:\n", statsRecentDays))
+		for _, day := range dayTotals {
+			sb.WriteString(fmt.Sprintf("   `%s` %s %s\n", day.Date[5:], util.ProgressBar(day.Seconds/maxSeconds, 8), util.FormatListeningTime(day.Seconds)))
+		}
+	}
+
+	if len(stats.TopItems) > 0 {
+		sb.WriteString("\n   *最常播放*:\n")
+		for i, item := range stats.TopItems {
+			if i >= statsTopItems {
+				break
+			}
+			sb.WriteString(fmt.Sprintf("   %d. %s (%s)\n", i+1, formatStatsItemTitle(item), util.FormatListeningTime(item.PlayTime)))
+		}
+	}
+
+	if len(stats.TopGenres) > 0 {
+		var genres []string
+		for i, genre := range stats.TopGenres {
+			if i >= statsTopGenres {
+				break
+			}
+			genres = append(genres, util.EscapeMarkdown(genre.Genre))
+		}
+		sb.WriteString(fmt.Sprintf("\n   🏷️ 常看分类: %s\n", strings.Join(genres, ", ")))
+	}
+
+	if len(stats.InProgress) > 0 {
+		sb.WriteString("\n   *进行中*:\n")
+		for i, item := range stats.InProgress {
+			if i >= statsInProgressItems {
+				break
+			}
+			sb.WriteString(fmt.Sprintf("   • %s %s %d%%\n", formatStatsItemTitle(item), util.ProgressBar(item.Progress, 8), int(item.Progress*100)))
+		}
+	}
+
+	if len(stats.RecentSessions) > 0 {
+		sb.WriteString("\n   *最近播放*:\n")
+		for i, session := range stats.RecentSessions {
+			if i >= statsRecentSessions {
+				break
+			}
+			updatedAt := time.UnixMilli(session.UpdatedAt).Format("01-02 15:04")
+			sb.WriteString(fmt.Sprintf("   • %s %s (%s)\n", updatedAt, util.EscapeMarkdown(session.Title), util.FormatListeningTime(session.TimeListening)))
+		}
+	}
+
+	return sb.String()
+}
+
+// formatStatsItemTitle 格式化统计项目的标题和作者
+func formatStatsItemTitle(item models.StatsItem) string {
+	title := util.EscapeMarkdown(item.Title)
+	if item.Author != "" {
+		title += " - " + util.EscapeMarkdown(item.Author)
+	}
+	return title
+}
@@ -0,0 +1,256 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+// 下载客户端中种子的回调动作，参数为客户端名称和种子 hash
+const (
+	actionTorrentDetails       = "tor"
+	actionTorrentPause         = "tor_pause"
+	actionTorrentResume        = "tor_resume"
+	actionTorrentDelete        = "tor_del"
+	actionTorrentDeleteConfirm = "tor_delok"
+)
+
+// torrentListLimit 种子列表中显示的最大条目数
+const torrentListLimit = 20
+
+// clientTorrent 种子及其所在的下载客户端
+type clientTorrent struct {
+	client  string
+	torrent models.Torrent
+}
+
+// SendDownloads 显示所有下载客户端中的种子，包括进度、剩余时间和分享率
+func (bm *Manager) SendDownloads(chatID int64, messageID int, userID int64) {
+	if !bm.IsUserAdmin(userID) {
+		bm.SendMessage(chatID, "🚫 只有管理员可以管理下载客户端")
+		return
+	}
+	if !bm.torrentMonitor.HasClients() {
+		bm.SendMessage(chatID, "❌ 未配置 qBittorrent 或 Transmission")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("⬇️ *下载客户端*\n\n")
+
+	var torrents []clientTorrent
+	for _, result := range bm.torrentMonitor.Torrents() {
+		if result.Err != nil {
+			log.Printf("获取 %s 种子列表失败: %v", result.Client, result.Err)
+			sb.WriteString(fmt.Sprintf("❌ %s 获取失败\n", result.Client))
+			continue
+		}
+		for _, torrent := range result.Torrents {
+			torrents = append(torrents, clientTorrent{client: result.Client, torrent: torrent})
+		}
+	}
+	if len(torrents) == 0 {
+		sb.WriteString("📭 没有种子\n")
+	}
+
+	var buttons [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, entry := range torrents {
+		if i >= torrentListLimit {
+			sb.WriteString(fmt.Sprintf("+ 还有 %d 个...\n", len(torrents)-torrentListLimit))
+			break
+		}
+
+		torrent := entry.torrent
+		sb.WriteString(fmt.Sprintf("%d. %s *%s* · %s\n   %s %s\n", i+1, torrentStateIcon(&torrent),
+			util.EscapeMarkdown(torrent.Name), entry.client, util.ProgressBar(torrent.Progress, 10),
+			util.EscapeMarkdown(formatTorrentStatus(&torrent))))
+
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d", i+1),
+			bm.callbackData(actionTorrentDetails, entry.client, torrent.ID)))
+		if len(row) == entityButtonsPerRow {
+			buttons = append(buttons, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		buttons = append(buttons, row)
+	}
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔄 刷新", "downloads_list"),
+		tgbotapi.NewInlineKeyboardButtonData("⬅ 返回主菜单", "main_menu")))
+
+	bm.sendOrEditMessage(chatID, messageID, sb.String(), tgbotapi.NewInlineKeyboardMarkup(buttons...))
+}
+
+// handleTorrentAction 查看、暂停、继续或删除种子
+func (bm *Manager) handleTorrentAction(callback *tgbotapi.CallbackQuery, action string, args []string) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	if !bm.IsUserAdmin(callback.From.ID) {
+		bm.SendMessage(chatID, "🚫 只有管理员可以管理下载客户端")
+		return
+	}
+	if len(args) < 2 {
+		log.Printf("无效的种子参数: %v", args)
+		return
+	}
+
+	client, err := bm.torrentMonitor.Client(args[0])
+	if err != nil {
+		bm.EditMessage(chatID, messageID, "❌ "+err.Error())
+		return
+	}
+	id := args[1]
+
+	switch action {
+	case actionTorrentDetails:
+		bm.sendTorrentDetails(chatID, messageID, client, id)
+	case actionTorrentPause:
+		if err := client.PauseTorrent(id); err != nil {
+			log.Printf("暂停种子 %s 失败: %v", id, err)
+			bm.SendMessage(chatID, fmt.Sprintf("❌ 暂停失败: %v", err))
+			return
+		}
+		bm.sendTorrentDetails(chatID, messageID, client, id)
+	case actionTorrentResume:
+		if err := client.ResumeTorrent(id); err != nil {
+			log.Printf("继续种子 %s 失败: %v", id, err)
+			bm.SendMessage(chatID, fmt.Sprintf("❌ 继续失败: %v", err))
+			return
+		}
+		bm.sendTorrentDetails(chatID, messageID, client, id)
+	case actionTorrentDelete:
+		torrent, err := findTorrent(client, id)
+		if err != nil {
+			bm.EditMessage(chatID, messageID, "❌ "+err.Error())
+			return
+		}
+		bm.editWithButtons(chatID, messageID, fmt.Sprintf("🗑 确定要从 %s 删除 %s 吗？", client.Name(), torrent.Name),
+			[][]tgbotapi.InlineKeyboardButton{
+				tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("仅删除种子",
+					bm.callbackData(actionTorrentDeleteConfirm, client.Name(), id, "0"))),
+				tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("删除种子和文件",
+					bm.callbackData(actionTorrentDeleteConfirm, client.Name(), id, "1"))),
+				tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("⬅ 取消",
+					bm.callbackData(actionTorrentDetails, client.Name(), id))),
+			})
+	case actionTorrentDeleteConfirm:
+		deleteFiles := len(args) >= 3 && args[2] == "1"
+		if err := client.DeleteTorrent(id, deleteFiles); err != nil {
+			log.Printf("删除种子 %s 失败: %v", id, err)
+			bm.EditMessage(chatID, messageID, fmt.Sprintf("❌ 删除失败: %v", err))
+			return
+		}
+		text := "✅ 已删除种子"
+		if deleteFiles {
+			text = "✅ 已删除种子和文件"
+		}
+		bm.editWithButtons(chatID, messageID, text, [][]tgbotapi.InlineKeyboardButton{
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("⬅ 返回", "downloads_list")),
+		})
+	}
+}
+
+// sendTorrentDetails 显示种子详情及操作按钮
+func (bm *Manager) sendTorrentDetails(chatID int64, messageID int, client models.TorrentClient, id string) {
+	torrent, err := findTorrent(client, id)
+	if err != nil {
+		bm.EditMessage(chatID, messageID, "❌ "+err.Error())
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s\n\n", torrentStateIcon(torrent), torrent.Name))
+	sb.WriteString(fmt.Sprintf("客户端: %s\n", client.Name()))
+	sb.WriteString(fmt.Sprintf("状态: %s\n", torrent.State))
+	sb.WriteString(fmt.Sprintf("进度: %s %.1f%%\n", util.ProgressBar(torrent.Progress, 10), torrent.Progress*100))
+	sb.WriteString(fmt.Sprintf("大小: %s\n", util.FormatBytes(torrent.Size)))
+	if !torrent.Completed {
+		sb.WriteString(fmt.Sprintf("剩余时间: %s\n", formatTorrentETA(torrent.ETA)))
+	}
+	sb.WriteString(fmt.Sprintf("分享率: %.2f\n", torrent.Ratio))
+	sb.WriteString(fmt.Sprintf("速度: ⬇️ %s/s ⬆️ %s/s\n", util.FormatBytes(torrent.DownloadSpeed), util.FormatBytes(torrent.UploadSpeed)))
+	if torrent.SavePath != "" {
+		sb.WriteString(fmt.Sprintf("保存路径: %s\n", torrent.SavePath))
+	}
+
+	toggle := tgbotapi.NewInlineKeyboardButtonData("⏸ 暂停", bm.callbackData(actionTorrentPause, client.Name(), torrent.ID))
+	if torrent.Paused {
+		toggle = tgbotapi.NewInlineKeyboardButtonData("▶️ 继续", bm.callbackData(actionTorrentResume, client.Name(), torrent.ID))
+	}
+	bm.editWithButtons(chatID, messageID, strings.TrimRight(sb.String(), "\n"), [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(toggle,
+			tgbotapi.NewInlineKeyboardButtonData("🗑 删除", bm.callbackData(actionTorrentDelete, client.Name(), torrent.ID))),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔄 刷新", bm.callbackData(actionTorrentDetails, client.Name(), torrent.ID)),
+			tgbotapi.NewInlineKeyboardButtonData("⬅ 返回", "downloads_list")),
+	})
+}
+
+// findTorrent 在下载客户端中按 hash 查找种子
+func findTorrent(client models.TorrentClient, id string) (*models.Torrent, error) {
+	torrents, err := client.GetTorrents()
+	if err != nil {
+		return nil, fmt.Errorf("获取 %s 种子列表失败: %w", client.Name(), err)
+	}
+	for i := range torrents {
+		if strings.EqualFold(torrents[i].ID, id) {
+			return &torrents[i], nil
+		}
+	}
+	return nil, fmt.Errorf("种子已不在 %s 中", client.Name())
+}
+
+// torrentStateIcon 返回种子状态对应的图标
+func torrentStateIcon(torrent *models.Torrent) string {
+	switch {
+	case torrent.Paused:
+		return "⏸"
+	case torrent.Completed:
+		return "✅"
+	default:
+		return "⬇️"
+	}
+}
+
+// formatTorrentStatus 格式化种子列表中的进度、剩余时间、分享率和速度
+func formatTorrentStatus(torrent *models.Torrent) string {
+	parts := []string{fmt.Sprintf("%.1f%%", torrent.Progress*100)}
+	if !torrent.Completed {
+		parts = append(parts, "剩余 "+formatTorrentETA(torrent.ETA))
+	}
+	parts = append(parts, fmt.Sprintf("分享率 %.2f", torrent.Ratio))
+	if !torrent.Paused && torrent.DownloadSpeed > 0 {
+		parts = append(parts, fmt.Sprintf("⬇️ %s/s", util.FormatBytes(torrent.DownloadSpeed)))
+	}
+	if !torrent.Paused && torrent.UploadSpeed > 0 {
+		parts = append(parts, fmt.Sprintf("⬆️ %s/s", util.FormatBytes(torrent.UploadSpeed)))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// formatTorrentETA 格式化剩余时间，未知时显示 ∞
+func formatTorrentETA(eta int64) string {
+	if eta < 0 {
+		return "∞"
+	}
+	return util.FormatDuration(time.Duration(eta) * time.Second)
+}
+
+// notifyTorrent 向管理员发送下载完成通知，媒体服务器已入库时附带查看项目的按钮
+func (bm *Manager) notifyTorrent(text string, serverType services.MediaServerType, itemID string) {
+	if itemID == "" {
+		bm.notifyAdmins(text)
+		return
+	}
+	bm.notifyAdminsWithMenu(text, tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("ℹ️ 查看详情", bm.callbackData(actionItemDetails, string(serverType), itemID)))))
+}
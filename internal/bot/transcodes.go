@@ -0,0 +1,203 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+const (
+	// defaultTranscodeReportDays 转码报告默认统计天数
+	defaultTranscodeReportDays = 7
+	// maxReportGroups 转码报告中每类最多显示的用户或客户端数量
+	maxReportGroups = 5
+	// maxReportReasons 每个用户或客户端最多显示的转码原因数量
+	maxReportReasons = 3
+)
+
+// transcodeReasonNames 常见转码原因的中文说明
+var transcodeReasonNames = map[string]string{
+	"ContainerNotSupported":        "容器不支持",
+	"VideoCodecNotSupported":       "视频编码不支持",
+	"AudioCodecNotSupported":       "音频编码不支持",
+	"SubtitleCodecNotSupported":    "字幕格式不支持",
+	"AudioIsExternal":              "外部音轨",
+	"SecondaryAudioNotSupported":   "次要音轨不支持",
+	"VideoProfileNotSupported":     "视频配置不支持",
+	"VideoLevelNotSupported":       "视频级别不支持",
+	"VideoResolutionNotSupported":  "分辨率不支持",
+	"VideoBitDepthNotSupported":    "视频位深不支持",
+	"VideoFramerateNotSupported":   "帧率不支持",
+	"RefFramesNotSupported":        "参考帧不支持",
+	"AnamorphicVideoNotSupported":  "变形视频不支持",
+	"InterlacedVideoNotSupported":  "隔行视频不支持",
+	"AudioChannelsNotSupported":    "声道数不支持",
+	"AudioProfileNotSupported":     "音频配置不支持",
+	"AudioSampleRateNotSupported":  "采样率不支持",
+	"AudioBitDepthNotSupported":    "音频位深不支持",
+	"ContainerBitrateExceedsLimit": "码率超出限制",
+	"VideoBitrateNotSupported":     "视频码率不支持",
+	"AudioBitrateNotSupported":     "音频码率不支持",
+	"UnknownVideoStreamInfo":       "视频流信息未知",
+	"UnknownAudioStreamInfo":       "音频流信息未知",
+	"DirectPlayError":              "直接播放出错",
+	"Unknown":                      "未知原因",
+}
+
+// transcodeReasonName 返回转码原因的中文说明
+func transcodeReasonName(reason string) string {
+	if name, ok := transcodeReasonNames[reason]; ok {
+		return name
+	}
+	return reason
+}
+
+// SendTranscodeReport 发送转码统计报告
+func (bm *Manager) SendTranscodeReport(chatID int64, userID int64, args string) {
+	if !bm.IsUserAdmin(userID) {
+		bm.SendMessage(chatID, "🚫 只有管理员可以查看转码报告")
+		return
+	}
+
+	days := defaultTranscodeReportDays
+	if args = strings.TrimSpace(args); args != "" {
+		n, err := strconv.Atoi(args)
+		if err != nil || n <= 0 {
+			bm.SendMessage(chatID, "❌ 天数格式不正确，示例: /transcodes 30")
+			return
+		}
+		days = n
+	}
+
+	report := bm.transcodeMonitor.Report(days)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔁 *最近 %d 天转码报告*:\n\n", report.Days))
+
+	if report.TotalTranscodes == 0 {
+		sb.WriteString("📭 没有转码记录\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("🎞 转码播放次数: %d\n", report.TotalTranscodes))
+
+		serverTypes := make([]string, 0, len(report.PeakConcurrent))
+		for serverType := range report.PeakConcurrent {
+			serverTypes = append(serverTypes, string(serverType))
+		}
+		sort.Strings(serverTypes)
+		for _, serverType := range serverTypes {
+			sb.WriteString(fmt.Sprintf("📈 %s 峰值并发: %d\n", strings.Title(serverType), report.PeakConcurrent[services.MediaServerType(serverType)]))
+		}
+		if report.Limit > 0 {
+			sb.WriteString(fmt.Sprintf("⚠️ 超过限制 (%d) 的采样次数: %d\n", report.Limit, report.OverLimitSamples))
+		}
+
+		sb.WriteString("\n*主要转码原因*:\n")
+		for i, reason := range report.Reasons {
+			if i >= maxReportGroups {
+				break
+			}
+			sb.WriteString(fmt.Sprintf("• %s: %d\n", transcodeReasonName(reason.Reason), reason.Count))
+		}
+
+		sb.WriteString("\n*按用户*:\n")
+		writeReasonGroups(&sb, report.ByUser)
+
+		sb.WriteString("\n*按客户端*:\n")
+		writeReasonGroups(&sb, report.ByClient)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, sb.String())
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = CreateMainMenu()
+	err := sendBotMessage(bm.Bot, msg)
+	if err != nil {
+		log.Printf("发送转码报告失败: %v", err)
+	}
+}
+
+// writeReasonGroups 按转码次数输出各分组的主要转码原因
+func writeReasonGroups(sb *strings.Builder, groups map[string][]services.ReasonCount) {
+	type group struct {
+		name    string
+		total   int
+		reasons []services.ReasonCount
+	}
+
+	sorted := make([]group, 0, len(groups))
+	for name, reasons := range groups {
+		g := group{name: name, reasons: reasons}
+		for _, reason := range reasons {
+			g.total += reason.Count
+		}
+		sorted = append(sorted, g)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].total != sorted[j].total {
+			return sorted[i].total > sorted[j].total
+		}
+		return sorted[i].name < sorted[j].name
+	})
+
+	for i, g := range sorted {
+		if i >= maxReportGroups {
+			sb.WriteString(fmt.Sprintf("+ 还有 %d 个...\n", len(sorted)-maxReportGroups))
+			break
+		}
+		var parts []string
+		for j, reason := range g.reasons {
+			if j >= maxReportReasons {
+				break
+			}
+			parts = append(parts, fmt.Sprintf("%s ×%d", transcodeReasonName(reason.Reason), reason.Count))
+		}
+		sb.WriteString(fmt.Sprintf("• %s: %s\n", util.EscapeMarkdown(g.name), strings.Join(parts, ", ")))
+	}
+}
+
+// notifyAdmins 向所有管理员发送通知
+func (bm *Manager) notifyAdmins(text string) {
+	for _, userID := range bm.adminRecipients(text) {
+		bm.SendMessage(userID, text)
+	}
+}
+
+// notifyUser 向用户发送通知，已被移出 ALLOWED_USER_IDS 的用户不再接收
+func (bm *Manager) notifyUser(userID int64, text string) {
+	if !bm.IsUserAllowed(userID) {
+		log.Printf("用户 %d 已无权使用机器人，不发送通知", userID)
+		return
+	}
+	bm.SendMessage(userID, text)
+}
+
+// notifyAdminsWithMenu 向所有管理员发送带按钮的通知
+func (bm *Manager) notifyAdminsWithMenu(text string, menu tgbotapi.InlineKeyboardMarkup) {
+	for _, userID := range bm.adminRecipients(text) {
+		msg := tgbotapi.NewMessage(userID, text)
+		msg.ReplyMarkup = menu
+		if err := sendBotMessage(bm.Bot, msg); err != nil {
+			log.Printf("发送管理员通知失败: %v", err)
+		}
+	}
+}
+
+// adminRecipients 返回通知的接收者，未配置管理员时不通知任何人
+func (bm *Manager) adminRecipients(text string) []int64 {
+	if len(bm.adminUserIDs) == 0 {
+		log.Printf("未配置管理员，不发送通知: %s", text)
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(bm.adminUserIDs))
+	for userID := range bm.adminUserIDs {
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs
+}
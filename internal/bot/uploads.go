@@ -0,0 +1,355 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/services"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+// 文件上传相关的回调动作
+const (
+	actionUploadTarget = "up"
+	actionUploadMeta   = "up_meta"
+	actionUploadCancel = "up_cancel"
+)
+
+const (
+	// uploadProgressInterval 上传进度消息的最短更新间隔
+	uploadProgressInterval = 3 * time.Second
+	// uploadVerifyAttempts 上传后在媒体库中查找新项目的次数
+	uploadVerifyAttempts = 6
+	// uploadVerifyInterval 两次查找之间的间隔，等待服务器扫描入库
+	uploadVerifyInterval = 10 * time.Second
+)
+
+// uploadTargetOption 可选择的上传目标
+type uploadTargetOption struct {
+	Server services.MediaServerType
+	Target models.UploadTarget
+}
+
+// pendingUpload 用户发送的等待选择上传目标的文件
+type pendingUpload struct {
+	FileID  string
+	Name    string
+	Size    int64
+	Title   string
+	Author  string
+	Targets []uploadTargetOption
+}
+
+// newPendingUpload 从消息中的文件、音频或视频创建待上传文件，消息不包含文件时返回 nil
+func newPendingUpload(message *tgbotapi.Message) *pendingUpload {
+	var upload *pendingUpload
+	switch {
+	case message.Document != nil:
+		upload = &pendingUpload{FileID: message.Document.FileID, Name: message.Document.FileName, Size: int64(message.Document.FileSize)}
+	case message.Audio != nil:
+		upload = &pendingUpload{FileID: message.Audio.FileID, Name: message.Audio.FileName, Size: int64(message.Audio.FileSize),
+			Title: message.Audio.Title, Author: message.Audio.Performer}
+	case message.Video != nil:
+		upload = &pendingUpload{FileID: message.Video.FileID, Name: message.Video.FileName, Size: int64(message.Video.FileSize)}
+	default:
+		return nil
+	}
+
+	if upload.Name == "" {
+		upload.Name = upload.FileID
+	}
+	if upload.Title == "" {
+		upload.Title = strings.TrimSuffix(upload.Name, path.Ext(upload.Name))
+	}
+	return upload
+}
+
+// uploadTargets 获取所有支持上传的服务器上的目标文件夹
+func (bm *Manager) uploadTargets() []uploadTargetOption {
+	var options []uploadTargetOption
+	for _, serverType := range bm.mediaServerManager.GetServerTypes() {
+		server, err := bm.mediaServerManager.GetServer(serverType)
+		if err != nil {
+			continue
+		}
+		uploader, ok := server.(models.FileUploader)
+		if !ok {
+			continue
+		}
+		targets, err := uploader.GetUploadTargets()
+		if err != nil {
+			log.Printf("获取 %s 的上传目标失败: %v", serverType, err)
+			continue
+		}
+		for _, target := range targets {
+			options = append(options, uploadTargetOption{Server: serverType, Target: target})
+		}
+	}
+	return options
+}
+
+// HandleUploadFile 处理用户发送的文件，检查权限和大小后让用户选择上传目标
+func (bm *Manager) HandleUploadFile(message *tgbotapi.Message, upload *pendingUpload) {
+	chatID := message.Chat.ID
+	if !bm.CanUpload(message.From.ID) {
+		bm.SendMessage(chatID, "🚫 您没有上传文件到媒体库的权限")
+		return
+	}
+	if upload.Size > bm.fileDownloadLimit {
+		bm.SendMessage(chatID, fmt.Sprintf("❌ 文件大小 %s 超过机器人可下载的上限 %s", util.FormatBytes(upload.Size), util.FormatBytes(bm.fileDownloadLimit)))
+		return
+	}
+
+	upload.Targets = bm.uploadTargets()
+	if len(upload.Targets) == 0 {
+		bm.SendMessage(chatID, "📭 没有可用的上传目标，请检查 Audiobookshelf 媒体库或 EMBY_UPLOAD_FOLDERS 配置")
+		return
+	}
+
+	bm.pendingUploads.Store(chatID, upload)
+	bm.sendUploadTargets(chatID, 0, upload)
+}
+
+// sendUploadTargets 发送或编辑上传目标选择菜单
+func (bm *Manager) sendUploadTargets(chatID int64, messageID int, upload *pendingUpload) {
+	var sb strings.Builder
+	sb.WriteString("📤 *上传文件到媒体库*\n\n")
+	sb.WriteString(fmt.Sprintf("文件: %s (%s)\n", util.EscapeMarkdown(upload.Name), util.FormatBytes(upload.Size)))
+	sb.WriteString(fmt.Sprintf("标题: %s\n", util.EscapeMarkdown(upload.Title)))
+	if upload.Author != "" {
+		sb.WriteString(fmt.Sprintf("作者: %s\n", util.EscapeMarkdown(upload.Author)))
+	}
+	sb.WriteString("\n请选择目标媒体库文件夹:")
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, option := range upload.Targets {
+		label := fmt.Sprintf("%s · %s · %s", strings.Title(string(option.Server)), option.Target.Library, path.Base(option.Target.Folder))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, bm.callbackData(actionUploadTarget, strconv.Itoa(i)))))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✏️ 修改标题/作者", actionUploadMeta),
+		tgbotapi.NewInlineKeyboardButtonData("❌ 取消", actionUploadCancel),
+	))
+
+	bm.sendOrEditMessage(chatID, messageID, sb.String(), tgbotapi.NewInlineKeyboardMarkup(rows...))
+}
+
+// handleUploadAction 处理上传目标选择菜单中的按钮
+func (bm *Manager) handleUploadAction(callback *tgbotapi.CallbackQuery, action string, args []string) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	value, ok := bm.pendingUploads.Load(chatID)
+	if !ok {
+		bm.EditMessage(chatID, messageID, "⌛ 上传请求已失效，请重新发送文件")
+		return
+	}
+	upload := value.(*pendingUpload)
+
+	switch action {
+	case actionUploadCancel:
+		bm.pendingUploads.Delete(chatID)
+		bm.EditMessage(chatID, messageID, "已取消上传")
+
+	case actionUploadMeta:
+		bm.promptForInput(chatID, "请输入标题和作者，用 “/” 分隔，例如: 三体 / 刘慈欣", func(message *tgbotapi.Message) {
+			// 输入前已选择目标开始上传或已取消时，不再修改
+			if current, ok := bm.pendingUploads.Load(chatID); !ok || current.(*pendingUpload) != upload {
+				bm.SendMessage(chatID, "⌛ 上传请求已失效，请重新发送文件")
+				return
+			}
+			title, author, hasAuthor := strings.Cut(message.Text, "/")
+			if title = strings.TrimSpace(title); title != "" {
+				upload.Title = title
+			}
+			if hasAuthor {
+				upload.Author = strings.TrimSpace(author)
+			}
+			bm.sendUploadTargets(chatID, 0, upload)
+		})
+
+	case actionUploadTarget:
+		if !bm.CanUpload(callback.From.ID) {
+			bm.SendMessage(chatID, "🚫 您没有上传文件到媒体库的权限")
+			return
+		}
+		index, err := strconv.Atoi(firstArg(args))
+		if err != nil || index < 0 || index >= len(upload.Targets) {
+			log.Printf("无效的上传目标参数: %v", args)
+			return
+		}
+		if _, running := bm.activeUploads.LoadOrStore(chatID, true); running {
+			bm.SendMessage(chatID, "⏳ 已有文件正在上传，请等待完成后再试")
+			return
+		}
+		bm.pendingUploads.Delete(chatID)
+
+		option := upload.Targets[index]
+		bm.EditMessage(chatID, messageID, fmt.Sprintf("⬇️ 正在从 Telegram 读取 %s...", upload.Name))
+		// 上传使用副本，避免与尚未回复的标题/作者输入同时读写
+		selected := *upload
+		go func() {
+			defer bm.activeUploads.Delete(chatID)
+			bm.uploadToLibrary(chatID, messageID, &selected, option)
+		}()
+	}
+}
+
+// firstArg 返回第一个参数，没有参数时返回空字符串
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
+// uploadToLibrary 将文件从 Telegram 转存到媒体库，触发扫描后确认新项目已入库
+func (bm *Manager) uploadToLibrary(chatID int64, statusMessageID int, upload *pendingUpload, option uploadTargetOption) {
+	server, err := bm.mediaServerManager.GetServer(option.Server)
+	if err != nil {
+		bm.EditMessage(chatID, statusMessageID, "❌ "+err.Error())
+		return
+	}
+	uploader, ok := server.(models.FileUploader)
+	if !ok {
+		bm.EditMessage(chatID, statusMessageID, fmt.Sprintf("❌ %s 服务器不支持上传文件", strings.Title(string(option.Server))))
+		return
+	}
+
+	body, err := bm.openTelegramFile(upload.FileID)
+	if err != nil {
+		log.Printf("读取 Telegram 文件 %s 失败: %v", upload.Name, err)
+		bm.EditMessage(chatID, statusMessageID, "❌ 读取 Telegram 文件失败: "+err.Error())
+		return
+	}
+	defer body.Close()
+
+	reader := &progressReader{
+		reader: body,
+		report: func(read int64) {
+			if upload.Size <= 0 {
+				bm.EditMessage(chatID, statusMessageID, fmt.Sprintf("⬆️ 正在上传 %s\n已上传 %s", upload.Name, util.FormatBytes(read)))
+				return
+			}
+			bm.EditMessage(chatID, statusMessageID, fmt.Sprintf("⬆️ 正在上传 %s\n%s %s / %s",
+				upload.Name, util.ProgressBar(float64(read)/float64(upload.Size), 10), util.FormatBytes(read), util.FormatBytes(upload.Size)))
+		},
+	}
+	err = uploader.UploadFile(option.Target, models.FileUpload{
+		Name:   upload.Name,
+		Size:   upload.Size,
+		Title:  upload.Title,
+		Author: upload.Author,
+		Reader: reader,
+	})
+	if err != nil {
+		log.Printf("上传文件 %s 到 %s 失败: %v", upload.Name, option.Target.Library, err)
+		bm.EditMessage(chatID, statusMessageID, "❌ 上传失败: "+err.Error())
+		return
+	}
+
+	bm.EditMessage(chatID, statusMessageID, fmt.Sprintf("✅ %s 已上传到 %s，正在扫描媒体库...", upload.Name, option.Target.Library))
+	if scanner, ok := server.(models.LibraryScanner); ok {
+		if err := scanner.ScanLibrary(option.Target.LibraryID); err != nil {
+			log.Printf("上传后扫描媒体库 %s 失败: %v", option.Target.Library, err)
+		}
+	}
+
+	item := bm.findUploadedItem(server, upload, option.Target)
+	if item == nil {
+		bm.EditMessage(chatID, statusMessageID, fmt.Sprintf("✅ %s 已上传到 %s\n\n⚠️ 暂未在媒体库中找到新项目，扫描可能仍在进行，请稍后搜索确认", upload.Name, option.Target.Library))
+		return
+	}
+
+	text := fmt.Sprintf("✅ *%s* 已入库\n\n媒体库: %s", util.EscapeMarkdown(item.Title), util.EscapeMarkdown(option.Target.Library))
+	menu := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("ℹ️ 查看详情", bm.callbackData(actionItemDetails, string(option.Server), item.ID))))
+	bm.sendOrEditMessage(chatID, statusMessageID, text, menu)
+}
+
+// openTelegramFile 打开用户发送到 Telegram 的文件的数据流，调用方负责关闭
+// 文件地址中包含机器人令牌，返回的错误不能带有地址
+func (bm *Manager) openTelegramFile(fileID string) (io.ReadCloser, error) {
+	fileURL, err := bm.Bot.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, stripURLError(err)
+	}
+
+	req, err := http.NewRequest("GET", fileURL, nil)
+	if err != nil {
+		return nil, stripURLError(err)
+	}
+	resp, err := bm.Bot.Client.Do(req)
+	if err != nil {
+		return nil, stripURLError(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("下载文件失败，状态码 %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// stripURLError 去掉请求错误中的地址，Telegram 的接口和文件地址都包含机器人令牌
+func stripURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s 请求失败: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
+
+// findUploadedItem 等待服务器扫描后在目标媒体库中按标题查找上传的项目
+func (bm *Manager) findUploadedItem(server models.MediaServer, upload *pendingUpload, target models.UploadTarget) *models.SearchResult {
+	title := util.NormalizeTitle(upload.Title)
+	for attempt := 0; attempt < uploadVerifyAttempts; attempt++ {
+		time.Sleep(uploadVerifyInterval)
+
+		results, err := server.Search(models.SearchQuery{Text: upload.Title})
+		if err != nil {
+			log.Printf("查找上传的项目 %s 失败: %v", upload.Title, err)
+			continue
+		}
+		for i := range results {
+			result := &results[i]
+			if result.LibraryID != "" && result.LibraryID != target.LibraryID {
+				continue
+			}
+			if util.NormalizeTitle(result.Title) == title {
+				return result
+			}
+		}
+	}
+	return nil
+}
+
+// progressReader 读取时按间隔报告已读取的字节数
+type progressReader struct {
+	reader     io.Reader
+	read       int64
+	lastReport time.Time
+	report     func(read int64)
+}
+
+// Read 实现 io.Reader 接口
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	if time.Since(r.lastReport) >= uploadProgressInterval {
+		r.lastReport = time.Now()
+		r.report(r.read)
+	}
+	return n, err
+}
@@ -0,0 +1,75 @@
+package chart
+
+import (
+	"fmt"
+	"image"
+)
+
+// BarChart 绘制柱状图并返回 PNG 数据，labels 与 values 一一对应
+func BarChart(title string, labels []string, values []float64) ([]byte, error) {
+	if len(labels) != len(values) {
+		return nil, fmt.Errorf("labels and values length mismatch: %d != %d", len(labels), len(values))
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no data to plot")
+	}
+
+	img := newCanvas()
+	drawTitle(img, title)
+
+	var maxValue float64
+	for _, v := range values {
+		if v > maxValue {
+			maxValue = v
+		}
+	}
+	maxValue = niceMax(maxValue)
+
+	// 绘图区域，左侧留给纵轴刻度，底部留给横轴标签
+	plot := image.Rect(90, 60, Width-20, Height-50)
+
+	// 纵轴刻度和网格线
+	const gridLines = 5
+	for i := 0; i <= gridLines; i++ {
+		v := maxValue * float64(i) / gridLines
+		y := plot.Max.Y - int(float64(plot.Dy())*float64(i)/gridLines)
+		if i > 0 {
+			hLine(img, plot.Min.X, plot.Max.X, y, gridColor)
+		}
+		label := formatValue(v)
+		drawText(img, plot.Min.X-10-textWidth(label), y-textHeight()/2, label, textColor)
+	}
+
+	// 柱子
+	slot := float64(plot.Dx()) / float64(len(values))
+	barWidth := int(slot * 0.7)
+	if barWidth < 1 {
+		barWidth = 1
+	}
+	for i, v := range values {
+		x := plot.Min.X + int(slot*float64(i)+(slot-float64(barWidth))/2)
+		h := int(float64(plot.Dy()) * v / maxValue)
+		if h > 0 {
+			fillRect(img, image.Rect(x, plot.Max.Y-h, x+barWidth, plot.Max.Y), barColor)
+		}
+	}
+
+	// 横轴标签过多时间隔显示
+	labelEvery := 1
+	for labelEvery*int(slot) < textWidth(labels[0])+8 {
+		labelEvery++
+	}
+	for i, label := range labels {
+		if i%labelEvery != 0 {
+			continue
+		}
+		x := plot.Min.X + int(slot*float64(i)+slot/2)
+		drawTextCentered(img, x, plot.Max.Y+10, label, textColor)
+	}
+
+	// 坐标轴
+	hLine(img, plot.Min.X, plot.Max.X, plot.Max.Y, axisColor)
+	vLine(img, plot.Min.X, plot.Min.Y, plot.Max.Y, axisColor)
+
+	return encodePNG(img)
+}
@@ -0,0 +1,169 @@
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// Width 图表宽度
+	Width = 800
+	// Height 图表高度
+	Height = 450
+	// textScale 文字放大倍数，基础字体在手机上过小
+	textScale = 2
+)
+
+var (
+	backgroundColor = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	axisColor       = color.RGBA{R: 96, G: 96, B: 96, A: 255}
+	gridColor       = color.RGBA{R: 225, G: 225, B: 225, A: 255}
+	textColor       = color.RGBA{R: 40, G: 40, B: 40, A: 255}
+	barColor        = color.RGBA{R: 85, G: 172, B: 238, A: 255}
+)
+
+// palette 分类颜色，与 Emoji 圆点颜色一一对应，方便在消息中说明图例
+var palette = []struct {
+	color color.RGBA
+	emoji string
+}{
+	{color.RGBA{R: 221, G: 46, B: 68, A: 255}, "🔴"},
+	{color.RGBA{R: 85, G: 172, B: 238, A: 255}, "🔵"},
+	{color.RGBA{R: 120, G: 177, B: 89, A: 255}, "🟢"},
+	{color.RGBA{R: 253, G: 203, B: 88, A: 255}, "🟡"},
+	{color.RGBA{R: 170, G: 142, B: 214, A: 255}, "🟣"},
+	{color.RGBA{R: 244, G: 144, B: 12, A: 255}, "🟠"},
+	{color.RGBA{R: 193, G: 105, B: 79, A: 255}, "🟤"},
+	{color.RGBA{R: 49, G: 55, B: 61, A: 255}, "⚫"},
+}
+
+// PaletteEmoji 返回第 i 个分类颜色对应的 Emoji
+func PaletteEmoji(i int) string {
+	return palette[i%len(palette)].emoji
+}
+
+// paletteColor 返回第 i 个分类颜色
+func paletteColor(i int) color.RGBA {
+	return palette[i%len(palette)].color
+}
+
+// newCanvas 创建白色背景的画布
+func newCanvas() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: backgroundColor}, image.Point{}, draw.Src)
+	return img
+}
+
+// encodePNG 将图片编码为 PNG
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("error encoding png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fillRect 填充矩形
+func fillRect(img *image.RGBA, rect image.Rectangle, c color.Color) {
+	draw.Draw(img, rect, &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+// hLine 绘制水平线
+func hLine(img *image.RGBA, x0, x1, y int, c color.Color) {
+	fillRect(img, image.Rect(x0, y, x1+1, y+1), c)
+}
+
+// vLine 绘制竖直线
+func vLine(img *image.RGBA, x, y0, y1 int, c color.Color) {
+	fillRect(img, image.Rect(x, y0, x+1, y1+1), c)
+}
+
+// textWidth 返回文字放大后的宽度
+func textWidth(text string) int {
+	face := basicfont.Face7x13
+	return font.MeasureString(face, text).Ceil() * textScale
+}
+
+// textHeight 返回文字放大后的高度
+func textHeight() int {
+	return basicfont.Face7x13.Height * textScale
+}
+
+// drawText 以 (x, y) 为左上角绘制放大后的文字，只支持 ASCII 字符
+func drawText(img *image.RGBA, x, y int, text string, c color.Color) {
+	face := basicfont.Face7x13
+	w := font.MeasureString(face, text).Ceil()
+	h := face.Height
+	if w == 0 {
+		return
+	}
+
+	// 先以原始大小绘制，再按最近邻放大到画布上
+	small := image.NewAlpha(image.Rect(0, 0, w, h))
+	drawer := font.Drawer{
+		Dst:  small,
+		Src:  image.Opaque,
+		Face: face,
+		Dot:  fixed.P(0, face.Ascent),
+	}
+	drawer.DrawString(text)
+
+	for sy := 0; sy < h; sy++ {
+		for sx := 0; sx < w; sx++ {
+			if small.AlphaAt(sx, sy).A < 128 {
+				continue
+			}
+			fillRect(img, image.Rect(x+sx*textScale, y+sy*textScale, x+(sx+1)*textScale, y+(sy+1)*textScale), c)
+		}
+	}
+}
+
+// drawTextCentered 以 x 为中心绘制文字
+func drawTextCentered(img *image.RGBA, x, y int, text string, c color.Color) {
+	drawText(img, x-textWidth(text)/2, y, text, c)
+}
+
+// drawTitle 在图表顶部居中绘制标题
+func drawTitle(img *image.RGBA, title string) {
+	drawTextCentered(img, Width/2, 12, title, textColor)
+}
+
+// formatValue 格式化坐标轴上的数值
+func formatValue(v float64) string {
+	switch {
+	case v == 0:
+		return "0"
+	case v >= 10:
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return fmt.Sprintf("%.1f", v)
+	}
+}
+
+// niceMax 将最大值向上取整到便于阅读的刻度
+func niceMax(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	step := 1.0
+	for v/step > 10 {
+		step *= 10
+	}
+	for v/step < 1 {
+		step /= 10
+	}
+	for _, m := range []float64{1, 2, 2.5, 5, 10} {
+		if m*step >= v {
+			return m * step
+		}
+	}
+	return 10 * step
+}
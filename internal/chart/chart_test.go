@@ -0,0 +1,154 @@
+package chart
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// update 重新生成 testdata 中的参考图片: go test ./internal/chart -update
+var update = flag.Bool("update", false, "update golden images")
+
+// assertGolden 比较渲染结果与 testdata 中的参考图片，比较解码后的像素而不是 PNG 字节，避免编码器差异
+func assertGolden(t *testing.T, name string, data []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".png")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden image: %v (run with -update to create it)", err)
+	}
+	got := decodePNG(t, data)
+	expected := decodePNG(t, want)
+	if got.Bounds() != expected.Bounds() {
+		t.Fatalf("bounds = %v, want %v", got.Bounds(), expected.Bounds())
+	}
+	bounds := got.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r1, g1, b1, a1 := got.At(x, y).RGBA()
+			r2, g2, b2, a2 := expected.At(x, y).RGBA()
+			if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+				t.Fatalf("pixel (%d, %d) differs from %s", x, y, path)
+			}
+		}
+	}
+}
+
+func decodePNG(t *testing.T, data []byte) image.Image {
+	t.Helper()
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decoding png: %v", err)
+	}
+	return img
+}
+
+func TestBarChart(t *testing.T) {
+	data, err := BarChart("Listening (min)", []string{"10-01", "10-02", "10-03", "10-04", "10-05", "10-06", "10-07"},
+		[]float64{35, 0, 120, 64.5, 12, 240, 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "bar", data)
+}
+
+func TestBarChartEmpty(t *testing.T) {
+	data, err := BarChart("Empty", []string{"a", "b"}, []float64{0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "bar_empty", data)
+}
+
+func TestHeatmap(t *testing.T) {
+	rows := []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+	cols := make([]string, 24)
+	values := make([][]float64, len(rows))
+	for hour := range cols {
+		if hour%6 == 0 {
+			cols[hour] = fmt.Sprintf("%02d", hour)
+		}
+	}
+	for day := range values {
+		values[day] = make([]float64, len(cols))
+		for hour := range values[day] {
+			values[day][hour] = float64((day*7 + hour*3) % 11)
+		}
+	}
+
+	data, err := Heatmap("Weekday / hour", rows, cols, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "heatmap", data)
+}
+
+func TestHeatmapMismatchedRows(t *testing.T) {
+	if _, err := Heatmap("Bad", []string{"a", "b"}, []string{"x"}, [][]float64{{1}}); err == nil {
+		t.Fatal("expected error for mismatched rows")
+	}
+}
+
+func TestPieChart(t *testing.T) {
+	data, err := PieChart("Libraries", []Slice{
+		{Label: "Movies", Value: 420},
+		{Label: "Shows", Value: 180},
+		{Label: "Audiobooks", Value: 95},
+		{Label: "Podcasts", Value: 12},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "pie", data)
+}
+
+func TestPieChartNegative(t *testing.T) {
+	_, err := PieChart("Bad", []Slice{{Label: "a", Value: 5}, {Label: "b", Value: -1}})
+	if err == nil || !strings.Contains(err.Error(), "negative value for slice b") {
+		t.Fatalf("err = %v, want negative value error", err)
+	}
+}
+
+func TestPieChartEmpty(t *testing.T) {
+	_, err := PieChart("Empty", []Slice{{Label: "a", Value: 0}})
+	if err == nil || !strings.Contains(err.Error(), "no data") {
+		t.Fatalf("err = %v, want no data error", err)
+	}
+}
+
+func TestMergeSmallSlices(t *testing.T) {
+	var slices []Slice
+	for i := 0; i < maxPieSlices+3; i++ {
+		slices = append(slices, Slice{Label: string(rune('a' + i)), Value: float64(100 - i)})
+	}
+	merged := MergeSmallSlices(slices)
+	if len(merged) > maxPieSlices {
+		t.Fatalf("len = %d, want at most %d", len(merged), maxPieSlices)
+	}
+	var total, mergedTotal float64
+	for _, s := range slices {
+		total += s.Value
+	}
+	for _, s := range merged {
+		mergedTotal += s.Value
+	}
+	if total != mergedTotal {
+		t.Fatalf("merged total = %v, want %v", mergedTotal, total)
+	}
+}
@@ -0,0 +1,83 @@
+package chart
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+var (
+	heatmapLow  = color.RGBA{R: 235, G: 237, B: 240, A: 255}
+	heatmapHigh = color.RGBA{R: 33, G: 110, B: 57, A: 255}
+)
+
+// Heatmap 绘制热力图并返回 PNG 数据，values[row][col] 对应 rowLabels 和 colLabels
+// colLabels 中的空字符串表示该列不显示标签
+func Heatmap(title string, rowLabels, colLabels []string, values [][]float64) ([]byte, error) {
+	if len(values) != len(rowLabels) {
+		return nil, fmt.Errorf("rows and row labels length mismatch: %d != %d", len(values), len(rowLabels))
+	}
+	for i, row := range values {
+		if len(row) != len(colLabels) {
+			return nil, fmt.Errorf("row %d and column labels length mismatch: %d != %d", i, len(row), len(colLabels))
+		}
+	}
+	if len(values) == 0 || len(colLabels) == 0 {
+		return nil, fmt.Errorf("no data to plot")
+	}
+
+	img := newCanvas()
+	drawTitle(img, title)
+
+	var maxValue float64
+	for _, row := range values {
+		for _, v := range row {
+			if v > maxValue {
+				maxValue = v
+			}
+		}
+	}
+
+	plot := image.Rect(80, 60, Width-20, Height-50)
+	cellW := plot.Dx() / len(colLabels)
+	cellH := plot.Dy() / len(rowLabels)
+
+	for r, row := range values {
+		y := plot.Min.Y + r*cellH
+		drawText(img, plot.Min.X-10-textWidth(rowLabels[r]), y+(cellH-textHeight())/2, rowLabels[r], textColor)
+
+		for c, v := range row {
+			x := plot.Min.X + c*cellW
+			ratio := 0.0
+			if maxValue > 0 {
+				ratio = v / maxValue
+			}
+			// 留出 2 像素间隙区分格子
+			fillRect(img, image.Rect(x+1, y+1, x+cellW-1, y+cellH-1), lerpColor(heatmapLow, heatmapHigh, ratio))
+		}
+	}
+
+	for c, label := range colLabels {
+		if label == "" {
+			continue
+		}
+		x := plot.Min.X + c*cellW + cellW/2
+		drawTextCentered(img, x, plot.Min.Y+len(rowLabels)*cellH+10, label, textColor)
+	}
+
+	return encodePNG(img)
+}
+
+// lerpColor 在两个颜色之间线性插值，ratio 取值范围为 0 到 1
+func lerpColor(from, to color.RGBA, ratio float64) color.RGBA {
+	if ratio < 0 {
+		ratio = 0
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*ratio + 0.5)
+	}
+	return color.RGBA{R: mix(from.R, to.R), G: mix(from.G, to.G), B: mix(from.B, to.B), A: 255}
+}
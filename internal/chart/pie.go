@@ -0,0 +1,107 @@
+package chart
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+)
+
+// maxPieSlices 饼图最多显示的分块数量，其余合并为 Other
+const maxPieSlices = 8
+
+// Slice 饼图中的一个分块
+type Slice struct {
+	Label string
+	Value float64
+}
+
+// PieChart 绘制饼图并返回 PNG 数据
+// 图中只标注百分比，分块名称可通过 PaletteEmoji 在消息文字中说明
+func PieChart(title string, slices []Slice) ([]byte, error) {
+	for _, s := range slices {
+		if s.Value < 0 {
+			return nil, fmt.Errorf("negative value for slice %s", s.Label)
+		}
+	}
+	slices = MergeSmallSlices(slices)
+
+	var total float64
+	for _, s := range slices {
+		total += s.Value
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("no data to plot")
+	}
+
+	img := newCanvas()
+	drawTitle(img, title)
+
+	cx, cy := 260, Height/2+20
+	radius := 170
+
+	// 计算每个分块的结束角度，从正上方顺时针排列
+	ends := make([]float64, len(slices))
+	var acc float64
+	for i, s := range slices {
+		acc += s.Value
+		ends[i] = acc / total * 2 * math.Pi
+	}
+
+	for y := cy - radius; y <= cy+radius; y++ {
+		for x := cx - radius; x <= cx+radius; x++ {
+			dx, dy := float64(x-cx), float64(y-cy)
+			if dx*dx+dy*dy > float64(radius*radius) {
+				continue
+			}
+			angle := math.Atan2(dx, -dy)
+			if angle < 0 {
+				angle += 2 * math.Pi
+			}
+			for i, end := range ends {
+				if angle <= end {
+					img.SetRGBA(x, y, paletteColor(i))
+					break
+				}
+			}
+		}
+	}
+
+	// 右侧图例：颜色块和百分比
+	legendX := cx + radius + 60
+	legendY := cy - len(slices)*textHeight()*3/4
+	for i, s := range slices {
+		y := legendY + i*textHeight()*3/2
+		fillRect(img, image.Rect(legendX, y+4, legendX+textHeight()-8, y+textHeight()-4), paletteColor(i))
+		label := fmt.Sprintf("%5.1f%%  %.0f", s.Value/total*100, s.Value)
+		drawText(img, legendX+textHeight(), y, label, textColor)
+	}
+
+	return encodePNG(img)
+}
+
+// MergeSmallSlices 超出最大分块数时，将最小的分块合并为 Other，并去掉为 0 的分块
+func MergeSmallSlices(slices []Slice) []Slice {
+	result := make([]Slice, 0, len(slices))
+	for _, s := range slices {
+		if s.Value > 0 {
+			result = append(result, s)
+		}
+	}
+	if len(result) <= maxPieSlices {
+		return result
+	}
+
+	// 按数值从大到小排序后合并尾部
+	sorted := make([]Slice, len(result))
+	copy(sorted, result)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Value > sorted[j].Value
+	})
+
+	other := Slice{Label: "Other"}
+	for _, s := range sorted[maxPieSlices-1:] {
+		other.Value += s.Value
+	}
+	return append(sorted[:maxPieSlices-1], other)
+}
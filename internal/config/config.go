@@ -20,9 +20,61 @@ type Config struct {
 	EmbyURL             string
 	EmbyToken           string
 	EmbyPort            int
+	EmbyUser            string
 	Debug               bool
 	ProxyAddress        string
 	AllowedUserIDs      []int64
+	AdminUserIDs        []int64
+	DataDir             string
+
+	// 转码监控配置
+	TranscodeLimit          int
+	TranscodeSampleInterval int // 秒
+	TranscodeHistoryDays    int
+
+	// 搜索索引配置
+	SearchIndexInterval int // 分钟
+	SearchIndexMaxAge   int // 分钟
+
+	// 播客新单集检查间隔
+	PodcastCheckInterval int // 分钟
+
+	// 文件下载配置
+	DownloadUserIDs     []int64
+	TelegramUploadLimit int // MB
+
+	// 文件上传配置
+	UploadUserIDs         []int64
+	TelegramDownloadLimit int               // MB
+	EmbyUploadFolders     map[string]string // Emby 媒体库名称 -> 机器人可写入的本地文件夹
+
+	// 播放进度同步配置
+	ProgressSyncInterval int // 分钟
+	ProgressSyncDryRun   bool
+
+	// 媒体请求配置
+	TmdbAPIKey           string
+	TmdbLanguage         string
+	RequestProviders     []string
+	RequestCheckInterval int // 分钟
+
+	// 下载管理配置
+	RadarrURL        string
+	RadarrAPIKey     string
+	SonarrURL        string
+	SonarrAPIKey     string
+	ReadarrURL       string
+	ReadarrAPIKey    string
+	ArrCheckInterval int // 分钟
+
+	// 下载客户端配置
+	QbittorrentURL        string
+	QbittorrentUser       string
+	QbittorrentPassword   string
+	TransmissionURL       string
+	TransmissionUser      string
+	TransmissionPassword  string
+	DownloadCheckInterval int // 分钟
 }
 
 // LoadConfig loads configuration from environment variables
@@ -32,6 +84,8 @@ func LoadConfig() *Config {
 
 	// 解析允许的用户ID列表
 	allowedUserIDs := parseAllowedUserIDs(getEnvWithDefault("ALLOWED_USER_IDS", ""))
+	// 解析管理员用户ID列表
+	adminUserIDs := parseAllowedUserIDs(getEnvWithDefault("ADMIN_USER_IDS", ""))
 
 	config := &Config{
 		TelegramBotToken:    getEnvWithDefault("TELEGRAM_BOT_TOKEN", ""),
@@ -39,9 +93,52 @@ func LoadConfig() *Config {
 		AudiobookshelfToken: getEnvWithDefault("AUDIOBOOKSHELF_TOKEN", ""),
 		EmbyURL:             getEnvWithDefault("EMBY_URL", ""),
 		EmbyToken:           getEnvWithDefault("EMBY_TOKEN", ""),
+		EmbyUser:            getEnvWithDefault("EMBY_USER", ""),
 		Debug:               getEnvWithDefault("DEBUG", "false") == "true",
 		ProxyAddress:        getEnvWithDefault("PROXY_ADDRESS", ""),
 		AllowedUserIDs:      allowedUserIDs,
+		AdminUserIDs:        adminUserIDs,
+		DataDir:             getEnvWithDefault("DATA_DIR", "data"),
+
+		TranscodeLimit:          getEnvInt("TRANSCODE_LIMIT", 2),
+		TranscodeSampleInterval: getEnvInt("TRANSCODE_SAMPLE_INTERVAL", 60),
+		TranscodeHistoryDays:    getEnvInt("TRANSCODE_HISTORY_DAYS", 30),
+
+		SearchIndexInterval: getEnvInt("SEARCH_INDEX_INTERVAL", 60),
+		SearchIndexMaxAge:   getEnvInt("SEARCH_INDEX_MAX_AGE", 180),
+
+		PodcastCheckInterval: getEnvInt("PODCAST_CHECK_INTERVAL", 15),
+
+		DownloadUserIDs:     parseAllowedUserIDs(getEnvWithDefault("DOWNLOAD_USER_IDS", "")),
+		TelegramUploadLimit: getEnvInt("TELEGRAM_UPLOAD_LIMIT", 50),
+
+		UploadUserIDs:         parseAllowedUserIDs(getEnvWithDefault("UPLOAD_USER_IDS", "")),
+		TelegramDownloadLimit: getEnvInt("TELEGRAM_DOWNLOAD_LIMIT", 20),
+		EmbyUploadFolders:     parseFolderMap(getEnvWithDefault("EMBY_UPLOAD_FOLDERS", "")),
+
+		ProgressSyncInterval: getEnvInt("PROGRESS_SYNC_INTERVAL", 0),
+		ProgressSyncDryRun:   getEnvWithDefault("PROGRESS_SYNC_DRY_RUN", "false") == "true",
+
+		TmdbAPIKey:           getEnvWithDefault("TMDB_API_KEY", ""),
+		TmdbLanguage:         getEnvWithDefault("TMDB_LANGUAGE", "zh-CN"),
+		RequestProviders:     parseList(getEnvWithDefault("REQUEST_PROVIDERS", "tmdb,openlibrary")),
+		RequestCheckInterval: getEnvInt("REQUEST_CHECK_INTERVAL", 30),
+
+		RadarrURL:        getEnvWithDefault("RADARR_URL", ""),
+		RadarrAPIKey:     getEnvWithDefault("RADARR_API_KEY", ""),
+		SonarrURL:        getEnvWithDefault("SONARR_URL", ""),
+		SonarrAPIKey:     getEnvWithDefault("SONARR_API_KEY", ""),
+		ReadarrURL:       getEnvWithDefault("READARR_URL", ""),
+		ReadarrAPIKey:    getEnvWithDefault("READARR_API_KEY", ""),
+		ArrCheckInterval: getEnvInt("ARR_CHECK_INTERVAL", 5),
+
+		QbittorrentURL:        getEnvWithDefault("QBITTORRENT_URL", ""),
+		QbittorrentUser:       getEnvWithDefault("QBITTORRENT_USER", ""),
+		QbittorrentPassword:   getEnvWithDefault("QBITTORRENT_PASSWORD", ""),
+		TransmissionURL:       getEnvWithDefault("TRANSMISSION_URL", ""),
+		TransmissionUser:      getEnvWithDefault("TRANSMISSION_USER", ""),
+		TransmissionPassword:  getEnvWithDefault("TRANSMISSION_PASSWORD", ""),
+		DownloadCheckInterval: getEnvInt("DOWNLOAD_CHECK_INTERVAL", 2),
 	}
 
 	// 处理Audiobookshelf端口
@@ -133,6 +230,15 @@ func getEnvWithDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvInt 获取整数类型的环境变量，不存在或无法解析时返回默认值
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(getEnvWithDefault(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
 // parseAllowedUserIDs 解析允许的用户ID列表
 func parseAllowedUserIDs(idsStr string) []int64 {
 	if idsStr == "" {
@@ -148,4 +254,29 @@ func parseAllowedUserIDs(idsStr string) []int64 {
 		}
 	}
 	return ids
-}
\ No newline at end of file
+}
+
+// parseFolderMap 解析 “名称=路径” 格式的列表，多个条目用逗号分隔
+func parseFolderMap(value string) map[string]string {
+	folders := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		name, path, ok := strings.Cut(entry, "=")
+		name, path = strings.TrimSpace(name), strings.TrimSpace(path)
+		if !ok || name == "" || path == "" {
+			continue
+		}
+		folders[name] = path
+	}
+	return folders
+}
+
+// parseList 解析逗号分隔的列表，忽略空条目
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
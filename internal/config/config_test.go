@@ -0,0 +1,27 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFolderMap(t *testing.T) {
+	tests := []struct {
+		value string
+		want  map[string]string
+	}{
+		{"", map[string]string{}},
+		{"电影=/mnt/movies", map[string]string{"电影": "/mnt/movies"}},
+		{" 电影 = /mnt/movies , 剧集=/mnt/tv ", map[string]string{"电影": "/mnt/movies", "剧集": "/mnt/tv"}},
+		{"Books=D:\\Media\\Books", map[string]string{"Books": "D:\\Media\\Books"}},
+		{"a=/x=y", map[string]string{"a": "/x=y"}},
+		{"missing,=/path,name=,电影=/mnt/movies,", map[string]string{"电影": "/mnt/movies"}},
+		{"电影=/old,电影=/new", map[string]string{"电影": "/new"}},
+	}
+
+	for _, tt := range tests {
+		if got := parseFolderMap(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseFolderMap(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 // AbsServerStatus 服务器状态信息
 type AbsServerStatus struct {
 	Success       bool   `json:"success"`
@@ -34,10 +36,15 @@ type AbsLibraryInfo struct {
 // 这些字段来自libraryItem对象
 // 现在添加libraryId字段以显示对应的媒体库，并使用relPath代替path以提高安全性
 type AbsBook struct {
-	LibraryID string `json:"libraryId"`
-	RelPath   string `json:"relPath"`
-	Size      int64  `json:"size"`
-	AddedAt   int64  `json:"addedAt"`
+	ID        string           `json:"id"`
+	LibraryID string           `json:"libraryId"`
+	Path      string           `json:"path"`
+	RelPath   string           `json:"relPath"`
+	Size      int64            `json:"size"`
+	AddedAt   int64            `json:"addedAt"`
+	MediaType string           `json:"mediaType"`
+	Duration  float64          `json:"duration"` // 秒
+	Metadata  AbsMediaMetadata `json:"metadata"`
 }
 
 // AbsServerInfo 服务器基本信息
@@ -59,13 +66,390 @@ type AbsServerInfo struct {
 
 // AbsUserInfo 用户信息
 type AbsUserInfo struct {
-	ID            string        `json:"id"`
-	Username      string        `json:"username"`
-	Type          string        `json:"type"`
-	Token         string        `json:"token,omitempty"`
-	IsActive      bool          `json:"isActive"`
-	LastSeen      int64         `json:"lastSeen"`
-	MediaProgress []interface{} `json:"mediaProgress"` // 根据实际情况调整类型
-	CreatedAt     int64         `json:"createdAt"`
-	UpdatedAt     int64         `json:"updatedAt"`
+	ID            string             `json:"id"`
+	Username      string             `json:"username"`
+	Type          string             `json:"type"`
+	Token         string             `json:"token,omitempty"`
+	IsActive      bool               `json:"isActive"`
+	LastSeen      int64              `json:"lastSeen"`
+	MediaProgress []AbsMediaProgress `json:"mediaProgress"`
+	CreatedAt     int64              `json:"createdAt"`
+	UpdatedAt     int64              `json:"updatedAt"`
+}
+
+// AbsPlaybackSession 播放会话信息
+type AbsPlaybackSession struct {
+	ID            string  `json:"id"`
+	UserID        string  `json:"userId"`
+	LibraryID     string  `json:"libraryId"`
+	LibraryItemID string  `json:"libraryItemId"`
+	EpisodeID     string  `json:"episodeId,omitempty"`
+	MediaType     string  `json:"mediaType"`
+	DisplayTitle  string  `json:"displayTitle"`
+	DisplayAuthor string  `json:"displayAuthor"`
+	Duration      float64 `json:"duration"` // 秒
+	PlayMethod    int     `json:"playMethod"`
+	MediaPlayer   string  `json:"mediaPlayer"`
+	DeviceInfo    struct {
+		DeviceID      string `json:"deviceId"`
+		ClientName    string `json:"clientName"`
+		ClientVersion string `json:"clientVersion"`
+		Manufacturer  string `json:"manufacturer"`
+		Model         string `json:"model"`
+		OSName        string `json:"osName"`
+		BrowserName   string `json:"browserName"`
+	} `json:"deviceInfo"`
+	TimeListening float64 `json:"timeListening"` // 秒
+	StartTime     float64 `json:"startTime"`     // 秒
+	CurrentTime   float64 `json:"currentTime"`   // 秒
+	StartedAt     int64   `json:"startedAt"`
+	UpdatedAt     int64   `json:"updatedAt"`
+	User          *struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"user,omitempty"`
+}
+
+// Audiobookshelf 播放方式
+const (
+	AbsPlayMethodDirectPlay   = 0
+	AbsPlayMethodDirectStream = 1
+	AbsPlayMethodTranscode    = 2
+	AbsPlayMethodLocal        = 3
+)
+
+// AbsAuthor 作者信息
+type AbsAuthor struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AbsSeriesSequence 系列及序号
+type AbsSeriesSequence struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Sequence string `json:"sequence"`
+}
+
+// AbsMediaMetadata 媒体元数据，书籍和播客共用
+type AbsMediaMetadata struct {
+	Title         string              `json:"title"`
+	Subtitle      string              `json:"subtitle,omitempty"`
+	Author        string              `json:"author,omitempty"` // 播客作者
+	Authors       []AbsAuthor         `json:"authors,omitempty"`
+	AuthorNames   string              `json:"authorName,omitempty"` // 精简模式下的作者名称
+	Narrators     []string            `json:"narrators,omitempty"`
+	NarratorNames string              `json:"narratorName,omitempty"` // 精简模式下的演播者名称
+	Series        []AbsSeriesSequence `json:"series,omitempty"`
+	SeriesNames   string              `json:"seriesName,omitempty"` // 精简模式下的系列，格式为 名称 #序号
+	Genres        []string            `json:"genres,omitempty"`
+	PublishedYear string              `json:"publishedYear,omitempty"`
+	Description   string              `json:"description,omitempty"`
+	ASIN          string              `json:"asin,omitempty"`
+	ISBN          string              `json:"isbn,omitempty"`
+	FeedURL       string              `json:"feedUrl,omitempty"`  // 播客的 RSS 地址
+	ImageURL      string              `json:"imageUrl,omitempty"` // 播客的封面地址
+}
+
+// AuthorName 返回以逗号分隔的作者名称
+func (m *AbsMediaMetadata) AuthorName() string {
+	if len(m.Authors) == 0 {
+		if m.AuthorNames != "" {
+			return m.AuthorNames
+		}
+		return m.Author
+	}
+	names := make([]string, len(m.Authors))
+	for i, author := range m.Authors {
+		names[i] = author.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// NarratorList 返回演播者列表，精简模式下从逗号分隔的名称中解析
+func (m *AbsMediaMetadata) NarratorList() []string {
+	if len(m.Narrators) > 0 || m.NarratorNames == "" {
+		return m.Narrators
+	}
+	var narrators []string
+	for _, name := range strings.Split(m.NarratorNames, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			narrators = append(narrators, name)
+		}
+	}
+	return narrators
+}
+
+// SeriesList 返回所属系列及序号，精简模式下从 “名称 #序号” 格式中解析
+func (m *AbsMediaMetadata) SeriesList() []SeriesEntry {
+	var series []SeriesEntry
+	if len(m.Series) > 0 {
+		for _, s := range m.Series {
+			series = append(series, SeriesEntry{Name: s.Name, Sequence: s.Sequence})
+		}
+		return series
+	}
+
+	for _, part := range strings.Split(m.SeriesNames, ", ") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		entry := SeriesEntry{Name: part}
+		if i := strings.LastIndex(part, " #"); i > 0 {
+			entry.Name = part[:i]
+			entry.Sequence = part[i+2:]
+		}
+		series = append(series, entry)
+	}
+	return series
+}
+
+// ProviderIDs 返回元数据中的 ASIN 和 ISBN
+func (m *AbsMediaMetadata) ProviderIDs() map[string]string {
+	ids := make(map[string]string)
+	if m.ASIN != "" {
+		ids[ProviderASIN] = m.ASIN
+	}
+	if m.ISBN != "" {
+		ids[ProviderISBN] = m.ISBN
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return ids
+}
+
+// AbsListeningStatsItem 收听统计中单个项目的收听时长
+type AbsListeningStatsItem struct {
+	ID            string           `json:"id"`
+	TimeListening float64          `json:"timeListening"` // 秒
+	MediaMetadata AbsMediaMetadata `json:"mediaMetadata"`
+}
+
+// AbsListeningStats /api/me/listening-stats 的响应，由 AbsAdapter 转换为 PlaybackStats
+type AbsListeningStats struct {
+	TotalTime      float64                          `json:"totalTime"` // 秒
+	Items          map[string]AbsListeningStatsItem `json:"items"`
+	Days           map[string]float64               `json:"days"`      // 日期(YYYY-MM-DD) -> 秒
+	DayOfWeek      map[string]float64               `json:"dayOfWeek"` // 星期(Monday) -> 秒
+	Today          float64                          `json:"today"`     // 秒
+	RecentSessions []AbsPlaybackSession             `json:"recentSessions"`
+}
+
+// AbsMediaProgress 用户的媒体播放进度
+type AbsMediaProgress struct {
+	ID                        string  `json:"id"`
+	LibraryItemID             string  `json:"libraryItemId"`
+	EpisodeID                 string  `json:"episodeId,omitempty"`
+	Duration                  float64 `json:"duration"` // 秒
+	Progress                  float64 `json:"progress"` // 0 到 1
+	CurrentTime               float64 `json:"currentTime"`
+	IsFinished                bool    `json:"isFinished"`
+	HideFromContinueListening bool    `json:"hideFromContinueListening"`
+	LastUpdate                int64   `json:"lastUpdate"`
+	StartedAt                 int64   `json:"startedAt"`
+	FinishedAt                int64   `json:"finishedAt,omitempty"`
+}
+
+// AbsCollection 合集，只能包含同一媒体库中的书籍
+type AbsCollection struct {
+	ID          string           `json:"id"`
+	LibraryID   string           `json:"libraryId"`
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Books       []AbsLibraryItem `json:"books"`
+}
+
+// AbsPlaylistItem 播放列表中的书籍或播客单集
+type AbsPlaylistItem struct {
+	LibraryItemID string             `json:"libraryItemId"`
+	EpisodeID     string             `json:"episodeId,omitempty"`
+	LibraryItem   *AbsLibraryItem    `json:"libraryItem,omitempty"`
+	Episode       *AbsPodcastEpisode `json:"episode,omitempty"`
+}
+
+// AbsPlaylist 用户的播放列表，只能包含同一媒体库中的项目
+type AbsPlaylist struct {
+	ID        string            `json:"id"`
+	LibraryID string            `json:"libraryId"`
+	Name      string            `json:"name"`
+	Items     []AbsPlaylistItem `json:"items"`
+}
+
+// AbsProgressUpdate 更新播放进度的请求
+type AbsProgressUpdate struct {
+	Duration    float64 `json:"duration,omitempty"`
+	Progress    float64 `json:"progress"`
+	CurrentTime float64 `json:"currentTime"`
+	IsFinished  bool    `json:"isFinished"`
+}
+
+// AbsLibraryItem 媒体库项目
+type AbsLibraryItem struct {
+	ID        string `json:"id"`
+	LibraryID string `json:"libraryId"`
+	FolderID  string `json:"folderId"`
+	Path      string `json:"path"`
+	RelPath   string `json:"relPath"`
+	MediaType string `json:"mediaType"`
+	AddedAt   int64  `json:"addedAt"`
+	UpdatedAt int64  `json:"updatedAt"`
+	Size      int64  `json:"size"`
+	Media     struct {
+		Metadata AbsMediaMetadata `json:"metadata"`
+		Duration float64          `json:"duration"` // 秒
+		Tags     []string         `json:"tags,omitempty"`
+
+		// 文件和章节
+		AudioFiles []AbsAudioFile `json:"audioFiles,omitempty"`
+		EbookFile  *AbsEbookFile  `json:"ebookFile,omitempty"`
+		Chapters   []AbsChapter   `json:"chapters,omitempty"`
+
+		// 播客相关字段
+		Episodes             []AbsPodcastEpisode `json:"episodes,omitempty"`
+		NumEpisodes          int                 `json:"numEpisodes,omitempty"` // 精简模式下的单集数量
+		AutoDownloadEpisodes bool                `json:"autoDownloadEpisodes,omitempty"`
+	} `json:"media"`
+	ProgressLastUpdate int64 `json:"progressLastUpdate,omitempty"`
+}
+
+// Book 转换为 AbsBook
+func (item *AbsLibraryItem) Book() AbsBook {
+	return AbsBook{
+		ID:        item.ID,
+		LibraryID: item.LibraryID,
+		Path:      item.Path,
+		RelPath:   item.RelPath,
+		Size:      item.Size,
+		AddedAt:   item.AddedAt,
+		MediaType: item.MediaType,
+		Duration:  item.Media.Duration,
+		Metadata:  item.Media.Metadata,
+	}
+}
+
+// AbsTask 后台任务信息，如媒体库扫描
+type AbsTask struct {
+	ID     string `json:"id"`
+	Action string `json:"action"`
+	Data   struct {
+		LibraryID   string `json:"libraryId"`
+		LibraryName string `json:"libraryName"`
+	} `json:"data"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Error       string `json:"error,omitempty"`
+	IsFailed    bool   `json:"isFailed"`
+	IsFinished  bool   `json:"isFinished"`
+	StartedAt   int64  `json:"startedAt"`
+	FinishedAt  int64  `json:"finishedAt,omitempty"`
+}
+
+// AbsTaskActionLibraryScan 媒体库扫描任务的动作名称
+const AbsTaskActionLibraryScan = "library-scan"
+
+// AbsPodcastEnclosure 播客单集的音频文件地址
+type AbsPodcastEnclosure struct {
+	URL    string `json:"url"`
+	Type   string `json:"type,omitempty"`
+	Length string `json:"length,omitempty"`
+}
+
+// AbsPodcastEpisode 已下载到媒体库的播客单集
+type AbsPodcastEpisode struct {
+	ID            string               `json:"id"`
+	LibraryItemID string               `json:"libraryItemId"`
+	PodcastID     string               `json:"podcastId"`
+	Index         int                  `json:"index"`
+	Season        string               `json:"season,omitempty"`
+	Episode       string               `json:"episode,omitempty"`
+	Title         string               `json:"title"`
+	Description   string               `json:"description,omitempty"`
+	Enclosure     *AbsPodcastEnclosure `json:"enclosure,omitempty"`
+	GUID          string               `json:"guid,omitempty"`
+	PublishedAt   int64                `json:"publishedAt"`
+	AddedAt       int64                `json:"addedAt"`
+	Duration      float64              `json:"duration"` // 秒
+	Size          int64                `json:"size"`
+	AudioFile     *AbsAudioFile        `json:"audioFile,omitempty"`
+	// Podcast 最新单集列表中附带的播客信息
+	Podcast *struct {
+		Metadata AbsMediaMetadata `json:"metadata"`
+	} `json:"podcast,omitempty"`
+}
+
+// AbsPodcastFeedEpisode RSS 订阅源中的单集，下载时需要原样提交
+type AbsPodcastFeedEpisode struct {
+	Title       string               `json:"title"`
+	Subtitle    string               `json:"subtitle,omitempty"`
+	Description string               `json:"description,omitempty"`
+	PubDate     string               `json:"pubDate,omitempty"`
+	PublishedAt int64                `json:"publishedAt,omitempty"`
+	Season      string               `json:"season,omitempty"`
+	Episode     string               `json:"episode,omitempty"`
+	EpisodeType string               `json:"episodeType,omitempty"`
+	GUID        string               `json:"guid,omitempty"`
+	Duration    string               `json:"duration,omitempty"` // RSS 中的原始时长，如 01:02:03
+	Enclosure   *AbsPodcastEnclosure `json:"enclosure,omitempty"`
+}
+
+// AbsPodcastFeed 解析后的 RSS 订阅源，对应 /api/podcasts/feed
+type AbsPodcastFeed struct {
+	Metadata struct {
+		Title       string   `json:"title"`
+		Author      string   `json:"author,omitempty"`
+		Description string   `json:"description,omitempty"`
+		FeedURL     string   `json:"feedUrl"`
+		ImageURL    string   `json:"imageUrl,omitempty"`
+		ITunesID    string   `json:"itunesId,omitempty"`
+		Language    string   `json:"language,omitempty"`
+		Explicit    bool     `json:"explicit,omitempty"`
+		Categories  []string `json:"categories,omitempty"`
+	} `json:"metadata"`
+	Episodes []AbsPodcastFeedEpisode `json:"episodes"`
+}
+
+// AbsNewPodcast 创建播客的请求，对应 POST /api/podcasts
+type AbsNewPodcast struct {
+	Path      string `json:"path"`
+	FolderID  string `json:"folderId"`
+	LibraryID string `json:"libraryId"`
+	Media     struct {
+		Metadata             AbsMediaMetadata `json:"metadata"`
+		AutoDownloadEpisodes bool             `json:"autoDownloadEpisodes"`
+	} `json:"media"`
+}
+
+// AbsFileMetadata 文件的基本信息
+type AbsFileMetadata struct {
+	Filename string `json:"filename"`
+	Ext      string `json:"ext"`
+	Path     string `json:"path"`
+	RelPath  string `json:"relPath"`
+	Size     int64  `json:"size"`
+}
+
+// AbsAudioFile 书籍或播客单集的音频文件
+type AbsAudioFile struct {
+	Index    int             `json:"index"`
+	Ino      string          `json:"ino"`
+	Metadata AbsFileMetadata `json:"metadata"`
+	Duration float64         `json:"duration"` // 秒
+	MimeType string          `json:"mimeType"`
+}
+
+// AbsEbookFile 书籍的电子书文件
+type AbsEbookFile struct {
+	Ino         string          `json:"ino"`
+	Metadata    AbsFileMetadata `json:"metadata"`
+	EbookFormat string          `json:"ebookFormat"`
+}
+
+// AbsChapter 书籍的章节，时间相对于整本书
+type AbsChapter struct {
+	ID    int     `json:"id"`
+	Start float64 `json:"start"` // 秒
+	End   float64 `json:"end"`   // 秒
+	Title string  `json:"title"`
 }
@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// MediaAcquirer 下载管理服务（Radarr、Sonarr 等），用于添加批准的请求并跟踪下载
+type MediaAcquirer interface {
+	// Name 返回服务名称，如 Radarr
+	Name() string
+
+	// Kind 返回负责的作品类型，如 movie
+	Kind() string
+
+	// GetQualityProfiles 获取质量配置
+	GetQualityProfiles() ([]ArrProfile, error)
+
+	// GetRootFolders 获取根文件夹
+	GetRootFolders() ([]ArrRootFolder, error)
+
+	// Add 添加作品并开始搜索下载，作品已存在时返回已有的条目
+	Add(metadata *MediaMetadata, options ArrAddOptions) (*ArrItem, error)
+
+	// GetQueue 获取下载队列
+	GetQueue() ([]ArrQueueItem, error)
+
+	// GetImportedSince 获取指定时间之后导入完成的下载
+	GetImportedSince(since time.Time) ([]ArrImport, error)
+}
+
+// MetadataProfileProvider 添加作品时需要选择元数据配置的下载管理服务（Readarr）
+type MetadataProfileProvider interface {
+	// GetMetadataProfiles 获取元数据配置
+	GetMetadataProfiles() ([]ArrProfile, error)
+}
+
+// ArrProfile 质量配置或元数据配置
+type ArrProfile struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ArrRootFolder 根文件夹
+type ArrRootFolder struct {
+	ID        int    `json:"id"`
+	Path      string `json:"path"`
+	FreeSpace int64  `json:"freeSpace"`
+}
+
+// ArrAddOptions 添加作品的选项
+type ArrAddOptions struct {
+	QualityProfileID  int
+	MetadataProfileID int // 仅 Readarr 使用
+	RootFolderPath    string
+}
+
+// ArrItem 下载管理服务中的作品
+type ArrItem struct {
+	ID      int    `json:"id"`
+	Title   string `json:"title"`
+	Year    int    `json:"year"`
+	Existed bool   `json:"-"` // 添加前已存在
+}
+
+// ArrQueueItem 下载队列中的条目
+type ArrQueueItem struct {
+	ID       int     `json:"id"`
+	ItemID   int     `json:"itemId"` // 所属作品在服务中的 ID
+	Title    string  `json:"title"`
+	Status   string  `json:"status"`
+	State    string  `json:"state"` // 跟踪状态，如 importPending
+	Size     float64 `json:"size"`
+	SizeLeft float64 `json:"sizeLeft"`
+	TimeLeft string  `json:"timeLeft"` // 如 00:12:34
+}
+
+// Progress 返回下载进度，0 到 1
+func (q *ArrQueueItem) Progress() float64 {
+	if q.Size <= 0 {
+		return 0
+	}
+	return (q.Size - q.SizeLeft) / q.Size
+}
+
+// ArrImport 一次导入完成的下载
+type ArrImport struct {
+	ID     int       `json:"id"`
+	ItemID int       `json:"itemId"` // 所属作品在服务中的 ID
+	Title  string    `json:"title"`  // 下载的发布名称
+	Date   time.Time `json:"date"`
+}
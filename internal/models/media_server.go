@@ -1,5 +1,7 @@
 package models
 
+import "io"
+
 // MediaServer 定义通用媒体服务器接口
 type MediaServer interface {
 	// GetServerInfo 获取服务器信息
@@ -17,11 +19,283 @@ type MediaServer interface {
 	// GetLibraryItemsCount 获取指定媒体库中的项目数量
 	GetLibraryItemsCount(libraryID string) (int, error)
 	
-	// Search 搜索媒体内容
-	Search(query string) ([]SearchResult, error)
+	// Search 按结构化条件搜索媒体内容
+	Search(query SearchQuery) ([]SearchResult, error)
 	
-	// GetListeningStats 获取当前用户的收听/观看统计
-	GetListeningStats() (map[string]interface{}, error)
+	// GetPlaybackStats 获取当前用户的收听/观看统计
+	GetPlaybackStats() (*PlaybackStats, error)
+
+	// GetSessions 获取当前正在播放的会话
+	GetSessions() ([]PlaybackSession, error)
+
+	// GetResumeItems 获取当前用户未播放完的项目
+	GetResumeItems() ([]ResumeItem, error)
+
+	// GetItem 获取单个项目的详细信息
+	GetItem(itemID string) (*SearchResult, error)
+
+	// ListItems 分页获取媒体库中的项目
+	ListItems(libraryID string, query ItemQuery) (*ItemPage, error)
+}
+
+// SessionController 支持停止播放会话的媒体服务器
+type SessionController interface {
+	// StopSession 停止指定会话的播放
+	StopSession(sessionID string) error
+}
+
+// SessionMessenger 支持向客户端发送消息的媒体服务器
+type SessionMessenger interface {
+	// SendSessionMessage 向指定会话的客户端发送消息
+	SendSessionMessage(sessionID, text string) error
+}
+
+// LibraryScanner 支持触发媒体库扫描和元数据刷新的媒体服务器
+type LibraryScanner interface {
+	// ScanLibrary 扫描媒体库中新增或变更的文件
+	ScanLibrary(libraryID string) error
+
+	// RefreshLibraryMetadata 刷新媒体库中所有项目的元数据
+	RefreshLibraryMetadata(libraryID string) error
+
+	// GetScanStatus 获取媒体库最近一次扫描任务的状态，没有任务记录时返回 nil
+	GetScanStatus(libraryID string) (*ScanStatus, error)
+}
+
+// MetadataRefreshReporter 可以说明是否报告元数据刷新进度的媒体服务器
+// 未实现该接口的服务器视为通过 GetScanStatus 报告元数据刷新进度
+type MetadataRefreshReporter interface {
+	// ReportsMetadataRefresh 判断 GetScanStatus 是否包含元数据刷新任务
+	ReportsMetadataRefresh() bool
+}
+
+// ScanStatus 媒体库扫描任务状态
+type ScanStatus struct {
+	Running    bool    `json:"running"`
+	Progress   float64 `json:"progress"`             // 0 到 100，小于 0 表示未知
+	StartedAt  int64   `json:"startedAt,omitempty"`  // 毫秒时间戳
+	FinishedAt int64   `json:"finishedAt,omitempty"` // 毫秒时间戳
+	Failed     bool    `json:"failed"`
+	Message    string  `json:"message,omitempty"`
+}
+
+// SeriesBrowser 支持按季浏览剧集的媒体服务器
+type SeriesBrowser interface {
+	// GetSeasons 获取剧集的所有季
+	GetSeasons(seriesID string) ([]Season, error)
+
+	// GetEpisodes 获取剧集某一季的所有单集，seasonID 为空时返回所有单集
+	GetEpisodes(seriesID, seasonID string) ([]Episode, error)
+}
+
+// Season 剧集中的一季
+type Season struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	IndexNumber   int    `json:"indexNumber"`
+	EpisodeCount  int    `json:"episodeCount"`
+	UnplayedCount int    `json:"unplayedCount"`
+	Played        bool   `json:"played"`
+}
+
+// Episode 剧集中的单集，播放状态属于机器人关联的用户
+type Episode struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	SeasonNumber  int     `json:"seasonNumber"`
+	EpisodeNumber int     `json:"episodeNumber"`
+	Overview      string  `json:"overview,omitempty"`
+	Duration      float64 `json:"duration"` // 秒
+	Played        bool    `json:"played"`
+	Progress      float64 `json:"progress"` // 0 到 1
+}
+
+// ProgressTracker 支持读写机器人关联用户播放进度的媒体服务器
+type ProgressTracker interface {
+	// GetProgressItems 获取有播放记录的音频项目及其进度，包括已播放完的项目
+	GetProgressItems() ([]ItemProgress, error)
+
+	// SetProgress 设置项目的播放位置（秒），finished 为 true 时同时标记为已播放
+	SetProgress(itemID string, position float64, finished bool) error
+
+	// SetPlayed 将项目标记为已播放或未播放，标记为未播放时清除播放位置
+	SetPlayed(itemID string, played bool) error
+}
+
+// ItemProgress 项目的播放进度
+type ItemProgress struct {
+	Item       SearchResult `json:"item"`
+	Position   float64      `json:"position"` // 秒
+	Duration   float64      `json:"duration"` // 秒
+	Finished   bool         `json:"finished"`
+	LastUpdate int64        `json:"lastUpdate"` // 毫秒时间戳
+}
+
+// CollectionManager 支持管理合集和播放列表的媒体服务器
+type CollectionManager interface {
+	// GetCollections 获取所有合集和播放列表
+	GetCollections() ([]Collection, error)
+
+	// GetCollectionItems 获取合集或播放列表中的项目，按列表中的顺序排列
+	GetCollectionItems(kind, collectionID string) ([]SearchResult, error)
+
+	// CreateCollection 创建包含指定项目的合集或播放列表
+	CreateCollection(kind, name, itemID string) (*Collection, error)
+
+	// AddToCollection 将项目加入合集或播放列表
+	AddToCollection(kind, collectionID, itemID string) error
+}
+
+// 合集的类型
+const (
+	CollectionKindCollection = "collection"
+	CollectionKindPlaylist   = "playlist"
+)
+
+// Collection 合集或播放列表
+type Collection struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`                // collection 或 playlist
+	LibraryID string `json:"libraryId,omitempty"` // 只能包含单个媒体库项目时所属的媒体库
+	ItemCount int    `json:"itemCount"`
+}
+
+// FileDownloader 支持下载项目原始文件的媒体服务器
+type FileDownloader interface {
+	// GetDownloadFiles 获取项目包含的媒体文件，文件夹类项目返回其中的所有文件
+	GetDownloadFiles(itemID string) ([]DownloadFile, error)
+
+	// OpenFile 打开文件的数据流，end 大于 0 时只读取 [start, end) 字节范围
+	OpenFile(itemID, fileID string, start, end int64) (io.ReadCloser, error)
+}
+
+// DownloadFile 可下载的媒体文件
+type DownloadFile struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"` // 文件名，包含扩展名
+	Size     int64     `json:"size"`
+	Duration float64   `json:"duration,omitempty"` // 秒
+	Title    string    `json:"title,omitempty"`
+	Artist   string    `json:"artist,omitempty"`
+	Chapters []Chapter `json:"chapters,omitempty"`
+}
+
+// Chapter 音频文件中的章节，时间相对于文件开头
+type Chapter struct {
+	Title string  `json:"title"`
+	Start float64 `json:"start"` // 秒
+	End   float64 `json:"end"`   // 秒
+}
+
+// FileUploader 支持将文件上传到媒体库的媒体服务器
+type FileUploader interface {
+	// GetUploadTargets 获取可以上传文件的媒体库文件夹
+	GetUploadTargets() ([]UploadTarget, error)
+
+	// UploadFile 将文件上传到目标文件夹，完成后由服务器扫描入库
+	UploadFile(target UploadTarget, upload FileUpload) error
+}
+
+// UploadTarget 上传的目标媒体库文件夹
+type UploadTarget struct {
+	LibraryID string `json:"libraryId"`
+	Library   string `json:"library"`
+	FolderID  string `json:"folderId"`
+	Folder    string `json:"folder"` // 文件夹路径
+}
+
+// FileUpload 待上传的文件
+type FileUpload struct {
+	Name   string // 文件名，包含扩展名
+	Size   int64
+	Title  string
+	Author string
+	Reader io.Reader
+}
+
+// PodcastManager 支持管理播客订阅和单集下载的媒体服务器
+type PodcastManager interface {
+	// GetPodcasts 获取所有播客媒体库中的播客
+	GetPodcasts() ([]Podcast, error)
+
+	// GetRecentEpisodes 获取最近加入媒体库的播客单集，按加入时间倒序
+	GetRecentEpisodes(limit int) ([]PodcastEpisode, error)
+
+	// AddPodcast 通过 RSS 地址添加播客
+	AddPodcast(feedURL string) (*Podcast, error)
+
+	// CheckNewEpisodes 检查播客的新单集并加入下载队列，返回找到的新单集
+	CheckNewEpisodes(podcastID string) ([]PodcastEpisode, error)
+
+	// GetFeedEpisodes 获取播客订阅源中的单集，标记是否已下载
+	GetFeedEpisodes(podcastID string) ([]PodcastFeedEpisode, error)
+
+	// DownloadEpisode 将订阅源中的单集加入下载队列，episodeKey 为单集的 GUID 或音频地址
+	DownloadEpisode(podcastID, episodeKey string) error
+}
+
+// Podcast 媒体库中的播客
+type Podcast struct {
+	ID           string `json:"id"`
+	LibraryID    string `json:"libraryId"`
+	Library      string `json:"library"`
+	Title        string `json:"title"`
+	Author       string `json:"author,omitempty"`
+	FeedURL      string `json:"feedUrl,omitempty"`
+	EpisodeCount int    `json:"episodeCount"`
+	AutoDownload bool   `json:"autoDownload"`
+}
+
+// PodcastEpisode 已下载到媒体库的播客单集
+type PodcastEpisode struct {
+	ID           string  `json:"id"`
+	PodcastID    string  `json:"podcastId"` // 播客所在媒体库项目的 ID
+	PodcastTitle string  `json:"podcastTitle,omitempty"`
+	Title        string  `json:"title"`
+	PublishedAt  int64   `json:"publishedAt,omitempty"` // 毫秒时间戳
+	AddedAt      int64   `json:"addedAt,omitempty"`     // 毫秒时间戳
+	Duration     float64 `json:"duration,omitempty"`    // 秒
+}
+
+// PodcastFeedEpisode 播客订阅源中的单集
+type PodcastFeedEpisode struct {
+	Key         string `json:"key"` // GUID，没有 GUID 时为音频地址
+	Title       string `json:"title"`
+	PublishedAt int64  `json:"publishedAt,omitempty"` // 毫秒时间戳
+	Duration    string `json:"duration,omitempty"`
+	Downloaded  bool   `json:"downloaded"`
+}
+
+// EntitySearcher 支持按人物、系列、演播者、标签搜索的媒体服务器
+type EntitySearcher interface {
+	// SearchEntities 搜索名称与关键词匹配的人物、系列等实体
+	SearchEntities(text string) ([]Entity, error)
+
+	// GetEntityItems 按实体类型和名称列出相关项目，名称不区分大小写
+	GetEntityItems(kind, name string) ([]SearchResult, error)
+}
+
+// 实体类型
+const (
+	EntityPerson   = "person"   // 演员、导演等人物
+	EntityAuthor   = "author"   // 作者
+	EntityNarrator = "narrator" // 演播者
+	EntitySeries   = "series"   // 系列
+	EntityTag      = "tag"      // 标签
+)
+
+// Entity 人物、系列等可以关联多个项目的实体
+type Entity struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	ItemCount int    `json:"itemCount,omitempty"` // 0 表示未知
+}
+
+// IsPersonKind 判断实体类型是否表示人物，不同服务器上的人物可以按名称互相匹配
+func IsPersonKind(kind string) bool {
+	return kind == EntityPerson || kind == EntityAuthor || kind == EntityNarrator
 }
 
 // ServerInfo 服务器信息
@@ -59,13 +333,14 @@ type UserInfo struct {
 
 // LibraryInfo 媒体库信息
 type LibraryInfo struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	ItemCount   int    `json:"itemCount"`
-	MediaType   string `json:"mediaType"`
-	CreatedAt   int64  `json:"createdAt"`
-	UpdatedAt   int64  `json:"updatedAt"`
-	LastScan    int64  `json:"lastScan,omitempty"`
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	ItemCount int      `json:"itemCount"`
+	MediaType string   `json:"mediaType"`
+	CreatedAt int64    `json:"createdAt"`
+	UpdatedAt int64    `json:"updatedAt"`
+	LastScan  int64    `json:"lastScan,omitempty"`
+	Folders   []string `json:"folders,omitempty"` // 媒体库文件夹路径，服务器不提供时为空
 }
 
 // SearchResult 搜索结果
@@ -87,4 +362,139 @@ type SearchResult struct {
 	PremiereDate string `json:"premiereDate,omitempty"`
 	RunTime     int64    `json:"runTime,omitempty"`
 	MediaType   string   `json:"mediaType,omitempty"`
+	Duration    float64  `json:"duration,omitempty"` // 秒
+	ProviderIDs map[string]string `json:"providerIds,omitempty"` // 外部元数据 ID，键为小写的提供方名称
+
+	// 单集相关字段
+	SeriesID      string `json:"seriesId,omitempty"`
+	SeriesName    string `json:"seriesName,omitempty"`
+	SeasonNumber  int    `json:"seasonNumber,omitempty"`
+	EpisodeNumber int    `json:"episodeNumber,omitempty"`
+
+	// 有声书相关字段
+	Subtitle  string        `json:"subtitle,omitempty"`
+	Narrators []string      `json:"narrators,omitempty"`
+	Series    []SeriesEntry `json:"series,omitempty"`
+}
+
+// SeriesEntry 项目所属的系列及在系列中的序号
+type SeriesEntry struct {
+	Name     string `json:"name"`
+	Sequence string `json:"sequence,omitempty"`
+}
+
+// String 返回 “名称 #序号” 格式的系列名称
+func (s SeriesEntry) String() string {
+	if s.Sequence == "" {
+		return s.Name
+	}
+	return s.Name + " #" + s.Sequence
+}
+
+// 外部元数据提供方，用于识别不同服务器上的同一项目
+const (
+	ProviderIMDb = "imdb"
+	ProviderTMDb = "tmdb"
+	ProviderTVDb = "tvdb"
+	ProviderASIN = "asin"
+	ProviderISBN = "isbn"
+)
+
+// 媒体库项目的排序方式
+const (
+	ItemSortDateAdded = "added"
+	ItemSortName      = "name"
+	ItemSortYear      = "year"
+)
+
+// 媒体库项目的过滤方式
+const (
+	ItemFilterNone       = ""
+	ItemFilterGenre      = "genre"
+	ItemFilterUnplayed   = "unplayed"
+	ItemFilterInProgress = "inprogress"
+)
+
+// ItemQuery 媒体库项目的分页查询条件
+type ItemQuery struct {
+	Page       int    `json:"page"` // 从 0 开始
+	PageSize   int    `json:"pageSize"`
+	SortBy     string `json:"sortBy"`
+	Descending bool   `json:"descending"`
+	Filter     string `json:"filter,omitempty"`
+	Genre      string `json:"genre,omitempty"` // Filter 为 ItemFilterGenre 时使用
+}
+
+// ItemPage 媒体库项目的分页结果
+type ItemPage struct {
+	Items []SearchResult `json:"items"`
+	Total int            `json:"total"`
+}
+
+// ResumeItem 继续观看/收听的项目
+type ResumeItem struct {
+	ID         string  `json:"id"`
+	Title      string  `json:"title"`
+	Author     string  `json:"author,omitempty"`
+	Type       string  `json:"type"`
+	Position   float64 `json:"position"`   // 秒
+	Duration   float64 `json:"duration"`   // 秒
+	Progress   float64 `json:"progress"`   // 0 到 1
+	LastPlayed int64   `json:"lastPlayed"` // 毫秒时间戳
+}
+
+// Remaining 返回剩余的播放时长（秒）
+func (r *ResumeItem) Remaining() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	remaining := r.Duration - r.Position
+	if r.Position <= 0 && r.Progress > 0 {
+		remaining = r.Duration * (1 - r.Progress)
+	}
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// 播放方式
+const (
+	PlayMethodDirectPlay   = "DirectPlay"
+	PlayMethodDirectStream = "DirectStream"
+	PlayMethodTranscode    = "Transcode"
+)
+
+// PlaybackSession 正在进行的播放会话
+type PlaybackSession struct {
+	ID          string           `json:"id"`
+	UserID      string           `json:"userId"`
+	UserName    string           `json:"userName"`
+	DeviceName  string           `json:"deviceName"`
+	Client      string           `json:"client"`
+	ItemID      string           `json:"itemId"`
+	ItemTitle   string           `json:"itemTitle"`
+	ItemType    string           `json:"itemType"`
+	Position    int64            `json:"position"` // 毫秒
+	Duration    int64            `json:"duration"` // 毫秒
+	IsPaused    bool             `json:"isPaused"`
+	PlayMethod  string           `json:"playMethod"`
+	Bitrate     int64            `json:"bitrate,omitempty"` // bps
+	Transcoding *TranscodingInfo `json:"transcoding,omitempty"`
+}
+
+// TranscodingInfo 转码信息
+type TranscodingInfo struct {
+	VideoCodec string `json:"videoCodec,omitempty"`
+	AudioCodec string `json:"audioCodec,omitempty"`
+	Container  string `json:"container,omitempty"`
+	Bitrate    int64  `json:"bitrate,omitempty"` // bps
+	// HardwareAcceleration 硬件加速方式，为空表示软件转码
+	HardwareAcceleration string   `json:"hardwareAcceleration,omitempty"`
+	Reasons              []string `json:"reasons,omitempty"`
+}
+
+// IsTranscoding 判断会话是否正在转码
+func (s *PlaybackSession) IsTranscoding() bool {
+	return s.PlayMethod == PlayMethodTranscode
 }
\ No newline at end of file
@@ -0,0 +1,30 @@
+package models
+
+// MetadataProvider 外部元数据服务，用于查找用户请求的作品
+type MetadataProvider interface {
+	// Name 返回提供方名称，如 tmdb
+	Name() string
+
+	// SearchMetadata 按标题搜索作品
+	SearchMetadata(text string) ([]MediaMetadata, error)
+}
+
+// 请求的作品类型
+const (
+	MediaKindMovie  = "movie"
+	MediaKindSeries = "series"
+	MediaKindBook   = "book"
+)
+
+// MediaMetadata 外部元数据服务中的作品
+type MediaMetadata struct {
+	Provider      string            `json:"provider"`
+	ID            string            `json:"id"`
+	Kind          string            `json:"kind"` // movie、series 或 book
+	Title         string            `json:"title"`
+	OriginalTitle string            `json:"originalTitle,omitempty"`
+	Year          int               `json:"year,omitempty"`
+	Author        string            `json:"author,omitempty"`
+	Overview      string            `json:"overview,omitempty"`
+	ProviderIDs   map[string]string `json:"providerIds,omitempty"` // 键为小写的提供方名称，与 SearchResult 相同
+}
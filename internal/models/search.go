@@ -0,0 +1,183 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 搜索中使用的规范化媒体类型
+const (
+	SearchTypeMovie     = "movie"
+	SearchTypeSeries    = "series"
+	SearchTypeEpisode   = "episode"
+	SearchTypeMusic     = "music"
+	SearchTypeBook      = "book"
+	SearchTypeAudiobook = "audiobook"
+	SearchTypePodcast   = "podcast"
+)
+
+// SearchTypes 所有支持的搜索类型
+var SearchTypes = []string{
+	SearchTypeMovie, SearchTypeSeries, SearchTypeEpisode, SearchTypeMusic,
+	SearchTypeBook, SearchTypeAudiobook, SearchTypePodcast,
+}
+
+// genreAliases 常见分类缩写对应的完整名称，键为规范化后的名称
+var genreAliases = map[string][]string{
+	"scifi":  {"Science Fiction", "Sci-Fi"},
+	"sf":     {"Science Fiction", "Sci-Fi"},
+	"romcom": {"Romantic Comedy"},
+	"doc":    {"Documentary"},
+	"anime":  {"Anime", "Animation"},
+}
+
+// SearchQuery 结构化的搜索条件
+type SearchQuery struct {
+	Text      string   `json:"text"`
+	Types     []string `json:"types,omitempty"`
+	YearMin   int      `json:"yearMin,omitempty"` // 0 表示不限
+	YearMax   int      `json:"yearMax,omitempty"` // 0 表示不限
+	Genres    []string `json:"genres,omitempty"`
+	Servers   []string `json:"servers,omitempty"`
+	Libraries []string `json:"libraries,omitempty"`
+}
+
+// HasFilters 判断是否包含关键词以外的过滤条件
+func (q *SearchQuery) HasFilters() bool {
+	return len(q.Types) > 0 || q.YearMin > 0 || q.YearMax > 0 || len(q.Genres) > 0 ||
+		len(q.Servers) > 0 || len(q.Libraries) > 0
+}
+
+// WantsType 判断是否需要搜索指定类型，未指定类型时返回 true
+func (q *SearchQuery) WantsType(searchType string) bool {
+	if len(q.Types) == 0 {
+		return true
+	}
+	for _, t := range q.Types {
+		if t == searchType {
+			return true
+		}
+	}
+	return false
+}
+
+// WantsServer 判断是否需要搜索指定服务器，未指定服务器时返回 true
+func (q *SearchQuery) WantsServer(server string) bool {
+	if len(q.Servers) == 0 {
+		return true
+	}
+	for _, s := range q.Servers {
+		if strings.EqualFold(s, server) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesLibrary 判断媒体库名称是否符合条件，按不区分大小写的包含关系匹配
+func (q *SearchQuery) MatchesLibrary(name string) bool {
+	if len(q.Libraries) == 0 {
+		return true
+	}
+	name = strings.ToLower(name)
+	for _, lib := range q.Libraries {
+		if strings.Contains(name, strings.ToLower(lib)) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesYear 判断年份是否在范围内，设置了年份条件时未知年份视为不匹配
+func (q *SearchQuery) MatchesYear(year int) bool {
+	if q.YearMin == 0 && q.YearMax == 0 {
+		return true
+	}
+	if year == 0 {
+		return false
+	}
+	return (q.YearMin == 0 || year >= q.YearMin) && (q.YearMax == 0 || year <= q.YearMax)
+}
+
+// MatchesGenres 判断分类是否符合任一条件，忽略大小写、空格和连字符，并识别常见缩写
+func (q *SearchQuery) MatchesGenres(genres []string) bool {
+	if len(q.Genres) == 0 {
+		return true
+	}
+	for _, want := range q.Genres {
+		candidates := []string{normalizeGenre(want)}
+		for _, alias := range GenreNames(want) {
+			candidates = append(candidates, normalizeGenre(alias))
+		}
+		for _, genre := range genres {
+			normalized := normalizeGenre(genre)
+			for _, candidate := range candidates {
+				if strings.Contains(normalized, candidate) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// MatchesType 判断搜索结果的类型是否符合条件
+func (q *SearchQuery) MatchesType(result *SearchResult) bool {
+	if len(q.Types) == 0 {
+		return true
+	}
+	for _, t := range ResultSearchTypes(result) {
+		if q.WantsType(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches 对搜索结果进行后过滤，媒体库和服务器条件由调用方处理
+func (q *SearchQuery) Matches(result *SearchResult) bool {
+	return q.MatchesType(result) && q.MatchesYear(result.Year) && q.MatchesGenres(result.Genres)
+}
+
+// GenreNames 返回分类条件本身及其缩写对应的完整名称
+func GenreNames(genre string) []string {
+	names := []string{genre}
+	return append(names, genreAliases[normalizeGenre(genre)]...)
+}
+
+// ResultSearchTypes 返回搜索结果对应的规范化搜索类型
+func ResultSearchTypes(result *SearchResult) []string {
+	switch strings.ToLower(result.Type) {
+	case "movie":
+		return []string{SearchTypeMovie}
+	case "series":
+		return []string{SearchTypeSeries}
+	case "episode":
+		return []string{SearchTypeEpisode}
+	case "musicalbum", "audio", "musicartist", "music":
+		return []string{SearchTypeMusic}
+	case "audiobook":
+		return []string{SearchTypeAudiobook, SearchTypeBook}
+	case "book":
+		// Audiobookshelf 的书籍是有声书
+		if result.MediaType == "audio" {
+			return []string{SearchTypeAudiobook, SearchTypeBook}
+		}
+		return []string{SearchTypeBook}
+	case "podcast":
+		return []string{SearchTypePodcast}
+	default:
+		return nil
+	}
+}
+
+// normalizeGenre 规范化分类名称用于比较
+func normalizeGenre(genre string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(genre) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,92 @@
+package models
+
+import "time"
+
+// 播放统计的数据来源
+const (
+	StatsSourceListeningStats    = "listening_stats"    // Audiobookshelf 收听统计
+	StatsSourcePlaybackReporting = "playback_reporting" // Emby Playback Reporting 插件
+	StatsSourceUserData          = "user_data"          // 已播放项目的用户数据
+)
+
+// PlaybackStats 当前用户的播放统计
+type PlaybackStats struct {
+	Source      string             `json:"source"`
+	TotalTime   float64            `json:"totalTime"` // 秒
+	ItemsPlayed int                `json:"itemsPlayed"`
+	Days        map[string]float64 `json:"days"` // 日期(YYYY-MM-DD) -> 秒
+	// WeekdayHours 按星期（0 为周日）和小时统计的播放时长，单位为秒
+	WeekdayHours   [7][24]float64 `json:"weekdayHours"`
+	TopItems       []StatsItem    `json:"topItems"`
+	TopGenres      []GenreStat    `json:"topGenres"`
+	InProgress     []StatsItem    `json:"inProgress"`
+	RecentSessions []StatsSession `json:"recentSessions,omitempty"`
+}
+
+// StatsDateLayout Days 中日期的格式
+const StatsDateLayout = "2006-01-02"
+
+// DayTotal 某一天的播放时长
+type DayTotal struct {
+	Date    string
+	Seconds float64
+}
+
+// Today 返回今天的播放时长，单位为秒
+func (s *PlaybackStats) Today(now time.Time) float64 {
+	return s.Days[now.Format(StatsDateLayout)]
+}
+
+// Streak 计算截至今天的连续播放天数，今天尚未播放时从昨天开始计算
+func (s *PlaybackStats) Streak(now time.Time) int {
+	day := now
+	if s.Days[day.Format(StatsDateLayout)] <= 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	streak := 0
+	for s.Days[day.Format(StatsDateLayout)] > 0 {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	return streak
+}
+
+// RecentDays 返回最近 n 天（含今天）每天的播放时长，按日期从早到晚排列
+func (s *PlaybackStats) RecentDays(now time.Time, n int) []DayTotal {
+	totals := make([]DayTotal, n)
+	for i := 0; i < n; i++ {
+		date := now.AddDate(0, 0, i-n+1).Format(StatsDateLayout)
+		totals[i] = DayTotal{Date: date, Seconds: s.Days[date]}
+	}
+	return totals
+}
+
+// StatsItem 统计中的单个媒体项目
+type StatsItem struct {
+	ID         string  `json:"id"`
+	Title      string  `json:"title"`
+	Author     string  `json:"author,omitempty"`
+	Type       string  `json:"type"`
+	PlayTime   float64 `json:"playTime"` // 秒
+	PlayCount  int     `json:"playCount,omitempty"`
+	Progress   float64 `json:"progress,omitempty"` // 0 到 1
+	Duration   float64 `json:"duration,omitempty"` // 秒
+	LastPlayed int64   `json:"lastPlayed,omitempty"`
+}
+
+// GenreStat 某个分类的播放时长
+type GenreStat struct {
+	Genre     string  `json:"genre"`
+	PlayTime  float64 `json:"playTime"` // 秒
+	ItemCount int     `json:"itemCount"`
+}
+
+// StatsSession 最近的播放会话
+type StatsSession struct {
+	ItemID        string  `json:"itemId"`
+	Title         string  `json:"title"`
+	TimeListening float64 `json:"timeListening"` // 秒
+	UpdatedAt     int64   `json:"updatedAt"`
+}
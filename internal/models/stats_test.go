@@ -0,0 +1,43 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlaybackStatsStreak(t *testing.T) {
+	now := time.Date(2026, 10, 19, 21, 0, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		days map[string]float64
+		want int
+	}{
+		{"none", nil, 0},
+		{"today only", map[string]float64{"2026-10-19": 60}, 1},
+		{"not yet today", map[string]float64{"2026-10-18": 60, "2026-10-17": 30}, 2},
+		{"gap", map[string]float64{"2026-10-19": 60, "2026-10-18": 60, "2026-10-16": 60}, 2},
+		{"zero day breaks", map[string]float64{"2026-10-19": 60, "2026-10-18": 0, "2026-10-17": 60}, 1},
+		{"month boundary", map[string]float64{"2026-10-02": 1, "2026-10-01": 1, "2026-09-30": 1}, 0},
+	}
+
+	for _, tt := range tests {
+		stats := &PlaybackStats{Days: tt.days}
+		if got := stats.Streak(now); got != tt.want {
+			t.Errorf("%s: Streak = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPlaybackStatsRecentDays(t *testing.T) {
+	now := time.Date(2026, 10, 1, 8, 0, 0, 0, time.Local)
+	stats := &PlaybackStats{Days: map[string]float64{"2026-09-30": 120, "2026-10-01": 30}}
+
+	want := []DayTotal{{"2026-09-29", 0}, {"2026-09-30", 120}, {"2026-10-01", 30}}
+	if got := stats.RecentDays(now, 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("RecentDays = %v, want %v", got, want)
+	}
+	if got := stats.Today(now); got != 30 {
+		t.Errorf("Today = %v, want 30", got)
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+// TorrentClient 下载客户端（qBittorrent、Transmission）
+type TorrentClient interface {
+	// Name 返回客户端名称，如 qBittorrent
+	Name() string
+
+	// GetTorrents 获取所有种子
+	GetTorrents() ([]Torrent, error)
+
+	// PauseTorrent 暂停种子
+	PauseTorrent(id string) error
+
+	// ResumeTorrent 继续种子
+	ResumeTorrent(id string) error
+
+	// DeleteTorrent 删除种子，deleteFiles 为 true 时同时删除已下载的文件
+	DeleteTorrent(id string, deleteFiles bool) error
+}
+
+// Torrent 下载客户端中的种子
+type Torrent struct {
+	ID            string  `json:"id"` // 种子的 info hash
+	Name          string  `json:"name"`
+	State         string  `json:"state"`    // 客户端原始状态
+	Progress      float64 `json:"progress"` // 0 到 1
+	ETA           int64   `json:"eta"`      // 秒，未知时为 -1
+	Ratio         float64 `json:"ratio"`
+	Size          int64   `json:"size"`
+	DownloadSpeed int64   `json:"downloadSpeed"` // 字节/秒
+	UploadSpeed   int64   `json:"uploadSpeed"`   // 字节/秒
+	SavePath      string  `json:"savePath"`
+	Paused        bool    `json:"paused"`
+	Completed     bool    `json:"completed"`
+}
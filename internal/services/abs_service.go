@@ -209,7 +209,7 @@ func (s *AbsServerService) SearchBooks(term string, libraryID string) ([]models.
 
 	// 如果需要在特定媒体库中搜索，需要创建一个新方法
 	// 目前适配器接口只支持全库搜索，我们暂时使用全库搜索结果并过滤
-	results, err := s.adapter.Search(term)
+	results, err := s.adapter.Search(models.SearchQuery{Text: term})
 	if err != nil {
 		return nil, fmt.Errorf("搜索失败: %w", err)
 	}
@@ -243,8 +243,8 @@ func (s *AbsServerService) GetCurrentUserWithProgress() (*models.UserInfo, error
 }
 
 // GetListeningStats 获取当前用户的收听统计信息
-func (s *AbsServerService) GetListeningStats() (map[string]interface{}, error) {
-	stats, err := s.adapter.GetListeningStats()
+func (s *AbsServerService) GetListeningStats() (*models.PlaybackStats, error) {
+	stats, err := s.adapter.GetPlaybackStats()
 	if err != nil {
 		return nil, fmt.Errorf("获取收听统计信息失败: %w", err)
 	}
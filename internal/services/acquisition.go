@@ -0,0 +1,318 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/api"
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/store"
+)
+
+// AcquirerQueue 某个下载管理服务的下载队列
+type AcquirerQueue struct {
+	Acquirer string
+	Items    []models.ArrQueueItem
+	Err      error
+}
+
+// AcquisitionService 将批准的请求添加到 Radarr、Sonarr 等下载管理服务，并定期检查导入完成的下载
+type AcquisitionService struct {
+	manager      *MediaServerManager
+	acquirers    []models.MediaAcquirer
+	requests     *RequestService
+	interval     time.Duration
+	notifyAdmins func(text string)
+	notifyUser   func(userID int64, text string)
+	file         *store.JSONFile
+
+	mu        sync.Mutex
+	lastCheck map[string]time.Time // 每个服务上次检查导入记录的时间，重启后从上次的时间继续检查
+	stop      <-chan struct{}
+}
+
+// NewAcquisitionService 创建下载管理服务，只启用配置了地址和 API Key 的服务
+func NewAcquisitionService(manager *MediaServerManager, requests *RequestService, cfg *config.Config, notifyAdmins func(text string), notifyUser func(userID int64, text string)) *AcquisitionService {
+	var acquirers []models.MediaAcquirer
+	if cfg.RadarrURL != "" && cfg.RadarrAPIKey != "" {
+		acquirers = append(acquirers, api.NewRadarrClient(cfg))
+	}
+	if cfg.SonarrURL != "" && cfg.SonarrAPIKey != "" {
+		acquirers = append(acquirers, api.NewSonarrClient(cfg))
+	}
+	if cfg.ReadarrURL != "" && cfg.ReadarrAPIKey != "" {
+		acquirers = append(acquirers, api.NewReadarrClient(cfg))
+	}
+
+	s := &AcquisitionService{
+		manager:      manager,
+		acquirers:    acquirers,
+		requests:     requests,
+		interval:     time.Duration(cfg.ArrCheckInterval) * time.Minute,
+		notifyAdmins: notifyAdmins,
+		notifyUser:   notifyUser,
+		file:         store.NewJSONFile(cfg.DataDir, "arr_imports.json"),
+		lastCheck:    make(map[string]time.Time),
+	}
+
+	if err := s.file.Load(&s.lastCheck); err != nil {
+		log.Printf("加载导入记录检查时间失败: %v", err)
+	}
+	// 新配置的服务从现在开始检查
+	now := time.Now()
+	added := false
+	for _, acquirer := range acquirers {
+		if _, ok := s.lastCheck[acquirer.Name()]; !ok {
+			s.lastCheck[acquirer.Name()] = now
+			added = true
+		}
+	}
+	if added {
+		if err := s.file.Save(s.lastCheck); err != nil {
+			log.Printf("保存导入记录检查时间失败: %v", err)
+		}
+	}
+
+	return s
+}
+
+// Run 按检查间隔检查导入完成的下载，直到 stop 被关闭
+func (s *AcquisitionService) Run(stop <-chan struct{}) {
+	if len(s.acquirers) == 0 {
+		return
+	}
+	if s.interval <= 0 {
+		log.Println("下载完成通知已关闭")
+		return
+	}
+	s.stop = stop
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.CheckImports()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// HasAcquirers 判断是否配置了下载管理服务
+func (s *AcquisitionService) HasAcquirers() bool {
+	return len(s.acquirers) > 0
+}
+
+// Acquirer 返回负责指定作品类型的服务
+func (s *AcquisitionService) Acquirer(kind string) (models.MediaAcquirer, bool) {
+	for _, acquirer := range s.acquirers {
+		if acquirer.Kind() == kind {
+			return acquirer, true
+		}
+	}
+	return nil, false
+}
+
+// Add 将请求的作品添加到对应的服务并开始下载
+func (s *AcquisitionService) Add(requestID int, options models.ArrAddOptions) (*models.ArrItem, error) {
+	request, ok := s.requests.Get(requestID)
+	if !ok {
+		return nil, fmt.Errorf("请求 #%d 不存在", requestID)
+	}
+	if request.Status != RequestApproved {
+		return nil, fmt.Errorf("请求 #%d 当前状态为 %s，只能添加已批准的请求", requestID, request.Status.Label())
+	}
+	acquirer, ok := s.Acquirer(request.Metadata.Kind)
+	if !ok {
+		return nil, fmt.Errorf("未配置处理该类型作品的下载管理服务")
+	}
+
+	item, err := acquirer.Add(&request.Metadata, options)
+	if err != nil {
+		return nil, fmt.Errorf("添加到 %s 失败: %w", acquirer.Name(), err)
+	}
+	if err := s.requests.SetAcquired(requestID, acquirer.Name(), item.ID); err != nil {
+		log.Printf("%v", err)
+	}
+	return item, nil
+}
+
+// Queues 获取所有服务的下载队列
+func (s *AcquisitionService) Queues() []AcquirerQueue {
+	queues := make([]AcquirerQueue, 0, len(s.acquirers))
+	for _, acquirer := range s.acquirers {
+		items, err := acquirer.GetQueue()
+		queues = append(queues, AcquirerQueue{Acquirer: acquirer.Name(), Items: items, Err: err})
+	}
+	return queues
+}
+
+// CheckImports 检查上次检查之后导入完成的下载，通知管理员和请求了该作品的用户
+func (s *AcquisitionService) CheckImports() {
+	for _, acquirer := range s.acquirers {
+		s.mu.Lock()
+		since := s.lastCheck[acquirer.Name()]
+		s.mu.Unlock()
+
+		imports, err := acquirer.GetImportedSince(since)
+		if err != nil {
+			log.Printf("获取 %s 导入记录失败: %v", acquirer.Name(), err)
+			continue
+		}
+
+		latest := since
+		var fresh []models.ArrImport
+		for _, imported := range imports {
+			// history/since 包含等于起始时间的记录，跳过已通知过的
+			if !imported.Date.After(since) {
+				continue
+			}
+			if imported.Date.After(latest) {
+				latest = imported.Date
+			}
+			fresh = append(fresh, imported)
+		}
+		if len(fresh) > 0 {
+			s.notifyImports(acquirer, fresh)
+			// Readarr 导入到 Audiobookshelf 媒体库文件夹后需要扫描才能入库
+			if acquirer.Kind() == models.MediaKindBook {
+				go s.scanBookLibraries(acquirer)
+			}
+		}
+
+		if !latest.After(since) {
+			continue
+		}
+		s.mu.Lock()
+		s.lastCheck[acquirer.Name()] = latest
+		if err := s.file.Save(s.lastCheck); err != nil {
+			log.Printf("保存导入记录检查时间失败: %v", err)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// notifyImports 将导入完成的下载汇总通知管理员，剧集的多个单集只通知请求者一次
+func (s *AcquisitionService) notifyImports(acquirer models.MediaAcquirer, imports []models.ArrImport) {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📥 %s 下载完成并已导入:\n", acquirer.Name()))
+	notified := make(map[int]bool)
+	for _, imported := range imports {
+		log.Printf("%s 导入完成: %s", acquirer.Name(), imported.Title)
+		sb.WriteString("• " + imported.Title + "\n")
+
+		if notified[imported.ItemID] || s.notifyUser == nil {
+			continue
+		}
+		notified[imported.ItemID] = true
+		for _, request := range s.requests.FindByAcquired(acquirer.Name(), imported.ItemID) {
+			if !request.IsOpen() {
+				continue
+			}
+			for _, userID := range request.Requesters() {
+				s.notifyUser(userID, fmt.Sprintf("📥 您请求的 %s 已下载完成，媒体服务器扫描后即可%s", FormatMetadataTitle(&request.Metadata), requestActionName(request.Metadata.Kind)))
+			}
+		}
+	}
+	if s.notifyAdmins != nil {
+		s.notifyAdmins(strings.TrimRight(sb.String(), "\n"))
+	}
+}
+
+// requestActionName 返回作品类型对应的动作，用于通知文本
+func requestActionName(kind string) string {
+	if kind == models.MediaKindBook {
+		return "收听"
+	}
+	return "观看"
+}
+
+// scanBookLibraries 扫描文件夹包含 Readarr 根文件夹的 Audiobookshelf 媒体库，扫描完成后检查请求的书籍是否已入库
+func (s *AcquisitionService) scanBookLibraries(acquirer models.MediaAcquirer) {
+	server, err := s.manager.GetServer(AbsServerType)
+	if err != nil {
+		return
+	}
+	scanner, ok := server.(models.LibraryScanner)
+	if !ok {
+		return
+	}
+	bookLibraries, err := server.GetLibraries()
+	if err != nil {
+		log.Printf("获取 Audiobookshelf 媒体库文件夹失败: %v", err)
+		return
+	}
+	rootFolders, err := acquirer.GetRootFolders()
+	if err != nil {
+		log.Printf("获取 %s 根文件夹失败: %v", acquirer.Name(), err)
+		return
+	}
+
+	libraries := matchRootFolderLibraries(bookLibraries, rootFolders)
+	if len(libraries) == 0 {
+		log.Printf("没有与 %s 根文件夹对应的 Audiobookshelf 媒体库，请确认两者使用相同的路径", acquirer.Name())
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, library := range libraries {
+		started := time.Now()
+		if err := scanner.ScanLibrary(library.ID); err != nil {
+			log.Printf("扫描媒体库 %s 失败: %v", library.Name, err)
+			continue
+		}
+		log.Printf("%s 导入完成，已触发媒体库 %s 扫描", acquirer.Name(), library.Name)
+
+		wg.Add(1)
+		go func(library models.LibraryInfo) {
+			defer wg.Done()
+			if _, err := WaitForScan(scanner, library.ID, started, s.stop, nil); err != nil && !errors.Is(err, ErrScanNotTracked) {
+				log.Printf("等待媒体库 %s 扫描完成失败: %v", library.Name, err)
+			}
+		}(library)
+	}
+	wg.Wait()
+
+	s.requests.CheckAvailable()
+}
+
+// matchRootFolderLibraries 返回文件夹包含任一根文件夹的书籍媒体库，播客媒体库不会导入书籍
+func matchRootFolderLibraries(libraries []models.LibraryInfo, rootFolders []models.ArrRootFolder) []models.LibraryInfo {
+	var matched []models.LibraryInfo
+	for _, library := range libraries {
+		if library.MediaType == "podcast" || !libraryContainsRootFolder(library, rootFolders) {
+			continue
+		}
+		matched = append(matched, library)
+	}
+	return matched
+}
+
+// libraryContainsRootFolder 判断媒体库的文件夹是否包含任一根文件夹
+func libraryContainsRootFolder(library models.LibraryInfo, rootFolders []models.ArrRootFolder) bool {
+	for _, folder := range library.Folders {
+		for _, root := range rootFolders {
+			if pathWithin(root.Path, folder) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pathWithin 判断 path 是否为 dir 或位于 dir 之下，忽略末尾的路径分隔符
+func pathWithin(path, dir string) bool {
+	path = strings.TrimRight(strings.ReplaceAll(path, "\\", "/"), "/")
+	dir = strings.TrimRight(strings.ReplaceAll(dir, "\\", "/"), "/")
+	if path == "" || dir == "" {
+		return false
+	}
+	return path == dir || strings.HasPrefix(path, dir+"/")
+}
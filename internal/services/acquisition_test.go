@@ -0,0 +1,119 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/store"
+)
+
+func TestCheckImportsPersistsLastCheck(t *testing.T) {
+	t0 := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	t1 := t0.Add(90 * time.Minute)
+
+	var mu sync.Mutex
+	var dates []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/history/since" {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		dates = append(dates, r.URL.Query().Get("date"))
+		mu.Unlock()
+		// history/since 包含等于起始时间的记录
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"id": 1, "movieId": 7, "sourceTitle": "Dune.2021.2160p", "date": t1.Format(time.RFC3339)},
+		})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	// 上次运行时检查到 t0
+	if err := store.NewJSONFile(dir, "arr_imports.json").Save(map[string]time.Time{"Radarr": t0}); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{DataDir: dir, RadarrURL: server.URL, RadarrAPIKey: "key", ArrCheckInterval: 5}
+
+	var notified []string
+	notify := func(text string) { notified = append(notified, text) }
+
+	NewAcquisitionService(nil, nil, cfg, notify, nil).CheckImports()
+	if len(notified) != 1 {
+		t.Fatalf("notifications = %d, want 1 for the import while stopped", len(notified))
+	}
+
+	// 重启后从上次导入的时间继续，不再重复通知
+	NewAcquisitionService(nil, nil, cfg, notify, nil).CheckImports()
+	if len(notified) != 1 {
+		t.Errorf("notifications after restart = %d, want 1", len(notified))
+	}
+
+	want := []string{t0.Format(time.RFC3339), t1.Format(time.RFC3339)}
+	if len(dates) != 2 || dates[0] != want[0] || dates[1] != want[1] {
+		t.Errorf("history dates = %q, want %q", dates, want)
+	}
+}
+
+func TestNewAcquisitionServiceStartsNewAcquirersNow(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{DataDir: dir, SonarrURL: "http://sonarr", SonarrAPIKey: "key"}
+
+	before := time.Now()
+	NewAcquisitionService(nil, nil, cfg, nil, nil)
+
+	var saved map[string]time.Time
+	if err := store.NewJSONFile(dir, "arr_imports.json").Load(&saved); err != nil {
+		t.Fatal(err)
+	}
+	if since, ok := saved["Sonarr"]; !ok || since.Before(before.Truncate(time.Second)) {
+		t.Errorf("saved = %v, want Sonarr starting now", saved)
+	}
+}
+
+func TestMatchRootFolderLibraries(t *testing.T) {
+	libraries := []models.LibraryInfo{
+		{ID: "audiobooks", Name: "有声书", MediaType: "book", Folders: []string{"/books/audio", "/books/audio-extra"}},
+		{ID: "ebooks", Name: "电子书", MediaType: "book", Folders: []string{"/books/ebooks/"}},
+		{ID: "other", Name: "其他", MediaType: "book", Folders: []string{"/books/audio2"}},
+		{ID: "podcasts", Name: "播客", MediaType: "podcast", Folders: []string{"/books/podcasts"}},
+	}
+
+	tests := []struct {
+		name  string
+		roots []string
+		want  []string
+	}{
+		{"exact", []string{"/books/audio"}, []string{"audiobooks"}},
+		{"trailing separator", []string{"/books/ebooks/"}, []string{"ebooks"}},
+		{"inside library folder", []string{"/books/audio/readarr"}, []string{"audiobooks"}},
+		{"prefix is not a parent", []string{"/books/audio22"}, nil},
+		{"parent of library folder", []string{"/books"}, nil},
+		{"several roots", []string{"/books/audio-extra", "/books/audio2"}, []string{"audiobooks", "other"}},
+		{"both folders of one library", []string{"/books/audio", "/books/audio-extra"}, []string{"audiobooks"}},
+		{"podcast library", []string{"/books/podcasts"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var roots []models.ArrRootFolder
+			for _, path := range tt.roots {
+				roots = append(roots, models.ArrRootFolder{Path: path})
+			}
+			var got []string
+			for _, library := range matchRootFolderLibraries(libraries, roots) {
+				got = append(got, library.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("libraries = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
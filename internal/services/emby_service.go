@@ -178,7 +178,7 @@ func (s *EmbyServerService) SearchItems(query string) ([]models.SearchResult, er
 		return nil, fmt.Errorf("搜索词不能为空")
 	}
 
-	results, err := s.adapter.Search(query)
+	results, err := s.adapter.Search(models.SearchQuery{Text: query})
 	if err != nil {
 		return nil, fmt.Errorf("搜索失败: %w", err)
 	}
@@ -201,8 +201,8 @@ func (s *EmbyServerService) GetCurrentUserWithProgress() (*models.UserInfo, erro
 }
 
 // GetListeningStats 获取当前用户的收听统计信息
-func (s *EmbyServerService) GetListeningStats() (map[string]interface{}, error) {
-	stats, err := s.adapter.GetListeningStats()
+func (s *EmbyServerService) GetListeningStats() (*models.PlaybackStats, error) {
+	stats, err := s.adapter.GetPlaybackStats()
 	if err != nil {
 		return nil, fmt.Errorf("获取收听统计信息失败: %w", err)
 	}
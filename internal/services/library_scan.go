@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+const (
+	// scanPollInterval 轮询扫描状态的间隔
+	scanPollInterval = 5 * time.Second
+	// scanStartTimeout 触发扫描后等待任务出现的最长时间
+	scanStartTimeout = time.Minute
+	// scanTimeout 等待扫描完成的最长时间
+	scanTimeout = 2 * time.Hour
+	// scanMaxErrors 连续获取状态失败的最大次数
+	scanMaxErrors = 5
+)
+
+// ErrScanNotTracked 服务器没有报告对应的扫描任务，无法跟踪完成情况
+var ErrScanNotTracked = errors.New("无法跟踪扫描任务")
+
+// WaitForScan 轮询媒体库扫描状态，直到 since 之后开始的扫描任务结束
+// 任务运行期间每次轮询都会调用 onProgress
+func WaitForScan(scanner models.LibraryScanner, libraryID string, since time.Time, stop <-chan struct{}, onProgress func(*models.ScanStatus)) (*models.ScanStatus, error) {
+	ticker := time.NewTicker(scanPollInterval)
+	defer ticker.Stop()
+
+	// 服务器时间可能略有偏差，留出一些余量
+	sinceMs := since.Add(-5 * time.Second).UnixMilli()
+	seenRunning := false
+	failures := 0
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return nil, fmt.Errorf("扫描跟踪已停止")
+		}
+
+		elapsed := time.Since(since)
+		if elapsed > scanTimeout {
+			return nil, fmt.Errorf("等待扫描完成超时")
+		}
+
+		status, err := scanner.GetScanStatus(libraryID)
+		if err != nil {
+			failures++
+			log.Printf("获取媒体库 %s 扫描状态失败: %v", libraryID, err)
+			if failures >= scanMaxErrors {
+				return nil, fmt.Errorf("获取扫描状态失败: %w", err)
+			}
+			continue
+		}
+		failures = 0
+
+		switch {
+		case status != nil && status.Running:
+			seenRunning = true
+			if onProgress != nil {
+				onProgress(status)
+			}
+		case status != nil && (seenRunning || status.FinishedAt >= sinceMs):
+			return status, nil
+		case elapsed > scanStartTimeout && !seenRunning:
+			return nil, ErrScanNotTracked
+		}
+	}
+}
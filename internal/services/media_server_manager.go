@@ -5,6 +5,7 @@ import (
 	"github.com/Heathcliff-third-space/MediaManager/internal/api"
 	"github.com/Heathcliff-third-space/MediaManager/internal/config"
 	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"sort"
 	"sync"
 )
 
@@ -39,6 +40,7 @@ func NewMediaServerManager(cfg *config.Config) (*MediaServerManager, error) {
 	if cfg.EmbyToken != "" {
 		embyClient := api.NewEmbyClient(cfg)
 		embyAdapter := api.NewEmbyAdapter(embyClient)
+		embyAdapter.SetUploadFolders(cfg.EmbyUploadFolders)
 		manager.servers[EmbyServerType] = embyAdapter
 	}
 
@@ -84,44 +86,28 @@ func (m *MediaServerManager) GetServerTypes() []MediaServerType {
 	for serverType := range m.servers {
 		types = append(types, serverType)
 	}
+	// 保证输出顺序稳定
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
 
 	return types
 }
 
-// SearchAcrossServers 在所有服务器中搜索
-func (m *MediaServerManager) SearchAcrossServers(query string) (map[MediaServerType][]models.SearchResult, error) {
+// SearchAcrossServers 在所有服务器中搜索，query 指定了服务器时只搜索对应的服务器
+func (m *MediaServerManager) SearchAcrossServers(query models.SearchQuery) (map[MediaServerType][]models.SearchResult, error) {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
-	results := make(map[MediaServerType][]models.SearchResult)
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-
-	// 使用信号量控制最大并发数
-	maxConcurrency := make(chan struct{}, 4)
-
+	servers := make(map[MediaServerType]models.MediaServer)
 	for serverType, server := range m.servers {
-		wg.Add(1)
-		go func(st MediaServerType, s models.MediaServer) {
-			defer wg.Done()
-			// 控制并发数
-			maxConcurrency <- struct{}{}
-			defer func() { <-maxConcurrency }()
-
-			searchResults, err := s.Search(query)
-			if err != nil {
-				// 记录错误但继续处理其他服务器
-				return
-			}
-
-			mu.Lock()
-			results[st] = searchResults
-			mu.Unlock()
-		}(serverType, server)
+		if query.WantsServer(string(serverType)) {
+			servers[serverType] = server
+		}
 	}
 
-	wg.Wait()
-
+	// 记录错误但继续处理其他服务器
+	results, _ := fanOut(servers, func(s models.MediaServer) ([]models.SearchResult, error) {
+		return s.Search(query)
+	})
 	return results, nil
 }
 
@@ -130,34 +116,103 @@ func (m *MediaServerManager) GetServerInfoAcrossServers() (map[MediaServerType]*
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
-	info := make(map[MediaServerType]*models.ServerInfo)
+	// 记录错误但继续处理其他服务器
+	info, _ := fanOut(m.servers, func(s models.MediaServer) (*models.ServerInfo, error) {
+		return s.GetServerInfo()
+	})
+	return info, nil
+}
+
+// GetSessionsAcrossServers 获取所有服务器当前正在播放的会话
+func (m *MediaServerManager) GetSessionsAcrossServers() (map[MediaServerType][]models.PlaybackSession, map[MediaServerType]error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return fanOut(m.servers, func(s models.MediaServer) ([]models.PlaybackSession, error) {
+		return s.GetSessions()
+	})
+}
+
+// GetResumeItemsAcrossServers 获取所有服务器中当前用户未播放完的项目
+func (m *MediaServerManager) GetResumeItemsAcrossServers() (map[MediaServerType][]models.ResumeItem, map[MediaServerType]error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return fanOut(m.servers, func(s models.MediaServer) ([]models.ResumeItem, error) {
+		return s.GetResumeItems()
+	})
+}
+
+// SearchEntitiesAcrossServers 在支持实体搜索的服务器中搜索人物、系列等实体
+func (m *MediaServerManager) SearchEntitiesAcrossServers(text string) (map[MediaServerType][]models.Entity, map[MediaServerType]error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return fanOut(m.entitySearchers(), func(s models.MediaServer) ([]models.Entity, error) {
+		return s.(models.EntitySearcher).SearchEntities(text)
+	})
+}
+
+// GetEntityItemsAcrossServers 在所有支持实体搜索的服务器中按实体名称列出相关项目
+func (m *MediaServerManager) GetEntityItemsAcrossServers(kind, name string) (map[MediaServerType][]models.SearchResult, map[MediaServerType]error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	items, errs := fanOut(m.entitySearchers(), func(s models.MediaServer) ([]models.SearchResult, error) {
+		return s.(models.EntitySearcher).GetEntityItems(kind, name)
+	})
+	for serverType, serverItems := range items {
+		if len(serverItems) == 0 {
+			delete(items, serverType)
+		}
+	}
+	return items, errs
+}
+
+// entitySearchers 返回支持实体搜索的服务器，调用方需持有读锁
+func (m *MediaServerManager) entitySearchers() map[MediaServerType]models.MediaServer {
+	servers := make(map[MediaServerType]models.MediaServer)
+	for serverType, server := range m.servers {
+		if _, ok := server.(models.EntitySearcher); ok {
+			servers[serverType] = server
+		}
+	}
+	return servers
+}
+
+// maxServerConcurrency 同时请求的最大服务器数量
+const maxServerConcurrency = 4
+
+// fanOut 并发地对每个服务器调用 fn，返回成功的结果和失败服务器的错误
+func fanOut[T any](servers map[MediaServerType]models.MediaServer, fn func(models.MediaServer) (T, error)) (map[MediaServerType]T, map[MediaServerType]error) {
+	results := make(map[MediaServerType]T)
+	errs := make(map[MediaServerType]error)
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
 	// 使用信号量控制最大并发数
-	maxConcurrency := make(chan struct{}, 4)
+	semaphore := make(chan struct{}, maxServerConcurrency)
 
-	for serverType, server := range m.servers {
+	for serverType, server := range servers {
 		wg.Add(1)
 		go func(st MediaServerType, s models.MediaServer) {
 			defer wg.Done()
-			// 控制并发数
-			maxConcurrency <- struct{}{}
-			defer func() { <-maxConcurrency }()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			result, err := fn(s)
 
-			serverInfo, err := s.GetServerInfo()
+			mu.Lock()
+			defer mu.Unlock()
 			if err != nil {
-				// 记录错误但继续处理其他服务器
+				errs[st] = err
 				return
 			}
-
-			mu.Lock()
-			info[st] = serverInfo
-			mu.Unlock()
+			results[st] = result
 		}(serverType, server)
 	}
 
 	wg.Wait()
 
-	return info, nil
-}
\ No newline at end of file
+	return results, errs
+}
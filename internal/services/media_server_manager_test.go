@@ -0,0 +1,29 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+func TestGetSessionsAcrossServers(t *testing.T) {
+	manager := &MediaServerManager{servers: map[MediaServerType]models.MediaServer{
+		EmbyServerType: &fakeSessionServer{sessions: []models.PlaybackSession{{ID: "1"}}},
+		AbsServerType:  &fakeSessionServer{err: errors.New("timeout")},
+	}}
+
+	sessions, errs := manager.GetSessionsAcrossServers()
+	if len(sessions) != 1 || len(sessions[EmbyServerType]) != 1 {
+		t.Errorf("sessions = %v, want only emby", sessions)
+	}
+	if len(errs) != 1 || errs[AbsServerType] == nil {
+		t.Errorf("errs = %v, want only audiobookshelf", errs)
+	}
+
+	// 不支持实体搜索的服务器不参与
+	entities, errs := manager.SearchEntitiesAcrossServers("x")
+	if len(entities) != 0 || len(errs) != 0 {
+		t.Errorf("entities = %v, errs = %v, want none", entities, errs)
+	}
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/store"
+)
+
+// podcastRecentLimit 每次检查时读取的最新单集数量
+const podcastRecentLimit = 50
+
+// PodcastSubscription 用户对播客新单集通知的订阅
+type PodcastSubscription struct {
+	Server      MediaServerType `json:"server"`
+	PodcastID   string          `json:"podcastId"`
+	Title       string          `json:"title"`
+	UserIDs     []int64         `json:"userIds"`
+	LastAddedAt int64           `json:"lastAddedAt"` // 已通知的最新单集加入时间，毫秒
+}
+
+// hasUser 判断用户是否订阅了该播客
+func (s *PodcastSubscription) hasUser(userID int64) bool {
+	for _, id := range s.UserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// PodcastNotifier 定期检查播客媒体库中新加入的单集，通知订阅了该播客的用户
+type PodcastNotifier struct {
+	manager  *MediaServerManager
+	file     *store.JSONFile
+	interval time.Duration
+	notify   func(userID int64, text string)
+
+	mu            sync.Mutex
+	subscriptions map[string]*PodcastSubscription
+}
+
+// NewPodcastNotifier 创建播客新单集通知服务
+func NewPodcastNotifier(manager *MediaServerManager, cfg *config.Config, notify func(userID int64, text string)) *PodcastNotifier {
+	n := &PodcastNotifier{
+		manager:       manager,
+		file:          store.NewJSONFile(cfg.DataDir, "podcast_subscriptions.json"),
+		interval:      time.Duration(cfg.PodcastCheckInterval) * time.Minute,
+		notify:        notify,
+		subscriptions: make(map[string]*PodcastSubscription),
+	}
+
+	if err := n.file.Load(&n.subscriptions); err != nil {
+		log.Printf("加载播客订阅失败: %v", err)
+	}
+
+	return n
+}
+
+// subscriptionKey 返回订阅的键
+func subscriptionKey(serverType MediaServerType, podcastID string) string {
+	return string(serverType) + ":" + podcastID
+}
+
+// Run 按检查间隔持续检查新单集，直到 stop 被关闭
+func (n *PodcastNotifier) Run(stop <-chan struct{}) {
+	if n.interval <= 0 {
+		log.Println("播客新单集通知已关闭")
+		return
+	}
+
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			n.Check()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Subscribe 订阅播客的新单集通知，只通知订阅之后加入的单集
+func (n *PodcastNotifier) Subscribe(serverType MediaServerType, podcastID, title string, userID int64) error {
+	n.mu.Lock()
+	key := subscriptionKey(serverType, podcastID)
+	sub, ok := n.subscriptions[key]
+	if !ok {
+		sub = &PodcastSubscription{
+			Server:      serverType,
+			PodcastID:   podcastID,
+			LastAddedAt: time.Now().UnixMilli(),
+		}
+		n.subscriptions[key] = sub
+	}
+	if title != "" {
+		sub.Title = title
+	}
+	if !sub.hasUser(userID) {
+		sub.UserIDs = append(sub.UserIDs, userID)
+	}
+	n.mu.Unlock()
+
+	return n.save()
+}
+
+// Unsubscribe 取消订阅播客的新单集通知
+func (n *PodcastNotifier) Unsubscribe(serverType MediaServerType, podcastID string, userID int64) error {
+	n.mu.Lock()
+	key := subscriptionKey(serverType, podcastID)
+	if sub, ok := n.subscriptions[key]; ok {
+		userIDs := sub.UserIDs[:0]
+		for _, id := range sub.UserIDs {
+			if id != userID {
+				userIDs = append(userIDs, id)
+			}
+		}
+		sub.UserIDs = userIDs
+		if len(sub.UserIDs) == 0 {
+			delete(n.subscriptions, key)
+		}
+	}
+	n.mu.Unlock()
+
+	return n.save()
+}
+
+// IsSubscribed 判断用户是否订阅了播客
+func (n *PodcastNotifier) IsSubscribed(serverType MediaServerType, podcastID string, userID int64) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	sub, ok := n.subscriptions[subscriptionKey(serverType, podcastID)]
+	return ok && sub.hasUser(userID)
+}
+
+// save 保存订阅数据
+func (n *PodcastNotifier) save() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := n.file.Save(n.subscriptions); err != nil {
+		return fmt.Errorf("保存播客订阅失败: %w", err)
+	}
+	return nil
+}
+
+// Check 检查所有有订阅的服务器，通知订阅者新加入的单集
+func (n *PodcastNotifier) Check() {
+	n.mu.Lock()
+	servers := make(map[MediaServerType]bool)
+	for _, sub := range n.subscriptions {
+		servers[sub.Server] = true
+	}
+	n.mu.Unlock()
+
+	changed := false
+	for serverType := range servers {
+		server, err := n.manager.GetServer(serverType)
+		if err != nil {
+			log.Printf("检查播客新单集失败: %v", err)
+			continue
+		}
+		podcastManager, ok := server.(models.PodcastManager)
+		if !ok {
+			continue
+		}
+
+		episodes, err := podcastManager.GetRecentEpisodes(podcastRecentLimit)
+		if err != nil {
+			log.Printf("获取 %s 的最新播客单集失败: %v", serverType, err)
+			continue
+		}
+		if n.notifyNewEpisodes(serverType, episodes) {
+			changed = true
+		}
+	}
+
+	if changed {
+		if err := n.save(); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// notifyNewEpisodes 通知订阅者比上次通知更新的单集，返回订阅数据是否有变化
+func (n *PodcastNotifier) notifyNewEpisodes(serverType MediaServerType, episodes []models.PodcastEpisode) bool {
+	newEpisodes := make(map[string][]models.PodcastEpisode)
+	recipients := make(map[string][]int64)
+
+	n.mu.Lock()
+	for _, episode := range episodes {
+		key := subscriptionKey(serverType, episode.PodcastID)
+		sub, ok := n.subscriptions[key]
+		if !ok || episode.AddedAt <= sub.LastAddedAt {
+			continue
+		}
+		newEpisodes[key] = append(newEpisodes[key], episode)
+		recipients[key] = append([]int64(nil), sub.UserIDs...)
+	}
+	for key, list := range newEpisodes {
+		sub := n.subscriptions[key]
+		for _, episode := range list {
+			if episode.AddedAt > sub.LastAddedAt {
+				sub.LastAddedAt = episode.AddedAt
+			}
+			if episode.PodcastTitle != "" {
+				sub.Title = episode.PodcastTitle
+			}
+		}
+	}
+	n.mu.Unlock()
+
+	if n.notify != nil {
+		for key, list := range newEpisodes {
+			text := formatNewEpisodes(list)
+			for _, userID := range recipients[key] {
+				n.notify(userID, text)
+			}
+		}
+	}
+
+	return len(newEpisodes) > 0
+}
+
+// formatNewEpisodes 格式化新单集通知，单集按加入时间排序
+func formatNewEpisodes(episodes []models.PodcastEpisode) string {
+	sort.Slice(episodes, func(i, j int) bool {
+		return episodes[i].AddedAt < episodes[j].AddedAt
+	})
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🎙 %s 有 %d 个新单集:\n", episodes[0].PodcastTitle, len(episodes)))
+	for _, episode := range episodes {
+		sb.WriteString("• " + episode.Title)
+		if episode.PublishedAt > 0 {
+			sb.WriteString(" (" + time.UnixMilli(episode.PublishedAt).Format("2006-01-02") + ")")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,428 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+const (
+	// progressSyncTolerance 进度相差不超过该秒数时视为一致，不更新
+	progressSyncTolerance = 30
+	// progressDurationTolerance 同一作品在不同服务器上的时长允许相差的比例
+	progressDurationTolerance = 0.02
+	// progressDurationSlack 时长比较的最小容差（秒），不同服务器读取的时长略有差异
+	progressDurationSlack = 60
+	// progressRewindThreshold 最近更新的进度落后最远进度超过该秒数时，视为用户主动回退或重新开始，只报告不覆盖
+	progressRewindThreshold = 300
+)
+
+// ProgressEntry 某个服务器上的播放进度
+type ProgressEntry struct {
+	Server   MediaServerType
+	Progress models.ItemProgress
+}
+
+// ProgressSyncAction 将最远进度同步到另一个服务器的操作
+type ProgressSyncAction struct {
+	Title string
+	From  ProgressEntry
+	To    ProgressEntry
+	Err   error // 执行失败时的错误，试运行时为 nil
+}
+
+// ProgressConflict 无法自动同步的作品
+type ProgressConflict struct {
+	Title   string
+	Reason  string
+	Entries []ProgressEntry
+}
+
+// ProgressSyncReport 一次进度同步的结果
+type ProgressSyncReport struct {
+	DryRun    bool
+	Matched   int // 在多个服务器上匹配到的作品数量
+	Updates   []ProgressSyncAction
+	Conflicts []ProgressConflict
+	Errors    []string
+}
+
+// ProgressSync 在支持播放进度的服务器之间同步同一作品的收听进度，以最远的进度为准
+type ProgressSync struct {
+	manager  *MediaServerManager
+	interval time.Duration
+	dryRun   bool
+	notify   func(text string)
+
+	// running 保证同一时间只执行一次同步
+	running sync.Mutex
+	// reported 已通知过的更新和冲突，避免每次定期同步重复通知，试运行时同样的更新会一直出现
+	reported map[string]bool
+}
+
+// NewProgressSync 创建播放进度同步服务
+func NewProgressSync(manager *MediaServerManager, cfg *config.Config, notify func(text string)) *ProgressSync {
+	return &ProgressSync{
+		manager:  manager,
+		interval: time.Duration(cfg.ProgressSyncInterval) * time.Minute,
+		dryRun:   cfg.ProgressSyncDryRun,
+		notify:   notify,
+		reported: make(map[string]bool),
+	}
+}
+
+// Run 按同步间隔定期同步，有更新或新的冲突时通知，直到 stop 被关闭
+func (s *ProgressSync) Run(stop <-chan struct{}) {
+	if s.interval <= 0 {
+		log.Println("播放进度同步已关闭")
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			report, err := s.Sync(s.dryRun)
+			if err != nil {
+				log.Printf("同步播放进度失败: %v", err)
+				continue
+			}
+			if s.hasNews(report) && s.notify != nil {
+				s.notify(report.Format())
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// hasNews 判断定期同步的结果是否需要通知：有之前没有通知过的更新或冲突
+// 已经消失的更新和冲突从记录中删除，部分服务器获取失败时保留，避免服务器恢复后重复通知
+func (s *ProgressSync) hasNews(report *ProgressSyncReport) bool {
+	news := false
+	current := make(map[string]bool, len(report.Updates)+len(report.Conflicts))
+	mark := func(key string) {
+		current[key] = true
+		if !s.reported[key] {
+			s.reported[key] = true
+			news = true
+		}
+	}
+	for _, update := range report.Updates {
+		mark(update.key())
+	}
+	for _, conflict := range report.Conflicts {
+		mark("conflict|" + conflict.Title + "|" + conflict.Reason)
+	}
+
+	if len(report.Errors) == 0 {
+		for key := range s.reported {
+			if !current[key] {
+				delete(s.reported, key)
+			}
+		}
+	}
+	return news
+}
+
+// key 返回更新的唯一标识：作品、目标服务器和要写入的进度
+func (a *ProgressSyncAction) key() string {
+	position := fmt.Sprintf("%.0f", a.From.Progress.Position)
+	if a.From.Progress.Finished {
+		position = "finished"
+	}
+	return "update|" + a.Title + "|" + string(a.To.Server) + "|" + position
+}
+
+// Sync 匹配各服务器上的同一作品并将落后的进度更新为最远的进度，dryRun 为 true 时只生成报告不写入
+func (s *ProgressSync) Sync(dryRun bool) (*ProgressSyncReport, error) {
+	s.running.Lock()
+	defer s.running.Unlock()
+
+	report := &ProgressSyncReport{DryRun: dryRun}
+	servers := make(map[MediaServerType]models.MediaServer)
+	var serverTypes []MediaServerType
+	var entries []ProgressEntry
+	for _, serverType := range s.manager.GetServerTypes() {
+		server, err := s.manager.GetServer(serverType)
+		if err != nil {
+			continue
+		}
+		tracker, ok := server.(models.ProgressTracker)
+		if !ok {
+			continue
+		}
+		items, err := tracker.GetProgressItems()
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("获取 %s 的播放进度失败: %v", serverType, err))
+			continue
+		}
+		servers[serverType] = server
+		serverTypes = append(serverTypes, serverType)
+		for _, item := range items {
+			entries = append(entries, ProgressEntry{Server: serverType, Progress: item})
+		}
+	}
+	if len(servers) < 2 {
+		if len(report.Errors) > 0 {
+			return nil, fmt.Errorf("%s", strings.Join(report.Errors, "; "))
+		}
+		return nil, fmt.Errorf("至少需要两个支持播放进度的服务器")
+	}
+
+	for _, group := range groupProgressEntries(entries) {
+		title := group[0].Progress.Item.Title
+
+		// 在没有进度记录的服务器上搜索同一作品
+		ambiguous := false
+		for _, serverType := range serverTypes {
+			if groupHasServer(group, serverType) {
+				continue
+			}
+			matches, err := findSameWork(servers[serverType], &group[0].Progress.Item)
+			if err != nil {
+				log.Printf("在 %s 上查找 %s 失败: %v", serverType, title, err)
+				continue
+			}
+			if len(matches) > 1 {
+				report.Conflicts = append(report.Conflicts, ProgressConflict{
+					Title:   title,
+					Reason:  fmt.Sprintf("在 %s 上匹配到 %d 个项目", serverType, len(matches)),
+					Entries: group,
+				})
+				ambiguous = true
+				break
+			}
+			if len(matches) == 1 {
+				group = append(group, ProgressEntry{Server: serverType, Progress: models.ItemProgress{
+					Item:     matches[0],
+					Duration: matches[0].Duration,
+				}})
+			}
+		}
+		if ambiguous || len(group) < 2 {
+			continue
+		}
+		report.Matched++
+
+		if conflict := progressConflict(group); conflict != "" {
+			report.Conflicts = append(report.Conflicts, ProgressConflict{Title: title, Reason: conflict, Entries: group})
+			continue
+		}
+
+		furthest := furthestProgress(group)
+		for _, entry := range group {
+			if !progressBehind(entry.Progress, furthest.Progress) {
+				continue
+			}
+			action := ProgressSyncAction{Title: title, From: furthest, To: entry}
+			if !dryRun {
+				tracker := servers[entry.Server].(models.ProgressTracker)
+				position := furthest.Progress.Position
+				if entry.Progress.Duration > 0 {
+					position = math.Min(position, entry.Progress.Duration)
+				}
+				action.Err = tracker.SetProgress(entry.Progress.Item.ID, position, furthest.Progress.Finished)
+				if action.Err != nil {
+					log.Printf("更新 %s 上 %s 的播放进度失败: %v", entry.Server, title, action.Err)
+				}
+			}
+			report.Updates = append(report.Updates, action)
+		}
+	}
+
+	return report, nil
+}
+
+// groupProgressEntries 将不同服务器上的同一作品分为一组，每组中每个服务器最多一个条目
+func groupProgressEntries(entries []ProgressEntry) [][]ProgressEntry {
+	var groups [][]ProgressEntry
+	for _, entry := range entries {
+		matched := false
+		for i, group := range groups {
+			if groupHasServer(group, entry.Server) || !SameWork(&group[0].Progress.Item, &entry.Progress.Item) {
+				continue
+			}
+			groups[i] = append(group, entry)
+			matched = true
+			break
+		}
+		if !matched {
+			groups = append(groups, []ProgressEntry{entry})
+		}
+	}
+	return groups
+}
+
+// groupHasServer 判断组中是否已有该服务器的条目
+func groupHasServer(group []ProgressEntry, serverType MediaServerType) bool {
+	for _, entry := range group {
+		if entry.Server == serverType {
+			return true
+		}
+	}
+	return false
+}
+
+// findSameWork 在服务器上按标题搜索并返回与 item 为同一作品的项目
+func findSameWork(server models.MediaServer, item *models.SearchResult) ([]models.SearchResult, error) {
+	results, err := server.Search(models.SearchQuery{Text: item.Title})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []models.SearchResult
+	for i := range results {
+		if SameWork(item, &results[i]) {
+			matches = append(matches, results[i])
+		}
+	}
+	return matches, nil
+}
+
+// SameWork 判断两个项目是否为同一作品：外部 ID 相同，或者规范化后的标题和作者相同且时长相近
+// 双方有同一提供方的外部 ID 但不相同时视为不同作品
+func SameWork(a, b *models.SearchResult) bool {
+	shared := false
+	for provider, idA := range a.ProviderIDs {
+		idB, ok := b.ProviderIDs[provider]
+		if !ok {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(idA), strings.TrimSpace(idB)) {
+			return true
+		}
+		shared = true
+	}
+	if shared {
+		return false
+	}
+
+	titleA, titleB := util.NormalizeTitle(a.Title), util.NormalizeTitle(b.Title)
+	if titleA == "" || titleA != titleB {
+		return false
+	}
+	if a.Author != "" && b.Author != "" {
+		authorA, authorB := util.NormalizeTitle(a.Author), util.NormalizeTitle(b.Author)
+		if !strings.Contains(authorA, authorB) && !strings.Contains(authorB, authorA) {
+			return false
+		}
+	}
+	if a.Duration > 0 && b.Duration > 0 && !durationsMatch(a.Duration, b.Duration) {
+		return false
+	}
+	return true
+}
+
+// durationsMatch 判断两个时长是否在容差范围内
+func durationsMatch(a, b float64) bool {
+	tolerance := math.Max(progressDurationSlack, math.Max(a, b)*progressDurationTolerance)
+	return math.Abs(a-b) <= tolerance
+}
+
+// progressConflict 检查组内的进度能否自动同步，不能时返回原因
+func progressConflict(group []ProgressEntry) string {
+	for i := 1; i < len(group); i++ {
+		a, b := group[0].Progress.Duration, group[i].Progress.Duration
+		if a > 0 && b > 0 && !durationsMatch(a, b) {
+			return fmt.Sprintf("%s 与 %s 上的时长不一致 (%s / %s)", group[0].Server, group[i].Server,
+				util.FormatListeningTime(a), util.FormatListeningTime(b))
+		}
+	}
+
+	furthest := furthestProgress(group)
+	latest := group[0]
+	for _, entry := range group[1:] {
+		if entry.Progress.LastUpdate > latest.Progress.LastUpdate {
+			latest = entry
+		}
+	}
+	if latest.Server != furthest.Server && latest.Progress.LastUpdate > furthest.Progress.LastUpdate &&
+		!latest.Progress.Finished && furthest.Progress.Position-latest.Progress.Position > progressRewindThreshold {
+		return fmt.Sprintf("最近在 %s 上的进度落后于 %s，可能是重新开始收听", latest.Server, furthest.Server)
+	}
+	return ""
+}
+
+// furthestProgress 返回组中最远的进度，已播放完的优先
+func furthestProgress(group []ProgressEntry) ProgressEntry {
+	furthest := group[0]
+	for _, entry := range group[1:] {
+		if progressBehind(furthest.Progress, entry.Progress) {
+			furthest = entry
+		}
+	}
+	return furthest
+}
+
+// progressBehind 判断进度 a 是否明显落后于 b
+func progressBehind(a, b models.ItemProgress) bool {
+	if a.Finished {
+		return false
+	}
+	if b.Finished {
+		return true
+	}
+	return b.Position-a.Position > progressSyncTolerance
+}
+
+// Format 将同步结果格式化为通知文本
+func (r *ProgressSyncReport) Format() string {
+	var sb strings.Builder
+	if r.DryRun {
+		sb.WriteString("🔄 播放进度同步（试运行，未写入）\n\n")
+	} else {
+		sb.WriteString("🔄 播放进度同步\n\n")
+	}
+	sb.WriteString(fmt.Sprintf("匹配到 %d 个跨服务器作品，%d 个需要更新，%d 个冲突\n", r.Matched, len(r.Updates), len(r.Conflicts)))
+
+	if len(r.Updates) > 0 {
+		sb.WriteString("\n更新:\n")
+		for _, update := range r.Updates {
+			sb.WriteString(fmt.Sprintf("• %s: %s %s → %s (来自 %s)", update.Title, update.To.Server,
+				formatProgressPosition(update.To.Progress), formatProgressPosition(update.From.Progress), update.From.Server))
+			if update.Err != nil {
+				sb.WriteString(" ❌ " + update.Err.Error())
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(r.Conflicts) > 0 {
+		sb.WriteString("\n⚠️ 冲突（未同步）:\n")
+		for _, conflict := range r.Conflicts {
+			sb.WriteString(fmt.Sprintf("• %s: %s\n", conflict.Title, conflict.Reason))
+			for _, entry := range conflict.Entries {
+				sb.WriteString(fmt.Sprintf("    %s: %s\n", entry.Server, formatProgressPosition(entry.Progress)))
+			}
+		}
+	}
+
+	if len(r.Errors) > 0 {
+		sb.WriteString("\n❌ 错误:\n")
+		for _, err := range r.Errors {
+			sb.WriteString("• " + err + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// formatProgressPosition 格式化播放位置，已播放完时显示 “已播放”
+func formatProgressPosition(progress models.ItemProgress) string {
+	if progress.Finished {
+		return "已播放"
+	}
+	if progress.Position <= 0 {
+		return "未开始"
+	}
+	return util.FormatListeningTime(progress.Position)
+}
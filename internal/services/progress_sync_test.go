@@ -0,0 +1,243 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+func TestSameWork(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b models.SearchResult
+		want bool
+	}{
+		{
+			name: "same asin",
+			a:    models.SearchResult{Title: "三体", ProviderIDs: map[string]string{"asin": "B00ABC"}},
+			b:    models.SearchResult{Title: "The Three-Body Problem", ProviderIDs: map[string]string{"asin": " b00abc"}},
+			want: true,
+		},
+		{
+			name: "different asin",
+			a:    models.SearchResult{Title: "三体", ProviderIDs: map[string]string{"asin": "B00ABC"}},
+			b:    models.SearchResult{Title: "三体", ProviderIDs: map[string]string{"asin": "B00XYZ"}},
+			want: false,
+		},
+		{
+			name: "unrelated providers fall back to title",
+			a:    models.SearchResult{Title: "三体", ProviderIDs: map[string]string{"asin": "B00ABC"}},
+			b:    models.SearchResult{Title: "三體", ProviderIDs: map[string]string{"isbn": "9787536692930"}},
+			want: true,
+		},
+		{
+			name: "normalized title",
+			a:    models.SearchResult{Title: "Dune: Part One"},
+			b:    models.SearchResult{Title: "dune part one"},
+			want: true,
+		},
+		{
+			name: "different title",
+			a:    models.SearchResult{Title: "三体"},
+			b:    models.SearchResult{Title: "三体2"},
+			want: false,
+		},
+		{
+			name: "author contained",
+			a:    models.SearchResult{Title: "三体", Author: "刘慈欣"},
+			b:    models.SearchResult{Title: "三体", Author: "刘慈欣, 刘宇昆"},
+			want: true,
+		},
+		{
+			name: "different author",
+			a:    models.SearchResult{Title: "Dune", Author: "Frank Herbert"},
+			b:    models.SearchResult{Title: "Dune", Author: "Brian Herbert"},
+			want: false,
+		},
+		{
+			name: "duration within slack",
+			a:    models.SearchResult{Title: "三体", Duration: 1000},
+			b:    models.SearchResult{Title: "三体", Duration: 1060},
+			want: true,
+		},
+		{
+			name: "duration outside slack",
+			a:    models.SearchResult{Title: "三体", Duration: 1000},
+			b:    models.SearchResult{Title: "三体", Duration: 1061},
+			want: false,
+		},
+		{
+			name: "duration within two percent",
+			a:    models.SearchResult{Title: "三体", Duration: 50000},
+			b:    models.SearchResult{Title: "三体", Duration: 49000},
+			want: true,
+		},
+		{
+			name: "duration outside two percent",
+			a:    models.SearchResult{Title: "三体", Duration: 50000},
+			b:    models.SearchResult{Title: "三体", Duration: 48900},
+			want: false,
+		},
+		{
+			name: "unknown duration",
+			a:    models.SearchResult{Title: "三体", Duration: 50000},
+			b:    models.SearchResult{Title: "三体"},
+			want: true,
+		},
+		{
+			name: "empty title",
+			a:    models.SearchResult{Title: "——"},
+			b:    models.SearchResult{Title: "——"},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := SameWork(&tt.a, &tt.b); got != tt.want {
+			t.Errorf("%s: SameWork = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProgressBehind(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b models.ItemProgress
+		want bool
+	}{
+		{"within tolerance", models.ItemProgress{Position: 100}, models.ItemProgress{Position: 130}, false},
+		{"beyond tolerance", models.ItemProgress{Position: 100}, models.ItemProgress{Position: 131}, true},
+		{"ahead", models.ItemProgress{Position: 200}, models.ItemProgress{Position: 100}, false},
+		{"other finished", models.ItemProgress{Position: 100}, models.ItemProgress{Position: 90, Finished: true}, true},
+		{"both finished", models.ItemProgress{Finished: true}, models.ItemProgress{Finished: true}, false},
+		{"finished", models.ItemProgress{Position: 10, Finished: true}, models.ItemProgress{Position: 500}, false},
+	}
+
+	for _, tt := range tests {
+		if got := progressBehind(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: progressBehind = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFurthestProgress(t *testing.T) {
+	tests := []struct {
+		name  string
+		group []ProgressEntry
+		want  MediaServerType
+	}{
+		{
+			name: "furthest position",
+			group: []ProgressEntry{
+				{Server: EmbyServerType, Progress: models.ItemProgress{Position: 100}},
+				{Server: AbsServerType, Progress: models.ItemProgress{Position: 500}},
+			},
+			want: AbsServerType,
+		},
+		{
+			name: "first wins within tolerance",
+			group: []ProgressEntry{
+				{Server: EmbyServerType, Progress: models.ItemProgress{Position: 100}},
+				{Server: AbsServerType, Progress: models.ItemProgress{Position: 120}},
+			},
+			want: EmbyServerType,
+		},
+		{
+			name: "finished first",
+			group: []ProgressEntry{
+				{Server: EmbyServerType, Progress: models.ItemProgress{Position: 900}},
+				{Server: AbsServerType, Progress: models.ItemProgress{Finished: true}},
+			},
+			want: AbsServerType,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := furthestProgress(tt.group); got.Server != tt.want {
+			t.Errorf("%s: furthestProgress = %s, want %s", tt.name, got.Server, tt.want)
+		}
+	}
+}
+
+func TestProgressConflict(t *testing.T) {
+	entry := func(server MediaServerType, position, duration float64, lastUpdate int64, finished bool) ProgressEntry {
+		return ProgressEntry{Server: server, Progress: models.ItemProgress{
+			Position: position, Duration: duration, LastUpdate: lastUpdate, Finished: finished,
+		}}
+	}
+
+	tests := []struct {
+		name  string
+		group []ProgressEntry
+		want  string // 原因中应包含的文字，为空时不应有冲突
+	}{
+		{
+			name:  "furthest is latest",
+			group: []ProgressEntry{entry(EmbyServerType, 100, 36000, 1, false), entry(AbsServerType, 3000, 36000, 2, false)},
+		},
+		{
+			name:  "older progress behind",
+			group: []ProgressEntry{entry(EmbyServerType, 3000, 36000, 2, false), entry(AbsServerType, 100, 36000, 1, false)},
+		},
+		{
+			name:  "duration mismatch",
+			group: []ProgressEntry{entry(EmbyServerType, 100, 36000, 1, false), entry(AbsServerType, 100, 30000, 2, false)},
+			want:  "时长不一致",
+		},
+		{
+			name:  "rewind beyond threshold",
+			group: []ProgressEntry{entry(EmbyServerType, 1000, 36000, 1, false), entry(AbsServerType, 699, 36000, 2, false)},
+			want:  "重新开始",
+		},
+		{
+			name:  "rewind within threshold",
+			group: []ProgressEntry{entry(EmbyServerType, 1000, 36000, 1, false), entry(AbsServerType, 700, 36000, 2, false)},
+		},
+		{
+			name:  "latest finished",
+			group: []ProgressEntry{entry(EmbyServerType, 5000, 36000, 1, false), entry(AbsServerType, 100, 36000, 2, true)},
+		},
+	}
+
+	for _, tt := range tests {
+		got := progressConflict(tt.group)
+		if tt.want == "" && got != "" || tt.want != "" && !strings.Contains(got, tt.want) {
+			t.Errorf("%s: progressConflict = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProgressSyncHasNews(t *testing.T) {
+	s := &ProgressSync{reported: make(map[string]bool)}
+	update := func(position float64) ProgressSyncAction {
+		return ProgressSyncAction{
+			Title: "三体",
+			From:  ProgressEntry{Server: AbsServerType, Progress: models.ItemProgress{Position: position}},
+			To:    ProgressEntry{Server: EmbyServerType, Progress: models.ItemProgress{Position: 100}},
+		}
+	}
+	conflict := ProgressConflict{Title: "Dune", Reason: "时长不一致"}
+
+	steps := []struct {
+		name   string
+		report ProgressSyncReport
+		want   bool
+	}{
+		{"first update", ProgressSyncReport{DryRun: true, Updates: []ProgressSyncAction{update(3000)}}, true},
+		{"dry-run repeat", ProgressSyncReport{DryRun: true, Updates: []ProgressSyncAction{update(3000)}}, false},
+		{"position changed", ProgressSyncReport{DryRun: true, Updates: []ProgressSyncAction{update(3600)}}, true},
+		{"new conflict", ProgressSyncReport{Updates: []ProgressSyncAction{update(3600)}, Conflicts: []ProgressConflict{conflict}}, true},
+		{"conflict repeat", ProgressSyncReport{Conflicts: []ProgressConflict{conflict}}, false},
+		{"server unreachable", ProgressSyncReport{Errors: []string{"获取 emby 的播放进度失败"}}, false},
+		{"conflict kept while unreachable", ProgressSyncReport{Conflicts: []ProgressConflict{conflict}}, false},
+		{"nothing left", ProgressSyncReport{}, false},
+		{"conflict returns", ProgressSyncReport{Conflicts: []ProgressConflict{conflict}}, true},
+	}
+
+	for _, step := range steps {
+		if got := s.hasNews(&step.report); got != step.want {
+			t.Errorf("%s: hasNews = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,466 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/api"
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/store"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+// RequestStatus 媒体请求的状态
+type RequestStatus string
+
+const (
+	RequestPending   RequestStatus = "pending"
+	RequestApproved  RequestStatus = "approved"
+	RequestAvailable RequestStatus = "available"
+	RequestDenied    RequestStatus = "denied"
+)
+
+// Label 返回状态的中文名称
+func (s RequestStatus) Label() string {
+	switch s {
+	case RequestPending:
+		return "⏳ 待审核"
+	case RequestApproved:
+		return "👍 已批准"
+	case RequestAvailable:
+		return "✅ 已入库"
+	case RequestDenied:
+		return "🚫 已拒绝"
+	default:
+		return string(s)
+	}
+}
+
+// MediaRequest 用户提交的媒体请求
+type MediaRequest struct {
+	ID        int                  `json:"id"`
+	UserID    int64                `json:"userId"`
+	UserName  string               `json:"userName"`
+	Metadata  models.MediaMetadata `json:"metadata"`
+	Status    RequestStatus        `json:"status"`
+	CreatedAt time.Time            `json:"createdAt"`
+	UpdatedAt time.Time            `json:"updatedAt"`
+	Server    MediaServerType      `json:"server,omitempty"` // 入库后所在的服务器
+	ItemID    string               `json:"itemId,omitempty"` // 入库后的项目 ID
+	// Subscribers 之后请求同一作品的其他用户，状态变化时与请求者一起通知
+	Subscribers []int64 `json:"subscribers,omitempty"`
+
+	// 批准后添加到的下载管理服务
+	Acquirer       string `json:"acquirer,omitempty"`
+	AcquirerItemID int    `json:"acquirerItemId,omitempty"`
+}
+
+// IsOpen 判断请求是否仍在等待入库
+func (r *MediaRequest) IsOpen() bool {
+	return r.Status == RequestPending || r.Status == RequestApproved
+}
+
+// Requesters 返回请求者和所有之后请求同一作品的用户
+func (r *MediaRequest) Requesters() []int64 {
+	return append([]int64{r.UserID}, r.Subscribers...)
+}
+
+// HasRequester 判断用户是否请求了该作品
+func (r *MediaRequest) HasRequester(userID int64) bool {
+	for _, id := range r.Requesters() {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// requestData 持久化的请求数据
+type requestData struct {
+	NextID   int             `json:"nextId"`
+	Requests []*MediaRequest `json:"requests"`
+}
+
+// RequestService 管理用户的媒体请求：查找元数据、检查是否已入库、记录审核状态，并定期检查请求的作品是否已入库
+type RequestService struct {
+	manager   *MediaServerManager
+	providers []models.MetadataProvider
+	file      *store.JSONFile
+	interval  time.Duration
+	notify    func(userID int64, text string)
+
+	mu   sync.Mutex
+	data requestData
+}
+
+// NewRequestService 创建媒体请求服务
+func NewRequestService(manager *MediaServerManager, cfg *config.Config, notify func(userID int64, text string)) *RequestService {
+	s := &RequestService{
+		manager:   manager,
+		providers: newMetadataProviders(cfg),
+		file:      store.NewJSONFile(cfg.DataDir, "requests.json"),
+		interval:  time.Duration(cfg.RequestCheckInterval) * time.Minute,
+		notify:    notify,
+		data:      requestData{NextID: 1},
+	}
+
+	if err := s.file.Load(&s.data); err != nil {
+		log.Printf("加载媒体请求失败: %v", err)
+	}
+
+	return s
+}
+
+// newMetadataProviders 按配置的顺序创建元数据服务，未配置 API Key 的服务会被跳过
+func newMetadataProviders(cfg *config.Config) []models.MetadataProvider {
+	var providers []models.MetadataProvider
+	for _, name := range cfg.RequestProviders {
+		switch strings.ToLower(name) {
+		case models.ProviderTMDb:
+			if cfg.TmdbAPIKey == "" {
+				log.Println("未配置 TMDB_API_KEY，跳过 TMDb 元数据服务")
+				continue
+			}
+			providers = append(providers, api.NewTmdbClient(cfg))
+		case "openlibrary":
+			providers = append(providers, api.NewOpenLibraryClient())
+		case "readarr":
+			if cfg.ReadarrURL == "" || cfg.ReadarrAPIKey == "" {
+				log.Println("未配置 READARR_URL 或 READARR_API_KEY，跳过 Readarr 元数据服务")
+				continue
+			}
+			providers = append(providers, api.NewReadarrClient(cfg))
+		default:
+			log.Printf("未知的元数据服务: %s", name)
+		}
+	}
+	return providers
+}
+
+// Run 按检查间隔检查未完成的请求是否已入库，直到 stop 被关闭
+func (s *RequestService) Run(stop <-chan struct{}) {
+	if s.interval <= 0 {
+		log.Println("媒体请求入库检查已关闭")
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.CheckAvailable()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// HasProviders 判断是否配置了元数据服务
+func (s *RequestService) HasProviders() bool {
+	return len(s.providers) > 0
+}
+
+// LookupMetadata 在所有元数据服务中搜索作品，单个服务失败时继续使用其他服务的结果
+func (s *RequestService) LookupMetadata(text string) ([]models.MediaMetadata, error) {
+	if len(s.providers) == 0 {
+		return nil, fmt.Errorf("未配置元数据服务")
+	}
+
+	var results []models.MediaMetadata
+	var errs []string
+	for _, provider := range s.providers {
+		items, err := provider.SearchMetadata(text)
+		if err != nil {
+			log.Printf("在 %s 中搜索 %q 失败: %v", provider.Name(), text, err)
+			errs = append(errs, provider.Name())
+			continue
+		}
+		results = append(results, items...)
+	}
+	if len(results) == 0 && len(errs) == len(s.providers) {
+		return nil, fmt.Errorf("元数据服务查询失败: %s", strings.Join(errs, ", "))
+	}
+	return results, nil
+}
+
+// FindAvailable 在所有服务器中查找请求的作品，找到时返回所在服务器和项目
+func (s *RequestService) FindAvailable(metadata *models.MediaMetadata) (MediaServerType, *models.SearchResult, bool) {
+	titles := []string{metadata.Title}
+	if metadata.OriginalTitle != "" && metadata.OriginalTitle != metadata.Title {
+		titles = append(titles, metadata.OriginalTitle)
+	}
+
+	for _, title := range titles {
+		results, _ := s.manager.SearchAcrossServers(models.SearchQuery{Text: title, Types: []string{metadata.Kind}})
+		for _, serverType := range sortedServerTypes(results) {
+			items := results[serverType]
+			for i := range items {
+				if metadataMatches(metadata, &items[i]) {
+					return serverType, &items[i], true
+				}
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// sortedServerTypes 返回按名称排序的服务器类型，使查找结果稳定
+func sortedServerTypes(results map[MediaServerType][]models.SearchResult) []MediaServerType {
+	serverTypes := make([]MediaServerType, 0, len(results))
+	for serverType := range results {
+		serverTypes = append(serverTypes, serverType)
+	}
+	sort.Slice(serverTypes, func(i, j int) bool { return serverTypes[i] < serverTypes[j] })
+	return serverTypes
+}
+
+// metadataMatches 判断服务器中的项目是否为请求的作品：外部 ID 相同，或者类型相同且标题一致、年份相差不超过一年
+func metadataMatches(metadata *models.MediaMetadata, item *models.SearchResult) bool {
+	query := models.SearchQuery{Types: []string{metadata.Kind}}
+	if !query.MatchesType(item) {
+		return false
+	}
+
+	shared := false
+	for provider, id := range metadata.ProviderIDs {
+		itemID, ok := item.ProviderIDs[provider]
+		if !ok {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(id), strings.TrimSpace(itemID)) {
+			return true
+		}
+		shared = true
+	}
+	if shared {
+		return false
+	}
+
+	title := util.NormalizeTitle(item.Title)
+	if title == "" || (title != util.NormalizeTitle(metadata.Title) && title != util.NormalizeTitle(metadata.OriginalTitle)) {
+		return false
+	}
+	if metadata.Author != "" && item.Author != "" {
+		author, itemAuthor := util.NormalizeTitle(metadata.Author), util.NormalizeTitle(item.Author)
+		if !strings.Contains(author, itemAuthor) && !strings.Contains(itemAuthor, author) {
+			return false
+		}
+	}
+	year := item.Year
+	if year == 0 {
+		year = item.ProductionYear
+	}
+	if metadata.Year > 0 && year > 0 && (year-metadata.Year > 1 || metadata.Year-year > 1) {
+		return false
+	}
+	return true
+}
+
+// Create 记录新的请求，同一作品已有未完成的请求时将用户加入该请求并返回，created 为 false
+func (s *RequestService) Create(userID int64, userName string, metadata models.MediaMetadata) (*MediaRequest, bool, error) {
+	s.mu.Lock()
+	for _, r := range s.data.Requests {
+		if r.IsOpen() && r.Metadata.Provider == metadata.Provider && r.Metadata.ID == metadata.ID {
+			if r.HasRequester(userID) {
+				copied := *r
+				s.mu.Unlock()
+				return &copied, false, nil
+			}
+			r.Subscribers = append(r.Subscribers, userID)
+			r.UpdatedAt = time.Now()
+			copied := *r
+			s.mu.Unlock()
+			return &copied, false, s.save()
+		}
+	}
+
+	now := time.Now()
+	stored := &MediaRequest{
+		ID:        s.data.NextID,
+		UserID:    userID,
+		UserName:  userName,
+		Metadata:  metadata,
+		Status:    RequestPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.data.NextID++
+	s.data.Requests = append(s.data.Requests, stored)
+	copied := *stored
+	s.mu.Unlock()
+
+	return &copied, true, s.save()
+}
+
+// SetStatus 审核请求：只有待审核的请求可以批准，待审核和已批准的请求可以拒绝
+func (s *RequestService) SetStatus(id int, status RequestStatus) (*MediaRequest, error) {
+	s.mu.Lock()
+	request := s.find(id)
+	if request == nil {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("请求 #%d 不存在", id)
+	}
+
+	allowed := false
+	switch status {
+	case RequestApproved:
+		allowed = request.Status == RequestPending
+	case RequestDenied:
+		allowed = request.IsOpen()
+	}
+	if !allowed {
+		current := request.Status
+		s.mu.Unlock()
+		return nil, fmt.Errorf("请求 #%d 当前状态为 %s，无法修改", id, current.Label())
+	}
+
+	request.Status = status
+	request.UpdatedAt = time.Now()
+	copied := *request
+	s.mu.Unlock()
+
+	return &copied, s.save()
+}
+
+// SetAcquired 记录请求已添加到下载管理服务中的作品
+func (s *RequestService) SetAcquired(id int, acquirer string, itemID int) error {
+	s.mu.Lock()
+	request := s.find(id)
+	if request == nil {
+		s.mu.Unlock()
+		return fmt.Errorf("请求 #%d 不存在", id)
+	}
+	request.Acquirer = acquirer
+	request.AcquirerItemID = itemID
+	request.UpdatedAt = time.Now()
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// FindByAcquired 返回已添加到下载管理服务中指定作品的请求
+func (s *RequestService) FindByAcquired(acquirer string, itemID int) []MediaRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var requests []MediaRequest
+	for _, r := range s.data.Requests {
+		if r.Acquirer == acquirer && r.AcquirerItemID == itemID {
+			requests = append(requests, *r)
+		}
+	}
+	return requests
+}
+
+// Get 返回指定请求
+func (s *RequestService) Get(id int) (*MediaRequest, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	request := s.find(id)
+	if request == nil {
+		return nil, false
+	}
+	copied := *request
+	return &copied, true
+}
+
+// List 返回请求列表，最新的在前，userID 为 0 时返回所有用户的请求，否则返回用户请求过的作品
+func (s *RequestService) List(userID int64) []MediaRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var requests []MediaRequest
+	for i := len(s.data.Requests) - 1; i >= 0; i-- {
+		if userID == 0 || s.data.Requests[i].HasRequester(userID) {
+			requests = append(requests, *s.data.Requests[i])
+		}
+	}
+	return requests
+}
+
+// find 按 ID 查找请求，调用方需持有锁
+func (s *RequestService) find(id int) *MediaRequest {
+	for _, r := range s.data.Requests {
+		if r.ID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// save 保存请求数据
+func (s *RequestService) save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.file.Save(s.data); err != nil {
+		return fmt.Errorf("保存媒体请求失败: %w", err)
+	}
+	return nil
+}
+
+// CheckAvailable 检查未完成的请求是否已入库，入库后关闭请求并通知请求者
+func (s *RequestService) CheckAvailable() {
+	s.mu.Lock()
+	var open []MediaRequest
+	for _, r := range s.data.Requests {
+		if r.IsOpen() {
+			open = append(open, *r)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, r := range open {
+		serverType, item, ok := s.FindAvailable(&r.Metadata)
+		if !ok {
+			continue
+		}
+		s.MarkAvailable(r.ID, serverType, item.ID)
+	}
+}
+
+// MarkAvailable 将请求标记为已入库并通知请求者
+func (s *RequestService) MarkAvailable(id int, serverType MediaServerType, itemID string) {
+	s.mu.Lock()
+	request := s.find(id)
+	if request == nil || !request.IsOpen() {
+		s.mu.Unlock()
+		return
+	}
+	request.Status = RequestAvailable
+	request.Server = serverType
+	request.ItemID = itemID
+	request.UpdatedAt = time.Now()
+	copied := *request
+	s.mu.Unlock()
+
+	if err := s.save(); err != nil {
+		log.Printf("%v", err)
+	}
+	log.Printf("请求 #%d %s 已在 %s 中入库", copied.ID, copied.Metadata.Title, serverType)
+	if s.notify != nil {
+		for _, userID := range copied.Requesters() {
+			s.notify(userID, fmt.Sprintf("✅ 您请求的 %s 已在 %s 中入库，可以使用 /search 查找", FormatMetadataTitle(&copied.Metadata), strings.Title(string(serverType))))
+		}
+	}
+}
+
+// FormatMetadataTitle 返回带年份和作者的作品名称，如 “沙丘 (2021)”
+func FormatMetadataTitle(metadata *models.MediaMetadata) string {
+	title := metadata.Title
+	if metadata.Year > 0 {
+		title += fmt.Sprintf(" (%d)", metadata.Year)
+	}
+	if metadata.Author != "" {
+		title += " - " + metadata.Author
+	}
+	return title
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/store"
+)
+
+func TestRequestServiceCreateSubscribes(t *testing.T) {
+	var notified []int64
+	s := &RequestService{
+		file:   store.NewJSONFile(t.TempDir(), "requests.json"),
+		data:   requestData{NextID: 1},
+		notify: func(userID int64, text string) { notified = append(notified, userID) },
+	}
+	metadata := models.MediaMetadata{Provider: models.ProviderTMDb, ID: "438631", Kind: models.MediaKindMovie, Title: "Dune"}
+
+	first, created, err := s.Create(1, "alice", metadata)
+	if err != nil || !created {
+		t.Fatalf("first Create = %v, %v", created, err)
+	}
+	second, created, err := s.Create(2, "bob", metadata)
+	if err != nil || created || second.ID != first.ID {
+		t.Fatalf("second Create = #%d, %v, %v; want existing #%d", second.ID, created, err, first.ID)
+	}
+	if _, _, err := s.Create(2, "bob", metadata); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := s.Get(first.ID)
+	if got := request.Requesters(); !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Errorf("Requesters = %v, want [1 2]", got)
+	}
+	if got := s.List(2); len(got) != 1 || got[0].ID != first.ID {
+		t.Errorf("List(2) = %v, want request #%d", got, first.ID)
+	}
+
+	s.MarkAvailable(first.ID, EmbyServerType, "item")
+	if !reflect.DeepEqual(notified, []int64{1, 2}) {
+		t.Errorf("notified = %v, want [1 2]", notified)
+	}
+}
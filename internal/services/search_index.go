@@ -0,0 +1,551 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/store"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+const (
+	// indexCheckInterval 检查是否需要同步索引的间隔
+	indexCheckInterval = time.Minute
+	// indexPageSize 同步时每次读取的项目数量
+	indexPageSize = 200
+	// indexSearchLimit 索引搜索时每个服务器返回的最大结果数
+	indexSearchLimit = 100
+)
+
+// indexedServer 单个服务器的索引数据
+type indexedServer struct {
+	Items    []models.SearchResult `json:"items"`
+	SyncedAt int64                 `json:"syncedAt"` // 最近一次成功同步的时间，毫秒
+	Error    string                `json:"error,omitempty"`
+}
+
+// indexDocument 索引中的一个文档
+type indexDocument struct {
+	server MediaServerType
+	result *models.SearchResult
+}
+
+// SearchIndexServerStatus 单个服务器的索引状态
+type SearchIndexServerStatus struct {
+	Server   MediaServerType
+	Items    int
+	SyncedAt time.Time
+	Fresh    bool
+	Error    string
+}
+
+// SearchIndexStatus 搜索索引状态
+type SearchIndexStatus struct {
+	Enabled bool
+	Syncing bool
+	Terms   int
+	Servers []SearchIndexServerStatus
+}
+
+// SearchIndex 本地全文搜索索引，后台定期从各服务器同步项目元数据，中文按单字和二元组分词
+type SearchIndex struct {
+	manager  *MediaServerManager
+	file     *store.JSONFile
+	interval time.Duration
+	maxAge   time.Duration
+
+	mu       sync.RWMutex
+	servers  map[MediaServerType]*indexedServer
+	docs     []indexDocument
+	postings map[string][]int
+	terms    []string // 排序后的词项，用于前缀匹配
+	syncing  bool
+}
+
+// NewSearchIndex 创建搜索索引，并加载上次保存的索引数据
+func NewSearchIndex(manager *MediaServerManager, cfg *config.Config) *SearchIndex {
+	idx := &SearchIndex{
+		manager:  manager,
+		file:     store.NewJSONFile(cfg.DataDir, "search_index.json"),
+		interval: time.Duration(cfg.SearchIndexInterval) * time.Minute,
+		maxAge:   time.Duration(cfg.SearchIndexMaxAge) * time.Minute,
+		servers:  make(map[MediaServerType]*indexedServer),
+	}
+
+	if idx.Enabled() {
+		if err := idx.file.Load(&idx.servers); err != nil {
+			log.Printf("加载搜索索引失败: %v", err)
+		}
+		if idx.servers == nil {
+			idx.servers = make(map[MediaServerType]*indexedServer)
+		}
+		idx.rebuild()
+	}
+
+	return idx
+}
+
+// Enabled 返回是否启用了索引
+func (idx *SearchIndex) Enabled() bool {
+	return idx.interval > 0
+}
+
+// Run 定期同步到期的服务器索引，直到 stop 被关闭
+func (idx *SearchIndex) Run(stop <-chan struct{}) {
+	if !idx.Enabled() {
+		log.Println("搜索索引已关闭")
+		return
+	}
+
+	ticker := time.NewTicker(indexCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		idx.syncDue(stop)
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// syncDue 同步距上次同步超过同步间隔的服务器
+func (idx *SearchIndex) syncDue(stop <-chan struct{}) {
+	for _, serverType := range idx.manager.GetServerTypes() {
+		idx.mu.RLock()
+		server := idx.servers[serverType]
+		due := server == nil || time.Since(time.UnixMilli(server.SyncedAt)) >= idx.interval
+		idx.mu.RUnlock()
+		if !due {
+			continue
+		}
+
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		if err := idx.SyncServer(serverType, stop); err != nil {
+			log.Printf("同步 %s 搜索索引失败: %v", serverType, err)
+		}
+	}
+}
+
+// SyncServer 从服务器读取所有媒体库的项目并替换该服务器的索引数据
+func (idx *SearchIndex) SyncServer(serverType MediaServerType, stop <-chan struct{}) error {
+	server, err := idx.manager.GetServer(serverType)
+	if err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	idx.syncing = true
+	idx.mu.Unlock()
+	defer func() {
+		idx.mu.Lock()
+		idx.syncing = false
+		idx.mu.Unlock()
+	}()
+
+	started := time.Now()
+	items, err := fetchAllItems(server, stop)
+
+	idx.mu.Lock()
+	entry := idx.servers[serverType]
+	if entry == nil {
+		entry = &indexedServer{}
+		idx.servers[serverType] = entry
+	}
+	if err != nil {
+		// 同步失败时保留旧数据，过期后搜索会改用实时搜索
+		entry.Error = err.Error()
+	} else {
+		entry.Items = items
+		entry.SyncedAt = time.Now().UnixMilli()
+		entry.Error = ""
+		idx.rebuild()
+	}
+	idx.mu.Unlock()
+
+	// 持有读锁保存，避免保存时索引数据被修改
+	idx.mu.RLock()
+	saveErr := idx.file.Save(idx.servers)
+	idx.mu.RUnlock()
+	if saveErr != nil {
+		log.Printf("保存搜索索引失败: %v", saveErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%s 搜索索引同步完成: %d 个项目，耗时 %s", serverType, len(items), time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+// fetchAllItems 分页读取服务器所有媒体库中的项目
+func fetchAllItems(server models.MediaServer, stop <-chan struct{}) ([]models.SearchResult, error) {
+	libraries, err := server.GetLibraries()
+	if err != nil {
+		return nil, fmt.Errorf("获取媒体库失败: %w", err)
+	}
+
+	var items []models.SearchResult
+	for _, library := range libraries {
+		query := models.ItemQuery{PageSize: indexPageSize, SortBy: models.ItemSortName}
+		fetched := 0
+		for {
+			select {
+			case <-stop:
+				return nil, fmt.Errorf("同步已取消")
+			default:
+			}
+
+			page, err := server.ListItems(library.ID, query)
+			if err != nil {
+				return nil, fmt.Errorf("读取媒体库 %s 失败: %w", library.Name, err)
+			}
+			for _, item := range page.Items {
+				// 列表接口返回的媒体库可能只有 ID，统一使用媒体库名称
+				item.LibraryID = library.ID
+				item.Library = library.Name
+				items = append(items, item)
+			}
+
+			fetched += len(page.Items)
+			if len(page.Items) == 0 || fetched >= page.Total {
+				break
+			}
+			query.Page++
+		}
+	}
+
+	return items, nil
+}
+
+// rebuild 根据各服务器的项目重建倒排索引，调用方需持有写锁
+func (idx *SearchIndex) rebuild() {
+	serverTypes := make([]MediaServerType, 0, len(idx.servers))
+	for serverType := range idx.servers {
+		serverTypes = append(serverTypes, serverType)
+	}
+	sort.Slice(serverTypes, func(i, j int) bool { return serverTypes[i] < serverTypes[j] })
+
+	idx.docs = nil
+	idx.postings = make(map[string][]int)
+	for _, serverType := range serverTypes {
+		items := idx.servers[serverType].Items
+		for i := range items {
+			docID := len(idx.docs)
+			idx.docs = append(idx.docs, indexDocument{server: serverType, result: &items[i]})
+			for term := range documentTerms(&items[i]) {
+				idx.postings[term] = append(idx.postings[term], docID)
+			}
+		}
+	}
+
+	idx.terms = make([]string, 0, len(idx.postings))
+	for term := range idx.postings {
+		idx.terms = append(idx.terms, term)
+	}
+	sort.Strings(idx.terms)
+}
+
+// isFresh 判断服务器的索引是否在有效期内，调用方需持有读锁
+func (idx *SearchIndex) isFresh(serverType MediaServerType) bool {
+	server := idx.servers[serverType]
+	if server == nil || server.SyncedAt == 0 {
+		return false
+	}
+	return idx.maxAge <= 0 || time.Since(time.UnixMilli(server.SyncedAt)) < idx.maxAge
+}
+
+// Search 在索引中搜索，索引过期或不支持该查询的服务器改用实时搜索
+// 实时搜索失败的服务器在 errs 中返回，不影响其他服务器的结果
+func (idx *SearchIndex) Search(query models.SearchQuery) (map[MediaServerType][]models.SearchResult, map[MediaServerType]error) {
+	var indexed, stale []MediaServerType
+	idx.mu.RLock()
+	for _, serverType := range idx.manager.GetServerTypes() {
+		if !query.WantsServer(string(serverType)) {
+			continue
+		}
+		if idx.Enabled() && indexSupports(query) && idx.isFresh(serverType) {
+			indexed = append(indexed, serverType)
+		} else {
+			stale = append(stale, serverType)
+		}
+	}
+	results := idx.searchIndexed(query, indexed)
+	idx.mu.RUnlock()
+
+	liveResults, errs := idx.liveSearch(query, stale)
+	for serverType, serverResults := range liveResults {
+		results[serverType] = serverResults
+	}
+	return results, errs
+}
+
+// liveSearch 在指定的服务器中实时搜索
+func (idx *SearchIndex) liveSearch(query models.SearchQuery, serverTypes []MediaServerType) (map[MediaServerType][]models.SearchResult, map[MediaServerType]error) {
+	all := idx.manager.GetAllServers()
+	servers := make(map[MediaServerType]models.MediaServer, len(serverTypes))
+	for _, serverType := range serverTypes {
+		servers[serverType] = all[serverType]
+	}
+	return fanOut(servers, func(s models.MediaServer) ([]models.SearchResult, error) {
+		return s.Search(query)
+	})
+}
+
+// indexSupports 判断索引能否处理该查询，索引中不包含单集，搜索单集时使用实时搜索
+func indexSupports(query models.SearchQuery) bool {
+	return len(query.Types) == 0 || !query.WantsType(models.SearchTypeEpisode)
+}
+
+// searchIndexed 在指定服务器的索引数据中搜索，调用方需持有读锁
+func (idx *SearchIndex) searchIndexed(query models.SearchQuery, serverTypes []MediaServerType) map[MediaServerType][]models.SearchResult {
+	results := make(map[MediaServerType][]models.SearchResult)
+	if len(serverTypes) == 0 {
+		return results
+	}
+	wanted := make(map[MediaServerType]bool, len(serverTypes))
+	for _, serverType := range serverTypes {
+		wanted[serverType] = true
+		results[serverType] = []models.SearchResult{}
+	}
+
+	for _, docID := range idx.matchDocuments(query.Text) {
+		doc := idx.docs[docID]
+		if !wanted[doc.server] || !query.MatchesLibrary(doc.result.Library) || !query.Matches(doc.result) {
+			continue
+		}
+		results[doc.server] = append(results[doc.server], *doc.result)
+	}
+
+	// 每个服务器只保留与标题最相关的结果
+	for serverType, serverResults := range results {
+		sort.SliceStable(serverResults, func(i, j int) bool {
+			return TitleSimilarity(query.Text, serverResults[i].Title) > TitleSimilarity(query.Text, serverResults[j].Title)
+		})
+		if len(serverResults) > indexSearchLimit {
+			results[serverType] = serverResults[:indexSearchLimit]
+		}
+	}
+
+	return results
+}
+
+// matchDocuments 返回包含搜索词所有词项的文档，字母数字词项按前缀匹配
+func (idx *SearchIndex) matchDocuments(text string) []int {
+	tokens := queryTerms(text)
+	if len(tokens) == 0 {
+		docIDs := make([]int, len(idx.docs))
+		for i := range docIDs {
+			docIDs[i] = i
+		}
+		return docIDs
+	}
+
+	var matched []int
+	for i, token := range tokens {
+		var docIDs []int
+		if token.prefix {
+			docIDs = idx.prefixDocuments(token.text)
+		} else {
+			docIDs = idx.postings[token.text]
+		}
+		if i == 0 {
+			matched = docIDs
+		} else {
+			matched = intersectSorted(matched, docIDs)
+		}
+		if len(matched) == 0 {
+			return nil
+		}
+	}
+	return matched
+}
+
+// prefixDocuments 返回包含以 prefix 开头的词项的文档，结果有序且不重复
+func (idx *SearchIndex) prefixDocuments(prefix string) []int {
+	seen := make(map[int]bool)
+	var docIDs []int
+	for i := sort.SearchStrings(idx.terms, prefix); i < len(idx.terms) && strings.HasPrefix(idx.terms[i], prefix); i++ {
+		for _, docID := range idx.postings[idx.terms[i]] {
+			if !seen[docID] {
+				seen[docID] = true
+				docIDs = append(docIDs, docID)
+			}
+		}
+	}
+	sort.Ints(docIDs)
+	return docIDs
+}
+
+// intersectSorted 求两个有序文档列表的交集
+func intersectSorted(a, b []int) []int {
+	var result []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, a[i])
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return result
+}
+
+// Status 返回索引状态
+func (idx *SearchIndex) Status() SearchIndexStatus {
+	status := SearchIndexStatus{Enabled: idx.Enabled()}
+	if !status.Enabled {
+		return status
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	status.Syncing = idx.syncing
+	status.Terms = len(idx.terms)
+	for _, serverType := range idx.manager.GetServerTypes() {
+		serverStatus := SearchIndexServerStatus{Server: serverType}
+		if server := idx.servers[serverType]; server != nil {
+			serverStatus.Items = len(server.Items)
+			if server.SyncedAt > 0 {
+				serverStatus.SyncedAt = time.UnixMilli(server.SyncedAt)
+			}
+			serverStatus.Error = server.Error
+		}
+		serverStatus.Fresh = idx.isFresh(serverType)
+		status.Servers = append(status.Servers, serverStatus)
+	}
+	return status
+}
+
+// queryTerm 查询中的一个词项
+type queryTerm struct {
+	text   string
+	prefix bool
+}
+
+// documentTerms 返回文档中需要索引的词项：标题、作者、演播者、系列、分类、概述的分词结果，以及标题的拼音和拼音首字母
+func documentTerms(result *models.SearchResult) map[string]bool {
+	terms := make(map[string]bool)
+	add := func(text string) {
+		for _, run := range splitRuns(text) {
+			if run.han {
+				// 中文同时索引单字和二元组，单字查询和多字查询都能命中
+				for i := range run.runes {
+					terms[string(run.runes[i])] = true
+					if i+1 < len(run.runes) {
+						terms[string(run.runes[i:i+2])] = true
+					}
+				}
+			} else {
+				terms[string(run.runes)] = true
+			}
+		}
+	}
+
+	add(result.Title)
+	add(result.Subtitle)
+	add(result.Author)
+	for _, narrator := range result.Narrators {
+		add(narrator)
+	}
+	for _, series := range result.Series {
+		add(series.Name)
+	}
+	for _, genre := range result.Genres {
+		add(genre)
+	}
+	add(result.Overview)
+
+	// 标题拼音用于 santi 之类的拼音查询
+	title := util.NormalizeTitle(result.Title)
+	if pinyin := util.Pinyin(title); pinyin != title {
+		terms[pinyin] = true
+		terms[util.PinyinInitials(title)] = true
+	}
+
+	return terms
+}
+
+// queryTerms 对搜索词分词，中文使用二元组（单字时使用单字），字母数字词按前缀匹配
+func queryTerms(text string) []queryTerm {
+	var terms []queryTerm
+	for _, run := range splitRuns(text) {
+		if !run.han {
+			terms = append(terms, queryTerm{text: string(run.runes), prefix: true})
+			continue
+		}
+		if len(run.runes) == 1 {
+			terms = append(terms, queryTerm{text: string(run.runes)})
+			continue
+		}
+		for i := 0; i+1 < len(run.runes); i++ {
+			terms = append(terms, queryTerm{text: string(run.runes[i : i+2])})
+		}
+	}
+	return terms
+}
+
+// textRun 连续的汉字或字母数字
+type textRun struct {
+	runes []rune
+	han   bool
+}
+
+// splitRuns 规范化文本后按汉字和字母数字切分，其他字符作为分隔符
+func splitRuns(text string) []textRun {
+	var runs []textRun
+	var current []rune
+	currentHan := false
+
+	flush := func() {
+		if len(current) > 0 {
+			runs = append(runs, textRun{runes: current, han: currentHan})
+			current = nil
+		}
+	}
+
+	for _, r := range util.ToSimplified(text) {
+		// 全角 ASCII 字符转为半角
+		if r >= 0xFF01 && r <= 0xFF5E {
+			r -= 0xFEE0
+		}
+		switch {
+		case util.IsHan(r):
+			if !currentHan {
+				flush()
+			}
+			currentHan = true
+			current = append(current, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if currentHan {
+				flush()
+			}
+			currentHan = false
+			current = append(current, unicode.ToLower(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return runs
+}
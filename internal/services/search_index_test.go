@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// fakeIndexServer 只有一个媒体库的媒体服务器，searchErr 不为空时实时搜索失败
+type fakeIndexServer struct {
+	models.MediaServer
+	items     []models.SearchResult
+	searchErr error
+	searched  int
+}
+
+func (s *fakeIndexServer) GetLibraries() ([]models.LibraryInfo, error) {
+	return []models.LibraryInfo{{ID: "lib", Name: "媒体库"}}, nil
+}
+
+func (s *fakeIndexServer) ListItems(libraryID string, query models.ItemQuery) (*models.ItemPage, error) {
+	start := query.Page * query.PageSize
+	if start > len(s.items) {
+		start = len(s.items)
+	}
+	end := start + query.PageSize
+	if end > len(s.items) {
+		end = len(s.items)
+	}
+	return &models.ItemPage{Items: s.items[start:end], Total: len(s.items)}, nil
+}
+
+func (s *fakeIndexServer) Search(query models.SearchQuery) ([]models.SearchResult, error) {
+	s.searched++
+	if s.searchErr != nil {
+		return nil, s.searchErr
+	}
+	return []models.SearchResult{{ID: "live", Title: query.Text}}, nil
+}
+
+func TestSplitRuns(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"三体 The Three-Body 問題", []string{"三体", "the", "three", "body", "问题"}},
+		{"三体2：黑暗森林", []string{"三体", "2", "黑暗森林"}},
+		{"ＡＢＣ１２３", []string{"abc123"}},
+		{"  —— ", nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, run := range splitRuns(tt.text) {
+			got = append(got, string(run.runes))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitRuns(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestQueryTerms(t *testing.T) {
+	tests := []struct {
+		text string
+		want []queryTerm
+	}{
+		{"三", []queryTerm{{text: "三"}}},
+		{"三体", []queryTerm{{text: "三体"}}},
+		{"三体问题", []queryTerm{{text: "三体"}, {text: "体问"}, {text: "问题"}}},
+		{"Dune 2", []queryTerm{{text: "dune", prefix: true}, {text: "2", prefix: true}}},
+		{"沙丘dune", []queryTerm{{text: "沙丘"}, {text: "dune", prefix: true}}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		if got := queryTerms(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("queryTerms(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDocumentTerms(t *testing.T) {
+	terms := documentTerms(&models.SearchResult{Title: "三体", Author: "刘慈欣", Genres: []string{"Sci-Fi"}})
+
+	var got []string
+	for term := range terms {
+		got = append(got, term)
+	}
+	sort.Strings(got)
+	want := []string{"fi", "sci", "santi", "st", "三", "三体", "体", "刘", "刘慈", "慈", "慈欣", "欣"}
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("documentTerms = %q, want %q", got, want)
+	}
+}
+
+func newTestIndex(t *testing.T, servers map[MediaServerType]models.MediaServer) *SearchIndex {
+	t.Helper()
+	return NewSearchIndex(&MediaServerManager{servers: servers}, &config.Config{DataDir: t.TempDir(), SearchIndexInterval: 60})
+}
+
+// resultIDs 返回服务器结果的 ID，按字母排序
+func resultIDs(results []models.SearchResult) []string {
+	var ids []string
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestSearchIndexSearch(t *testing.T) {
+	emby := &fakeIndexServer{items: []models.SearchResult{
+		{ID: "1", Title: "三体", Type: models.SearchTypeSeries},
+		{ID: "2", Title: "三体问题", Type: models.SearchTypeMovie},
+		{ID: "3", Title: "Dune", Type: models.SearchTypeMovie},
+		{ID: "4", Title: "Dune: Part Two", Type: models.SearchTypeMovie},
+	}}
+	idx := newTestIndex(t, map[MediaServerType]models.MediaServer{EmbyServerType: emby})
+	if err := idx.SyncServer(EmbyServerType, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"三体", []string{"1", "2"}},
+		{"三", []string{"1", "2"}},
+		{"体问", []string{"2"}},
+		{"santi", []string{"1", "2"}},
+		{"du", []string{"3", "4"}},
+		{"dune part", []string{"4"}},
+		{"黑暗森林", nil},
+	}
+	for _, tt := range tests {
+		results, errs := idx.Search(models.SearchQuery{Text: tt.text})
+		if len(errs) != 0 {
+			t.Fatalf("Search(%q) errs = %v", tt.text, errs)
+		}
+		if got := resultIDs(results[EmbyServerType]); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+	if emby.searched != 0 {
+		t.Errorf("live searches = %d, want 0 for a fresh index", emby.searched)
+	}
+
+	// 重新同步后，已从服务器删除的项目不再出现在索引中
+	emby.items = emby.items[2:]
+	if err := idx.SyncServer(EmbyServerType, nil); err != nil {
+		t.Fatal(err)
+	}
+	results, _ := idx.Search(models.SearchQuery{Text: "三体"})
+	if got := resultIDs(results[EmbyServerType]); got != nil {
+		t.Errorf("removed items still found: %v", got)
+	}
+	for _, term := range idx.terms {
+		if term == "三体" {
+			t.Error("removed item's terms remain in the index")
+		}
+	}
+}
+
+func TestSearchIndexKeepsIndexedResultsWhenLiveSearchFails(t *testing.T) {
+	emby := &fakeIndexServer{items: []models.SearchResult{{ID: "1", Title: "三体"}}}
+	abs := &fakeIndexServer{searchErr: errors.New("connection refused")}
+	idx := newTestIndex(t, map[MediaServerType]models.MediaServer{EmbyServerType: emby, AbsServerType: abs})
+	if err := idx.SyncServer(EmbyServerType, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Audiobookshelf 尚未同步，改用实时搜索且失败
+	results, errs := idx.Search(models.SearchQuery{Text: "三体"})
+	if got := resultIDs(results[EmbyServerType]); !reflect.DeepEqual(got, []string{"1"}) {
+		t.Errorf("indexed results = %v, want [1]", got)
+	}
+	if len(errs) != 1 || errs[AbsServerType] == nil {
+		t.Errorf("errs = %v, want audiobookshelf", errs)
+	}
+	if _, ok := results[AbsServerType]; ok {
+		t.Errorf("failed server has results: %v", results[AbsServerType])
+	}
+
+	// 搜索单集时索引不可用，全部服务器使用实时搜索
+	results, errs = idx.Search(models.SearchQuery{Text: "三体", Types: []string{models.SearchTypeEpisode}})
+	if got := resultIDs(results[EmbyServerType]); !reflect.DeepEqual(got, []string{"live"}) || errs[AbsServerType] == nil {
+		t.Errorf("episode search = %v, %v; want live emby results and audiobookshelf error", results, errs)
+	}
+}
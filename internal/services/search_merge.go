@@ -0,0 +1,215 @@
+package services
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+// 标题匹配得分，按匹配方式由强到弱
+const (
+	scoreExact          = 1.0
+	scorePrefix         = 0.9
+	scoreContains       = 0.8
+	scorePinyinExact    = 0.75
+	scorePinyinPrefix   = 0.7
+	scorePinyinContains = 0.6
+	scoreInitials       = 0.5
+	// scoreFuzzyWeight 字符二元组相似度的权重
+	scoreFuzzyWeight = 0.6
+	// serverBonus 每多一个服务器拥有该项目增加的得分，用于同分时排序
+	serverBonus = 0.01
+)
+
+// ServerSearchResult 某个服务器上的搜索结果
+type ServerSearchResult struct {
+	Server MediaServerType
+	Result models.SearchResult
+}
+
+// MergedSearchResult 合并后的搜索结果，同一项目在多个服务器上只出现一次
+type MergedSearchResult struct {
+	// Result 用于展示的主结果，取得分最高的条目
+	Result models.SearchResult
+	// Entries 各服务器上的对应条目
+	Entries []ServerSearchResult
+	// Score 标题与搜索词的相似度，范围 0 到 1
+	Score float64
+}
+
+// Servers 返回拥有该项目的服务器，按名称排序且不重复
+func (m *MergedSearchResult) Servers() []MediaServerType {
+	seen := make(map[MediaServerType]bool)
+	var servers []MediaServerType
+	for _, entry := range m.Entries {
+		if !seen[entry.Server] {
+			seen[entry.Server] = true
+			servers = append(servers, entry.Server)
+		}
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i] < servers[j] })
+	return servers
+}
+
+// MergeSearchResults 合并各服务器的搜索结果：按外部 ID 或规范化标题加年份去重，并按标题相似度排序
+func MergeSearchResults(searchText string, searchResults map[MediaServerType][]models.SearchResult) []MergedSearchResult {
+	// 按服务器名称遍历，保证结果顺序稳定
+	serverTypes := make([]MediaServerType, 0, len(searchResults))
+	for serverType := range searchResults {
+		serverTypes = append(serverTypes, serverType)
+	}
+	sort.Slice(serverTypes, func(i, j int) bool { return serverTypes[i] < serverTypes[j] })
+
+	var merged []*MergedSearchResult
+	groupByKey := make(map[string]*MergedSearchResult)
+
+	for _, serverType := range serverTypes {
+		for _, result := range searchResults[serverType] {
+			entry := ServerSearchResult{Server: serverType, Result: result}
+			score := TitleSimilarity(searchText, result.Title)
+			keys := mergeKeys(&result)
+
+			var group *MergedSearchResult
+			for _, key := range keys {
+				if g, ok := groupByKey[key]; ok {
+					group = g
+					break
+				}
+			}
+			if group == nil {
+				group = &MergedSearchResult{Result: result, Score: score}
+				merged = append(merged, group)
+			} else if score > group.Score {
+				group.Result = result
+				group.Score = score
+			}
+			group.Entries = append(group.Entries, entry)
+			for _, key := range keys {
+				if _, ok := groupByKey[key]; !ok {
+					groupByKey[key] = group
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		scoreI := merged[i].Score + serverBonus*float64(len(merged[i].Servers()))
+		scoreJ := merged[j].Score + serverBonus*float64(len(merged[j].Servers()))
+		if scoreI != scoreJ {
+			return scoreI > scoreJ
+		}
+		return util.NormalizeTitle(merged[i].Result.Title) < util.NormalizeTitle(merged[j].Result.Title)
+	})
+
+	results := make([]MergedSearchResult, len(merged))
+	for i, group := range merged {
+		results[i] = *group
+	}
+	return results
+}
+
+// mergeKeys 返回用于识别重复项目的键：外部 ID，以及规范化标题加年份，都带上作品类型
+// TMDb 等服务的电影和剧集 ID 相互独立，同名同年的电影和有声书也不是同一作品
+func mergeKeys(result *models.SearchResult) []string {
+	kind := mergeKind(result)
+	var keys []string
+	for provider, id := range result.ProviderIDs {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if id != "" {
+			keys = append(keys, kind+"|"+provider+":"+id)
+		}
+	}
+	// 外部 ID 的顺序不影响分组，排序后使匹配结果稳定
+	sort.Strings(keys)
+
+	if title := util.NormalizeTitle(result.Title); title != "" {
+		keys = append(keys, kind+"|title:"+title+"|"+strconv.Itoa(result.Year))
+	}
+	return keys
+}
+
+// mergeKind 返回合并时使用的作品类型，书籍和有声书视为同一类，便于合并不同服务器上的同一本书
+func mergeKind(result *models.SearchResult) string {
+	types := models.ResultSearchTypes(result)
+	if len(types) == 0 {
+		return strings.ToLower(result.Type)
+	}
+	if types[0] == models.SearchTypeAudiobook {
+		return models.SearchTypeBook
+	}
+	return types[0]
+}
+
+// TitleSimilarity 计算搜索词与标题的相似度，支持繁简转换和拼音匹配，范围 0 到 1
+func TitleSimilarity(searchText, title string) float64 {
+	query := util.NormalizeTitle(searchText)
+	normalized := util.NormalizeTitle(title)
+	if query == "" || normalized == "" {
+		return 0
+	}
+
+	switch {
+	case normalized == query:
+		return scoreExact
+	case strings.HasPrefix(normalized, query):
+		return scorePrefix
+	case strings.Contains(normalized, query):
+		return scoreContains
+	}
+
+	// 搜索词为拼音时与标题的拼音比较
+	pinyin := util.Pinyin(normalized)
+	if pinyin != normalized {
+		queryPinyin := util.Pinyin(query)
+		switch {
+		case pinyin == queryPinyin:
+			return scorePinyinExact
+		case strings.HasPrefix(pinyin, queryPinyin):
+			return scorePinyinPrefix
+		case strings.Contains(pinyin, queryPinyin):
+			return scorePinyinContains
+		case strings.HasPrefix(util.PinyinInitials(normalized), query):
+			return scoreInitials
+		}
+	}
+
+	return scoreFuzzyWeight * bigramSimilarity(query, normalized)
+}
+
+// bigramSimilarity 计算两个字符串字符二元组的 Dice 系数
+func bigramSimilarity(a, b string) float64 {
+	bigramsA := bigrams(a)
+	bigramsB := bigrams(b)
+	if len(bigramsA) == 0 || len(bigramsB) == 0 {
+		return 0
+	}
+
+	counts := make(map[string]int, len(bigramsA))
+	for _, bigram := range bigramsA {
+		counts[bigram]++
+	}
+	common := 0
+	for _, bigram := range bigramsB {
+		if counts[bigram] > 0 {
+			counts[bigram]--
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(bigramsA)+len(bigramsB))
+}
+
+// bigrams 返回字符串中相邻两个字符组成的片段，单个字符时返回其本身
+func bigrams(s string) []string {
+	runes := []rune(s)
+	if len(runes) == 1 {
+		return []string{s}
+	}
+	result := make([]string, 0, len(runes)-1)
+	for i := 0; i+1 < len(runes); i++ {
+		result = append(result, string(runes[i:i+2]))
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+func TestMergeSearchResults(t *testing.T) {
+	tests := []struct {
+		name    string
+		results map[MediaServerType][]models.SearchResult
+		groups  int
+	}{
+		{
+			name: "same movie on two servers",
+			results: map[MediaServerType][]models.SearchResult{
+				EmbyServerType: {{ID: "1", Title: "Dune", Type: "Movie", Year: 2021, ProviderIDs: map[string]string{"tmdb": "438631"}}},
+				"jellyfin":     {{ID: "2", Title: "Dune", Type: "Movie", Year: 2021, ProviderIDs: map[string]string{"tmdb": "438631"}}},
+			},
+			groups: 1,
+		},
+		{
+			name: "movie and series sharing a tmdb id",
+			results: map[MediaServerType][]models.SearchResult{
+				EmbyServerType: {
+					{ID: "1", Title: "Dune", Type: "Movie", Year: 2021, ProviderIDs: map[string]string{"tmdb": "1399"}},
+					{ID: "2", Title: "Game of Thrones", Type: "Series", Year: 2011, ProviderIDs: map[string]string{"tmdb": "1399"}},
+				},
+			},
+			groups: 2,
+		},
+		{
+			name: "movie and audiobook with the same title and year",
+			results: map[MediaServerType][]models.SearchResult{
+				EmbyServerType: {{ID: "1", Title: "Dune", Type: "Movie", Year: 2021}},
+				AbsServerType:  {{ID: "2", Title: "Dune", Type: "book", MediaType: "audio", Year: 2021}},
+			},
+			groups: 2,
+		},
+		{
+			name: "ebook and audiobook of the same book",
+			results: map[MediaServerType][]models.SearchResult{
+				EmbyServerType: {{ID: "1", Title: "三体", Type: "Book", Year: 2008}},
+				AbsServerType:  {{ID: "2", Title: "三體", Type: "book", MediaType: "audio", Year: 2008}},
+			},
+			groups: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := MergeSearchResults("dune", tt.results)
+			if len(merged) != tt.groups {
+				t.Fatalf("got %d groups, want %d", len(merged), tt.groups)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// SearchSyntaxHelp 搜索语法说明
+const SearchSyntaxHelp = `支持的过滤条件:
+• type:movie - 类型 (movie, series, episode, music, book, audiobook, podcast)
+• year:2000 / year:>2000 / year:<=2010 / year:2000-2010 - 年份
+• genre:scifi - 分类，含空格时使用引号，如 genre:"science fiction"
+• server:emby - 服务器 (emby, abs)
+• lib:电影 - 媒体库名称
+多个值可以用逗号分隔，如 type:movie,series`
+
+// SearchQueryError 搜索语法错误，Hint 给出修正建议
+type SearchQueryError struct {
+	Message string
+	Hint    string
+}
+
+// Error 实现 error 接口
+func (e *SearchQueryError) Error() string {
+	return e.Message
+}
+
+// searchTypeAliases 搜索类型的别名
+var searchTypeAliases = map[string]string{
+	"movies":     models.SearchTypeMovie,
+	"film":       models.SearchTypeMovie,
+	"电影":         models.SearchTypeMovie,
+	"tv":         models.SearchTypeSeries,
+	"show":       models.SearchTypeSeries,
+	"shows":      models.SearchTypeSeries,
+	"电视剧":        models.SearchTypeSeries,
+	"剧集":         models.SearchTypeEpisode,
+	"episodes":   models.SearchTypeEpisode,
+	"album":      models.SearchTypeMusic,
+	"song":       models.SearchTypeMusic,
+	"音乐":         models.SearchTypeMusic,
+	"books":      models.SearchTypeBook,
+	"书籍":         models.SearchTypeBook,
+	"audiobooks": models.SearchTypeAudiobook,
+	"有声书":        models.SearchTypeAudiobook,
+	"podcasts":   models.SearchTypePodcast,
+	"播客":         models.SearchTypePodcast,
+}
+
+// searchServerAliases 服务器名称的别名
+var searchServerAliases = map[string]MediaServerType{
+	"emby":           EmbyServerType,
+	"abs":            AbsServerType,
+	"audiobookshelf": AbsServerType,
+}
+
+// ParseSearchQuery 解析带过滤条件的搜索语句，如 dune type:movie year:>2000
+// 无法识别的 key:value 会作为普通关键词处理，单字母别名的值无效时也作为关键词，如 “Y: The Last Man”
+func ParseSearchQuery(input string) (models.SearchQuery, error) {
+	var query models.SearchQuery
+	var words []string
+
+	tokens, err := tokenizeSearchQuery(input)
+	if err != nil {
+		return query, err
+	}
+
+	for _, token := range tokens {
+		key, value, found := strings.Cut(token, ":")
+		if !found {
+			words = append(words, token)
+			continue
+		}
+
+		// 在副本上解析，单字母别名的值无效时丢弃解析结果
+		parsed := query
+		switch strings.ToLower(key) {
+		case "type", "t":
+			err = parseSearchTypes(&parsed, value)
+		case "year", "y":
+			err = parseSearchYear(&parsed, value)
+		case "genre", "g":
+			err = appendSearchValues(&parsed.Genres, key, value, "genre:scifi")
+		case "server", "s":
+			err = parseSearchServers(&parsed, value)
+		case "lib", "library":
+			err = appendSearchValues(&parsed.Libraries, key, value, "lib:电影")
+		default:
+			// 不是过滤条件，例如标题中带冒号
+			words = append(words, token)
+			continue
+		}
+		if err != nil {
+			if len(key) == 1 {
+				words = append(words, token)
+				err = nil
+				continue
+			}
+			return query, err
+		}
+		query = parsed
+	}
+
+	query.Text = strings.Join(words, " ")
+	if query.Text == "" && !query.HasFilters() {
+		return query, &SearchQueryError{
+			Message: "搜索内容不能为空",
+			Hint:    "请输入关键词或过滤条件，例如: dune type:movie",
+		}
+	}
+
+	return query, nil
+}
+
+// tokenizeSearchQuery 按空白拆分搜索语句，引号中的内容视为一个整体
+func tokenizeSearchQuery(input string) ([]string, error) {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+
+	for _, r := range input {
+		switch {
+		case r == '"' || r == '“' || r == '”':
+			inQuotes = !inQuotes
+		case unicode.IsSpace(r) && !inQuotes:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, &SearchQueryError{
+			Message: "引号没有闭合",
+			Hint:    `请检查引号是否成对出现，例如: genre:"science fiction"`,
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens, nil
+}
+
+// splitSearchValues 拆分逗号分隔的多个值
+func splitSearchValues(value string) []string {
+	var values []string
+	for _, v := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '，' }) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// appendSearchValues 追加逗号分隔的值，值为空时返回错误
+func appendSearchValues(target *[]string, key, value, example string) error {
+	values := splitSearchValues(value)
+	if len(values) == 0 {
+		return &SearchQueryError{
+			Message: fmt.Sprintf("%s: 后面缺少值", key),
+			Hint:    "例如: " + example,
+		}
+	}
+	*target = append(*target, values...)
+	return nil
+}
+
+// parseSearchTypes 解析类型条件
+func parseSearchTypes(query *models.SearchQuery, value string) error {
+	values := splitSearchValues(value)
+	if len(values) == 0 {
+		return &SearchQueryError{
+			Message: "type: 后面缺少类型",
+			Hint:    "可用类型: " + strings.Join(models.SearchTypes, ", "),
+		}
+	}
+
+	for _, v := range values {
+		searchType := strings.ToLower(v)
+		if alias, ok := searchTypeAliases[searchType]; ok {
+			searchType = alias
+		}
+
+		valid := false
+		for _, t := range models.SearchTypes {
+			if t == searchType {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return &SearchQueryError{
+				Message: fmt.Sprintf("未知的类型 %q", v),
+				Hint:    "可用类型: " + strings.Join(models.SearchTypes, ", "),
+			}
+		}
+		query.Types = append(query.Types, searchType)
+	}
+
+	return nil
+}
+
+// parseSearchYear 解析年份条件，支持 2000、>2000、>=2000、<2000、<=2000 和 2000-2010
+func parseSearchYear(query *models.SearchQuery, value string) error {
+	invalid := &SearchQueryError{
+		Message: fmt.Sprintf("无法识别的年份 %q", value),
+		Hint:    "年份格式示例: year:2000, year:>2000, year:<=2010, year:2000-2010",
+	}
+
+	parseYear := func(s string) (int, bool) {
+		year, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || year < 1800 || year > time.Now().Year()+10 {
+			return 0, false
+		}
+		return year, true
+	}
+
+	var ok bool
+	switch {
+	case strings.HasPrefix(value, ">="):
+		query.YearMin, ok = parseYear(value[2:])
+	case strings.HasPrefix(value, ">"):
+		query.YearMin, ok = parseYear(value[1:])
+		query.YearMin++
+	case strings.HasPrefix(value, "<="):
+		query.YearMax, ok = parseYear(value[2:])
+	case strings.HasPrefix(value, "<"):
+		query.YearMax, ok = parseYear(value[1:])
+		query.YearMax--
+	case strings.Contains(value, "-"):
+		from, to, _ := strings.Cut(value, "-")
+		var okFrom, okTo bool
+		query.YearMin, okFrom = parseYear(from)
+		query.YearMax, okTo = parseYear(to)
+		ok = okFrom && okTo && query.YearMin <= query.YearMax
+	default:
+		query.YearMin, ok = parseYear(value)
+		query.YearMax = query.YearMin
+	}
+
+	if !ok {
+		return invalid
+	}
+	return nil
+}
+
+// parseSearchServers 解析服务器条件
+func parseSearchServers(query *models.SearchQuery, value string) error {
+	values := splitSearchValues(value)
+	hint := "可用服务器: emby, abs (audiobookshelf)"
+	if len(values) == 0 {
+		return &SearchQueryError{Message: "server: 后面缺少服务器名称", Hint: hint}
+	}
+
+	for _, v := range values {
+		serverType, ok := searchServerAliases[strings.ToLower(v)]
+		if !ok {
+			return &SearchQueryError{Message: fmt.Sprintf("未知的服务器 %q", v), Hint: hint}
+		}
+		query.Servers = append(query.Servers, string(serverType))
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		input   string
+		text    string
+		types   []string
+		yearMin int
+		yearMax int
+		servers []string
+	}{
+		{input: "dune type:movie year:>2000", text: "dune", types: []string{"movie"}, yearMin: 2001},
+		{input: "dune t:movie y:2000-2010", text: "dune", types: []string{"movie"}, yearMin: 2000, yearMax: 2010},
+		{input: "Y: The Last Man", text: "Y: The Last Man"},
+		{input: "T: the movie", text: "T: the movie"},
+		{input: "s:emby g:horror", servers: []string{"emby"}},
+		{input: "S:Darko y:2001", text: "S:Darko", yearMin: 2001, yearMax: 2001},
+		{input: "Star Wars: Andor", text: "Star Wars: Andor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			query, err := ParseSearchQuery(tt.input)
+			if err != nil {
+				t.Fatalf("ParseSearchQuery(%q) error: %v", tt.input, err)
+			}
+			if query.Text != tt.text {
+				t.Errorf("Text = %q, want %q", query.Text, tt.text)
+			}
+			if !reflect.DeepEqual(query.Types, tt.types) {
+				t.Errorf("Types = %v, want %v", query.Types, tt.types)
+			}
+			if query.YearMin != tt.yearMin || query.YearMax != tt.yearMax {
+				t.Errorf("Year = %d-%d, want %d-%d", query.YearMin, query.YearMax, tt.yearMin, tt.yearMax)
+			}
+			if !reflect.DeepEqual(query.Servers, tt.servers) {
+				t.Errorf("Servers = %v, want %v", query.Servers, tt.servers)
+			}
+		})
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	for _, input := range []string{"dune year:abc", "type:spaceship", "server:plex", `genre:"sci fi`, ""} {
+		_, err := ParseSearchQuery(input)
+		var queryErr *SearchQueryError
+		if !errors.As(err, &queryErr) {
+			t.Errorf("ParseSearchQuery(%q) error = %v, want SearchQueryError", input, err)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/api"
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/util"
+)
+
+// torrentPickupTimeout 下载完成后等待媒体服务器入库的最长时间，超时后不再查找
+const torrentPickupTimeout = 24 * time.Hour
+
+var (
+	// releaseGroupPattern 发布名称中的方括号内容，如字幕组和标签
+	releaseGroupPattern = regexp.MustCompile(`\[[^\]]*\]|【[^】]*】`)
+	// releaseTokenPattern 发布名称中标题之后的第一个标记：年份、季集、分辨率或来源
+	releaseTokenPattern = regexp.MustCompile(`(?i)^\(?((19|20)\d{2})\)?$|^s\d{1,2}(e\d{1,3})?$|^\d{3,4}p$|^(web-?dl|webrip|bluray|bdrip|remux|hdtv|x26[45]|h\.?26[45]|hevc|complete)$`)
+	// releaseYearPattern 年份标记
+	releaseYearPattern = regexp.MustCompile(`^\(?((19|20)\d{2})\)?$`)
+	// mediaFileExtPattern 单文件种子名称中的扩展名
+	mediaFileExtPattern = regexp.MustCompile(`(?i)\.(mkv|mp4|avi|m4b|m4a|mp3|flac|epub|pdf)$`)
+)
+
+// ClientTorrents 某个下载客户端中的种子
+type ClientTorrents struct {
+	Client   string
+	Torrents []models.Torrent
+	Err      error
+}
+
+// pendingPickup 已下载完成、等待媒体服务器入库的种子
+type pendingPickup struct {
+	client      string
+	name        string
+	completedAt time.Time
+}
+
+// TorrentMonitor 管理 qBittorrent、Transmission 等下载客户端，定期检查完成的下载，并在媒体服务器入库后通知
+type TorrentMonitor struct {
+	manager  *MediaServerManager
+	clients  []models.TorrentClient
+	interval time.Duration
+	// notify 发送通知，找到入库的项目时 serverType 和 itemID 不为空
+	notify func(text string, serverType MediaServerType, itemID string)
+
+	mu          sync.Mutex
+	initialized map[string]bool // 已成功读取过种子列表的客户端，首次读取只记录已完成的种子
+	completed   map[string]bool // 已完成的种子，键为 客户端:hash
+	pending     []pendingPickup
+}
+
+// NewTorrentMonitor 创建下载客户端监控，只启用配置了地址的客户端
+func NewTorrentMonitor(manager *MediaServerManager, cfg *config.Config, notify func(text string, serverType MediaServerType, itemID string)) *TorrentMonitor {
+	var clients []models.TorrentClient
+	if cfg.QbittorrentURL != "" {
+		clients = append(clients, api.NewQbittorrentClient(cfg))
+	}
+	if cfg.TransmissionURL != "" {
+		clients = append(clients, api.NewTransmissionClient(cfg))
+	}
+
+	return &TorrentMonitor{
+		manager:     manager,
+		clients:     clients,
+		interval:    time.Duration(cfg.DownloadCheckInterval) * time.Minute,
+		notify:      notify,
+		initialized: make(map[string]bool),
+		completed:   make(map[string]bool),
+	}
+}
+
+// Run 按检查间隔检查完成的下载，直到 stop 被关闭
+func (m *TorrentMonitor) Run(stop <-chan struct{}) {
+	if len(m.clients) == 0 {
+		return
+	}
+	if m.interval <= 0 {
+		log.Println("下载客户端完成通知已关闭")
+		return
+	}
+
+	// 启动时记录已完成的种子，避免重复通知
+	m.Check()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.Check()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// HasClients 判断是否配置了下载客户端
+func (m *TorrentMonitor) HasClients() bool {
+	return len(m.clients) > 0
+}
+
+// Client 按名称返回下载客户端
+func (m *TorrentMonitor) Client(name string) (models.TorrentClient, error) {
+	for _, client := range m.clients {
+		if strings.EqualFold(client.Name(), name) {
+			return client, nil
+		}
+	}
+	return nil, fmt.Errorf("未配置下载客户端 %s", name)
+}
+
+// Torrents 获取所有下载客户端中的种子
+func (m *TorrentMonitor) Torrents() []ClientTorrents {
+	results := make([]ClientTorrents, 0, len(m.clients))
+	for _, client := range m.clients {
+		torrents, err := client.GetTorrents()
+		results = append(results, ClientTorrents{Client: client.Name(), Torrents: torrents, Err: err})
+	}
+	return results
+}
+
+// Check 检查新完成的下载并通知，之后继续查找等待入库的下载，入库后附带项目链接通知
+func (m *TorrentMonitor) Check() {
+	for _, result := range m.Torrents() {
+		if result.Err != nil {
+			log.Printf("获取 %s 种子列表失败: %v", result.Client, result.Err)
+			continue
+		}
+
+		m.mu.Lock()
+		initialized := m.initialized[result.Client]
+		m.mu.Unlock()
+		present := make(map[string]bool, len(result.Torrents))
+		for _, torrent := range result.Torrents {
+			key := result.Client + ":" + torrent.ID
+			present[key] = true
+			m.mu.Lock()
+			known := m.completed[key]
+			if torrent.Completed {
+				m.completed[key] = true
+			}
+			m.mu.Unlock()
+
+			if torrent.Completed && !known && initialized {
+				m.handleCompleted(result.Client, torrent)
+			}
+		}
+
+		// 启动时无法连接的客户端在首次读取成功后才记录已完成的种子
+		// 已从客户端删除的种子不再记录，避免长期运行时记录不断增加
+		m.mu.Lock()
+		m.initialized[result.Client] = true
+		prefix := result.Client + ":"
+		for key := range m.completed {
+			if strings.HasPrefix(key, prefix) && !present[key] {
+				delete(m.completed, key)
+			}
+		}
+		m.mu.Unlock()
+	}
+
+	m.mu.Lock()
+	pending := m.pending
+	m.pending = nil
+	m.mu.Unlock()
+
+	var waiting []pendingPickup
+	for _, p := range pending {
+		if serverType, item, ok := m.findMediaItem(p.name); ok {
+			m.notify(fmt.Sprintf("🎬 %s 下载的 %s 已在 %s 中入库", p.client, item.Title, strings.Title(string(serverType))), serverType, item.ID)
+			continue
+		}
+		if time.Since(p.completedAt) < torrentPickupTimeout {
+			waiting = append(waiting, p)
+		}
+	}
+
+	m.mu.Lock()
+	m.pending = append(m.pending, waiting...)
+	m.mu.Unlock()
+}
+
+// handleCompleted 通知下载完成，媒体服务器已入库时附带项目链接，否则等待入库
+func (m *TorrentMonitor) handleCompleted(client string, torrent models.Torrent) {
+	log.Printf("%s 下载完成: %s", client, torrent.Name)
+	if serverType, item, ok := m.findMediaItem(torrent.Name); ok {
+		m.notify(fmt.Sprintf("✅ %s 下载完成: %s\n\n已在 %s 中入库: %s", client, torrent.Name, strings.Title(string(serverType)), item.Title), serverType, item.ID)
+		return
+	}
+
+	m.notify(fmt.Sprintf("✅ %s 下载完成: %s\n\n媒体服务器入库后会再次通知", client, torrent.Name), "", "")
+	m.mu.Lock()
+	m.pending = append(m.pending, pendingPickup{client: client, name: torrent.Name, completedAt: time.Now()})
+	m.mu.Unlock()
+}
+
+// findMediaItem 按发布名称中的标题和年份在所有服务器中查找项目，优先匹配电影、剧集和书籍，其次是单集
+func (m *TorrentMonitor) findMediaItem(name string) (MediaServerType, *models.SearchResult, bool) {
+	title, year := ParseReleaseName(name)
+	if title == "" {
+		return "", nil, false
+	}
+	normalized := util.NormalizeTitle(title)
+
+	results, _ := m.manager.SearchAcrossServers(models.SearchQuery{Text: title})
+	serverTypes := sortedServerTypes(results)
+	for _, episodes := range []bool{false, true} {
+		for _, serverType := range serverTypes {
+			items := results[serverType]
+			for i := range items {
+				item := &items[i]
+				if (item.Type == "episode") != episodes {
+					continue
+				}
+				itemTitle := item.Title
+				if episodes {
+					itemTitle = item.SeriesName
+				}
+				if util.NormalizeTitle(itemTitle) != normalized {
+					continue
+				}
+				itemYear := item.Year
+				if itemYear == 0 {
+					itemYear = item.ProductionYear
+				}
+				if year > 0 && itemYear > 0 && (itemYear-year > 1 || year-itemYear > 1) {
+					continue
+				}
+				return serverType, item, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// ParseReleaseName 从种子的发布名称中取出标题和年份，如 “Dune.Part.Two.2024.2160p.WEB-DL” 返回 “Dune Part Two” 和 2024
+func ParseReleaseName(name string) (string, int) {
+	name = mediaFileExtPattern.ReplaceAllString(name, "")
+	name = releaseGroupPattern.ReplaceAllString(name, " ")
+	name = strings.NewReplacer(".", " ", "_", " ").Replace(name)
+
+	var words []string
+	year := 0
+	for _, token := range strings.Fields(name) {
+		// 标题本身可能是年份，如 “1917”
+		if len(words) > 0 && releaseTokenPattern.MatchString(token) {
+			if match := releaseYearPattern.FindStringSubmatch(token); match != nil {
+				year, _ = strconv.Atoi(match[1])
+			}
+			break
+		}
+		words = append(words, token)
+	}
+	return strings.TrimSpace(strings.Trim(strings.Join(words, " "), "-")), year
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// fakeTorrentClient 返回预设种子列表的下载客户端，err 不为空时获取失败
+type fakeTorrentClient struct {
+	name     string
+	torrents []models.Torrent
+	err      error
+}
+
+func (c *fakeTorrentClient) Name() string { return c.name }
+
+func (c *fakeTorrentClient) GetTorrents() ([]models.Torrent, error) { return c.torrents, c.err }
+
+func (c *fakeTorrentClient) PauseTorrent(id string) error { return nil }
+
+func (c *fakeTorrentClient) ResumeTorrent(id string) error { return nil }
+
+func (c *fakeTorrentClient) DeleteTorrent(id string, deleteFiles bool) error { return nil }
+
+func TestTorrentMonitorBaselinePerClient(t *testing.T) {
+	qbittorrent := &fakeTorrentClient{name: "qBittorrent", torrents: []models.Torrent{
+		{ID: "a", Name: "Dune.2021.2160p", Completed: true},
+	}}
+	transmission := &fakeTorrentClient{name: "Transmission", err: errors.New("connection refused"), torrents: []models.Torrent{
+		{ID: "b", Name: "Severance.S01.1080p", Completed: true},
+	}}
+
+	var notified []string
+	m := &TorrentMonitor{
+		manager:     &MediaServerManager{servers: make(map[MediaServerType]models.MediaServer)},
+		clients:     []models.TorrentClient{qbittorrent, transmission},
+		initialized: make(map[string]bool),
+		completed:   make(map[string]bool),
+		notify: func(text string, serverType MediaServerType, itemID string) {
+			notified = append(notified, text)
+		},
+	}
+
+	// 启动时 Transmission 无法连接
+	m.Check()
+	if len(notified) != 0 {
+		t.Fatalf("startup notifications = %q, want none", notified)
+	}
+
+	// Transmission 恢复后首次读取只记录已完成的种子
+	transmission.err = nil
+	m.Check()
+	if len(notified) != 0 {
+		t.Fatalf("notifications after reconnect = %q, want none", notified)
+	}
+
+	transmission.torrents = append(transmission.torrents, models.Torrent{ID: "c", Name: "Frieren.S01E01.1080p", Completed: true})
+	qbittorrent.torrents = append(qbittorrent.torrents, models.Torrent{ID: "d", Name: "Arrival.2016.1080p", Progress: 0.5})
+	m.Check()
+	if len(notified) != 1 || !strings.Contains(notified[0], "Frieren.S01E01.1080p") {
+		t.Errorf("notifications = %q, want only the new Transmission download", notified)
+	}
+}
+
+func TestTorrentMonitorPrunesRemovedTorrents(t *testing.T) {
+	qbittorrent := &fakeTorrentClient{name: "qBittorrent", torrents: []models.Torrent{
+		{ID: "a", Name: "Dune.2021.2160p", Completed: true},
+		{ID: "b", Name: "Arrival.2016.1080p", Completed: true},
+	}}
+	transmission := &fakeTorrentClient{name: "Transmission", torrents: []models.Torrent{
+		{ID: "c", Name: "Severance.S01.1080p", Completed: true},
+	}}
+	m := &TorrentMonitor{
+		manager:     &MediaServerManager{servers: make(map[MediaServerType]models.MediaServer)},
+		clients:     []models.TorrentClient{qbittorrent, transmission},
+		initialized: make(map[string]bool),
+		completed:   make(map[string]bool),
+		notify:      func(text string, serverType MediaServerType, itemID string) {},
+	}
+	m.Check()
+
+	// qBittorrent 中删除了一个种子，Transmission 暂时无法连接时保留其记录
+	qbittorrent.torrents = qbittorrent.torrents[:1]
+	transmission.err = errors.New("connection refused")
+	m.Check()
+
+	want := map[string]bool{"qBittorrent:a": true, "Transmission:c": true}
+	if !reflect.DeepEqual(m.completed, want) {
+		t.Errorf("completed = %v, want %v", m.completed, want)
+	}
+}
+
+func TestParseReleaseName(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		year  int
+	}{
+		{"Dune.Part.Two.2024.2160p.WEB-DL", "Dune Part Two", 2024},
+		{"1917.2019.1080p.BluRay.x264", "1917", 2019},
+		{"1917 (2019) 1080p", "1917", 2019},
+		{"[Group] Severance S01E02 1080p", "Severance", 0},
+		{"【字幕组】葬送的芙莉莲 S01E01 [1080p]", "葬送的芙莉莲", 0},
+		{"Severance.S01E02.1080p.WEB-DL.mkv", "Severance", 0},
+		{"The.Three-Body.Problem.2024.S01.2160p", "The Three-Body Problem", 2024},
+		{"Some.Show.-.S01E02.720p", "Some Show", 0},
+		{"Movie_Name_2021_720p", "Movie Name", 2021},
+		{"Arrival.2016.mkv", "Arrival", 2016},
+		{"三体.m4b", "三体", 0},
+	}
+
+	for _, tt := range tests {
+		title, year := ParseReleaseName(tt.name)
+		if title != tt.title || year != tt.year {
+			t.Errorf("ParseReleaseName(%q) = %q, %d; want %q, %d", tt.name, title, year, tt.title, tt.year)
+		}
+	}
+}
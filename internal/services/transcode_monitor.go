@@ -0,0 +1,347 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/config"
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/store"
+)
+
+// TranscodeSample 一次采样中各服务器的并发转码数，只记录存在转码的采样
+type TranscodeSample struct {
+	Time   int64                   `json:"time"`
+	Counts map[MediaServerType]int `json:"counts"`
+}
+
+// TranscodeEvent 一次转码播放的记录
+type TranscodeEvent struct {
+	Server               MediaServerType `json:"server"`
+	SessionID            string          `json:"sessionId"`
+	ItemID               string          `json:"itemId"`
+	ItemTitle            string          `json:"itemTitle"`
+	UserName             string          `json:"userName"`
+	Client               string          `json:"client"`
+	DeviceName           string          `json:"deviceName"`
+	VideoCodec           string          `json:"videoCodec,omitempty"`
+	AudioCodec           string          `json:"audioCodec,omitempty"`
+	HardwareAcceleration string          `json:"hardwareAcceleration,omitempty"`
+	Reasons              []string        `json:"reasons,omitempty"`
+	FirstSeen            int64           `json:"firstSeen"`
+	LastSeen             int64           `json:"lastSeen"`
+}
+
+// transcodeHistory 持久化的转码记录
+type transcodeHistory struct {
+	Samples []TranscodeSample `json:"samples"`
+	Events  []TranscodeEvent  `json:"events"`
+}
+
+// TranscodeMonitor 周期性采样各服务器的转码负载，超出限制时发出告警
+type TranscodeMonitor struct {
+	manager   *MediaServerManager
+	file      *store.JSONFile
+	limit     int
+	interval  time.Duration
+	retention time.Duration
+	notify    func(text string)
+
+	mu       sync.Mutex
+	history  transcodeHistory
+	alerting map[MediaServerType]bool
+}
+
+// NewTranscodeMonitor 创建转码监控服务
+func NewTranscodeMonitor(manager *MediaServerManager, cfg *config.Config, notify func(text string)) *TranscodeMonitor {
+	m := &TranscodeMonitor{
+		manager:   manager,
+		file:      store.NewJSONFile(cfg.DataDir, "transcodes.json"),
+		limit:     cfg.TranscodeLimit,
+		interval:  time.Duration(cfg.TranscodeSampleInterval) * time.Second,
+		retention: time.Duration(cfg.TranscodeHistoryDays) * 24 * time.Hour,
+		notify:    notify,
+		alerting:  make(map[MediaServerType]bool),
+	}
+
+	if err := m.file.Load(&m.history); err != nil {
+		log.Printf("加载转码记录失败: %v", err)
+	}
+
+	return m
+}
+
+// Run 按采样间隔持续采样，直到 stop 被关闭
+func (m *TranscodeMonitor) Run(stop <-chan struct{}) {
+	if m.interval <= 0 {
+		log.Println("转码采样已关闭")
+		return
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.Sample()
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Sample 执行一次采样
+func (m *TranscodeMonitor) Sample() {
+	sessions, errs := m.manager.GetSessionsAcrossServers()
+	for serverType, err := range errs {
+		log.Printf("转码采样获取 %s 会话失败: %v", serverType, err)
+	}
+
+	now := time.Now()
+	counts := make(map[MediaServerType]int)
+	var transcoding []TranscodeEvent
+
+	// 获取会话失败的服务器不计入记录，避免被当作没有转码
+	for serverType, serverSessions := range sessions {
+		if errs[serverType] != nil {
+			continue
+		}
+		for _, session := range serverSessions {
+			if !session.IsTranscoding() {
+				continue
+			}
+			counts[serverType]++
+			transcoding = append(transcoding, newTranscodeEvent(serverType, session, now))
+		}
+	}
+
+	m.mu.Lock()
+	changed := m.record(now, counts, transcoding)
+	alerts := m.checkLimit(counts, errs)
+	history := m.history
+	m.mu.Unlock()
+
+	if changed {
+		if err := m.file.Save(history); err != nil {
+			log.Printf("保存转码记录失败: %v", err)
+		}
+	}
+
+	if m.notify != nil {
+		for _, alert := range alerts {
+			m.notify(alert)
+		}
+	}
+}
+
+// newTranscodeEvent 根据播放会话创建转码记录
+func newTranscodeEvent(serverType MediaServerType, session models.PlaybackSession, now time.Time) TranscodeEvent {
+	event := TranscodeEvent{
+		Server:     serverType,
+		SessionID:  session.ID,
+		ItemID:     session.ItemID,
+		ItemTitle:  session.ItemTitle,
+		UserName:   session.UserName,
+		Client:     session.Client,
+		DeviceName: session.DeviceName,
+		FirstSeen:  now.UnixMilli(),
+		LastSeen:   now.UnixMilli(),
+	}
+	if t := session.Transcoding; t != nil {
+		event.VideoCodec = t.VideoCodec
+		event.AudioCodec = t.AudioCodec
+		event.HardwareAcceleration = t.HardwareAcceleration
+		event.Reasons = t.Reasons
+	}
+	return event
+}
+
+// record 记录采样结果并清理过期数据，返回记录是否有变化，调用方需持有锁
+func (m *TranscodeMonitor) record(now time.Time, counts map[MediaServerType]int, transcoding []TranscodeEvent) bool {
+	changed := len(counts) > 0 || len(transcoding) > 0
+	if len(counts) > 0 {
+		m.history.Samples = append(m.history.Samples, TranscodeSample{
+			Time:   now.UnixMilli(),
+			Counts: counts,
+		})
+	}
+
+	// 同一会话播放同一项目视为一次转码，只更新最后出现时间
+	for _, event := range transcoding {
+		found := false
+		for i := len(m.history.Events) - 1; i >= 0; i-- {
+			existing := &m.history.Events[i]
+			if existing.Server == event.Server && existing.SessionID == event.SessionID && existing.ItemID == event.ItemID {
+				existing.LastSeen = event.LastSeen
+				existing.Reasons = event.Reasons
+				existing.HardwareAcceleration = event.HardwareAcceleration
+				found = true
+				break
+			}
+		}
+		if !found {
+			m.history.Events = append(m.history.Events, event)
+		}
+	}
+
+	if m.retention <= 0 {
+		return changed
+	}
+	cutoff := now.Add(-m.retention).UnixMilli()
+
+	samples := m.history.Samples[:0]
+	for _, sample := range m.history.Samples {
+		if sample.Time >= cutoff {
+			samples = append(samples, sample)
+		}
+	}
+
+	events := m.history.Events[:0]
+	for _, event := range m.history.Events {
+		if event.LastSeen >= cutoff {
+			events = append(events, event)
+		}
+	}
+	pruned := len(samples) < len(m.history.Samples) || len(events) < len(m.history.Events)
+	m.history.Samples = samples
+	m.history.Events = events
+	return changed || pruned
+}
+
+// checkLimit 检查是否超出并发转码限制，只在首次超出时告警，获取会话失败的服务器保持原状态，调用方需持有锁
+func (m *TranscodeMonitor) checkLimit(counts map[MediaServerType]int, errs map[MediaServerType]error) []string {
+	if m.limit <= 0 {
+		return nil
+	}
+
+	var alerts []string
+	for _, serverType := range m.manager.GetServerTypes() {
+		if errs[serverType] != nil {
+			continue
+		}
+		count := counts[serverType]
+		if count > m.limit {
+			if !m.alerting[serverType] {
+				alerts = append(alerts, fmt.Sprintf("⚠️ %s 服务器当前有 %d 个并发转码，超过限制 %d", serverType, count, m.limit))
+			}
+			m.alerting[serverType] = true
+		} else if m.alerting[serverType] {
+			alerts = append(alerts, fmt.Sprintf("✅ %s 服务器并发转码已恢复到 %d 个", serverType, count))
+			m.alerting[serverType] = false
+		}
+	}
+
+	return alerts
+}
+
+// ReasonCount 转码原因及次数
+type ReasonCount struct {
+	Reason string
+	Count  int
+}
+
+// TranscodeReport 转码统计报告
+type TranscodeReport struct {
+	Days             int
+	Limit            int
+	TotalTranscodes  int
+	PeakConcurrent   map[MediaServerType]int
+	OverLimitSamples int
+	Reasons          []ReasonCount
+	ByUser           map[string][]ReasonCount
+	ByClient         map[string][]ReasonCount
+}
+
+// Report 生成最近 days 天的转码统计报告
+func (m *TranscodeMonitor) Report(days int) *TranscodeReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cutoff := time.Now().AddDate(0, 0, -days).UnixMilli()
+	report := &TranscodeReport{
+		Days:           days,
+		Limit:          m.limit,
+		PeakConcurrent: make(map[MediaServerType]int),
+	}
+
+	for _, sample := range m.history.Samples {
+		if sample.Time < cutoff {
+			continue
+		}
+		overLimit := false
+		for serverType, count := range sample.Counts {
+			if count > report.PeakConcurrent[serverType] {
+				report.PeakConcurrent[serverType] = count
+			}
+			if m.limit > 0 && count > m.limit {
+				overLimit = true
+			}
+		}
+		if overLimit {
+			report.OverLimitSamples++
+		}
+	}
+
+	reasons := make(map[string]int)
+	byUser := make(map[string]map[string]int)
+	byClient := make(map[string]map[string]int)
+	for _, event := range m.history.Events {
+		if event.LastSeen < cutoff {
+			continue
+		}
+		report.TotalTranscodes++
+
+		eventReasons := event.Reasons
+		if len(eventReasons) == 0 {
+			eventReasons = []string{"Unknown"}
+		}
+		for _, reason := range eventReasons {
+			reasons[reason]++
+			addReason(byUser, event.UserName, reason)
+			addReason(byClient, event.Client, reason)
+		}
+	}
+
+	report.Reasons = sortReasons(reasons)
+	report.ByUser = make(map[string][]ReasonCount, len(byUser))
+	for user, counts := range byUser {
+		report.ByUser[user] = sortReasons(counts)
+	}
+	report.ByClient = make(map[string][]ReasonCount, len(byClient))
+	for client, counts := range byClient {
+		report.ByClient[client] = sortReasons(counts)
+	}
+
+	return report
+}
+
+// addReason 累加某个分组下的转码原因次数
+func addReason(groups map[string]map[string]int, key, reason string) {
+	if key == "" {
+		key = "Unknown"
+	}
+	if groups[key] == nil {
+		groups[key] = make(map[string]int)
+	}
+	groups[key][reason]++
+}
+
+// sortReasons 按次数从多到少排序转码原因
+func sortReasons(counts map[string]int) []ReasonCount {
+	result := make([]ReasonCount, 0, len(counts))
+	for reason, count := range counts {
+		result = append(result, ReasonCount{Reason: reason, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Reason < result[j].Reason
+	})
+	return result
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+	"github.com/Heathcliff-third-space/MediaManager/internal/store"
+)
+
+// fakeSessionServer 只实现 GetSessions 的媒体服务器，err 不为空时获取失败
+type fakeSessionServer struct {
+	models.MediaServer
+	sessions []models.PlaybackSession
+	err      error
+}
+
+func (s *fakeSessionServer) GetSessions() ([]models.PlaybackSession, error) {
+	return s.sessions, s.err
+}
+
+func TestTranscodeMonitorSkipsUnreachableServers(t *testing.T) {
+	server := &fakeSessionServer{sessions: []models.PlaybackSession{
+		{ID: "1", ItemID: "a", PlayMethod: models.PlayMethodTranscode},
+		{ID: "2", ItemID: "b", PlayMethod: models.PlayMethodTranscode},
+	}}
+
+	var alerts []string
+	m := &TranscodeMonitor{
+		manager:  &MediaServerManager{servers: map[MediaServerType]models.MediaServer{EmbyServerType: server}},
+		file:     store.NewJSONFile(t.TempDir(), "transcodes.json"),
+		limit:    1,
+		notify:   func(text string) { alerts = append(alerts, text) },
+		alerting: make(map[MediaServerType]bool),
+	}
+
+	m.Sample()
+	if len(alerts) != 1 || !strings.Contains(alerts[0], "超过限制") {
+		t.Fatalf("alerts = %q, want one over-limit alert", alerts)
+	}
+
+	// 服务器暂时无法访问时不视为恢复，也不记录采样
+	server.err = errors.New("connection refused")
+	m.Sample()
+	if len(alerts) != 1 {
+		t.Fatalf("alerts after failed sample = %q, want no recovery", alerts)
+	}
+	if len(m.history.Samples) != 1 {
+		t.Errorf("samples = %d, want 1", len(m.history.Samples))
+	}
+
+	server.err = nil
+	server.sessions = server.sessions[:1]
+	m.Sample()
+	if len(alerts) != 2 || !strings.Contains(alerts[1], "恢复") {
+		t.Errorf("alerts = %q, want recovery once the server answers", alerts)
+	}
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+// uploadSafetyMargin 拆分时每部分的目标大小占上限的比例，按时长估算的字节位置对可变码率文件并不精确
+const uploadSafetyMargin = 0.9
+
+// splittableExts 可以按字节拆分后仍能正常播放的格式，MP3 的每一帧可以独立解码
+var splittableExts = map[string]bool{
+	".mp3": true,
+}
+
+// mp4Exts 使用 MP4 容器的格式，索引（moov）在文件头或文件尾，按字节拆分后的部分无法播放
+var mp4Exts = map[string]bool{
+	".m4b": true,
+	".m4a": true,
+	".mp4": true,
+	".m4v": true,
+	".mov": true,
+}
+
+// UploadPart 上传的一个文件或文件的一部分
+type UploadPart struct {
+	File     models.DownloadFile
+	Name     string  // 上传时使用的文件名
+	Index    int     // 在文件中的序号，从 1 开始
+	Count    int     // 文件拆分的部分数，未拆分时为 1
+	Start    int64   // 字节范围的起点
+	End      int64   // 字节范围的终点（不含），未拆分时为 0，表示整个文件
+	Duration float64 // 秒，按字节比例估算
+	Chapters string  // 包含的章节，如 “第一章 - 第五章”
+}
+
+// UploadPlan 上传计划
+type UploadPlan struct {
+	Parts   []UploadPart
+	Skipped []SkippedUpload // 超出大小限制且无法拆分的文件
+}
+
+// SkippedUpload 超出大小限制且无法拆分的文件及原因
+type SkippedUpload struct {
+	File   models.DownloadFile
+	Reason string
+}
+
+// PlanUploads 为文件制定上传计划，超出大小限制的 MP3 文件按章节拆分，没有章节时平均拆分，其他格式跳过并说明原因
+func PlanUploads(files []models.DownloadFile, limit int64) UploadPlan {
+	var plan UploadPlan
+	for _, file := range files {
+		if file.Size <= limit {
+			plan.Parts = append(plan.Parts, UploadPart{File: file, Name: file.Name, Index: 1, Count: 1, Duration: file.Duration})
+			continue
+		}
+
+		ext := strings.ToLower(path.Ext(file.Name))
+		if !splittableExts[ext] {
+			plan.Skipped = append(plan.Skipped, SkippedUpload{File: file, Reason: unsplittableReason(ext)})
+			continue
+		}
+
+		ranges := splitRanges(file, int64(float64(limit)*uploadSafetyMargin))
+		base := strings.TrimSuffix(file.Name, path.Ext(file.Name))
+		for i, r := range ranges {
+			part := UploadPart{
+				File:     file,
+				Name:     fmt.Sprintf("%s.part%02d%s", base, i+1, path.Ext(file.Name)),
+				Index:    i + 1,
+				Count:    len(ranges),
+				Start:    r[0],
+				End:      r[1],
+				Chapters: chaptersInRange(file, r[0], r[1]),
+			}
+			if file.Size > 0 {
+				part.Duration = file.Duration * float64(r[1]-r[0]) / float64(file.Size)
+			}
+			plan.Parts = append(plan.Parts, part)
+		}
+	}
+	return plan
+}
+
+// unsplittableReason 返回格式无法拆分的原因
+func unsplittableReason(ext string) string {
+	if mp4Exts[ext] {
+		return "MP4 容器（m4b/m4a 等）按字节拆分后无法播放，请在服务器上转换为 MP3 或按章节拆分后再下载"
+	}
+	return "该格式按字节拆分后无法播放"
+}
+
+// byteOffset 按时长比例估算时间点对应的字节位置
+func byteOffset(file models.DownloadFile, seconds float64) int64 {
+	if file.Duration <= 0 {
+		return 0
+	}
+	offset := int64(float64(file.Size) * seconds / file.Duration)
+	if offset < 0 {
+		return 0
+	}
+	if offset > file.Size {
+		return file.Size
+	}
+	return offset
+}
+
+// splitRanges 将文件拆分为不超过 maxBytes 的字节范围，优先在章节开头处拆分
+func splitRanges(file models.DownloadFile, maxBytes int64) [][2]int64 {
+	cuts := []int64{0}
+	if file.Duration > 0 {
+		last := int64(0)
+		for i := 1; i < len(file.Chapters); i++ {
+			start := byteOffset(file, file.Chapters[i].Start)
+			end := byteOffset(file, file.Chapters[i].End)
+			if i+1 == len(file.Chapters) {
+				end = file.Size
+			}
+			// 加入这一章会超出限制时，在这一章开头处拆分
+			if end-last > maxBytes && start > last {
+				cuts = append(cuts, start)
+				last = start
+			}
+		}
+	}
+	cuts = append(cuts, file.Size)
+
+	var ranges [][2]int64
+	for i := 0; i+1 < len(cuts); i++ {
+		start, end := cuts[i], cuts[i+1]
+		// 单个章节超出限制时平均拆分
+		count := (end - start + maxBytes - 1) / maxBytes
+		for j := int64(0); j < count; j++ {
+			ranges = append(ranges, [2]int64{start + (end-start)*j/count, start + (end-start)*(j+1)/count})
+		}
+	}
+	return ranges
+}
+
+// chaptersInRange 返回开头位于字节范围内的第一个和最后一个章节名称
+func chaptersInRange(file models.DownloadFile, start, end int64) string {
+	var titles []string
+	for _, chapter := range file.Chapters {
+		offset := byteOffset(file, chapter.Start)
+		if offset >= start && offset < end && chapter.Title != "" {
+			titles = append(titles, chapter.Title)
+		}
+	}
+	switch len(titles) {
+	case 0:
+		return ""
+	case 1:
+		return titles[0]
+	default:
+		return titles[0] + " - " + titles[len(titles)-1]
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Heathcliff-third-space/MediaManager/internal/models"
+)
+
+func TestPlanUploads(t *testing.T) {
+	const limit = 100
+	files := []models.DownloadFile{
+		{ID: "1", Name: "small.m4b", Size: 80},
+		{ID: "2", Name: "book.mp3", Size: 250, Duration: 250, Chapters: []models.Chapter{
+			{Title: "一", Start: 0, End: 80}, {Title: "二", Start: 80, End: 170}, {Title: "三", Start: 170, End: 250},
+		}},
+		{ID: "3", Name: "book.m4b", Size: 500},
+		{ID: "4", Name: "book.flac", Size: 500},
+	}
+
+	plan := PlanUploads(files, limit)
+
+	var mp3Parts []UploadPart
+	for _, part := range plan.Parts {
+		if part.File.ID == "2" {
+			mp3Parts = append(mp3Parts, part)
+		}
+	}
+	if len(plan.Parts) != 1+len(mp3Parts) || plan.Parts[0].File.ID != "1" {
+		t.Fatalf("unexpected parts: %+v", plan.Parts)
+	}
+	if len(mp3Parts) < 3 {
+		t.Fatalf("mp3 split into %d parts, want at least 3", len(mp3Parts))
+	}
+	var covered int64
+	for i, part := range mp3Parts {
+		if part.End-part.Start > int64(limit*uploadSafetyMargin) {
+			t.Errorf("part %d is %d bytes, over the limit", i+1, part.End-part.Start)
+		}
+		if part.Start != covered {
+			t.Errorf("part %d starts at %d, want %d", i+1, part.Start, covered)
+		}
+		covered = part.End
+	}
+	if covered != 250 {
+		t.Errorf("parts cover %d bytes, want 250", covered)
+	}
+
+	if len(plan.Skipped) != 2 {
+		t.Fatalf("skipped %d files, want 2", len(plan.Skipped))
+	}
+	if !strings.Contains(plan.Skipped[0].Reason, "MP4") {
+		t.Errorf("m4b reason = %q, want MP4 explanation", plan.Skipped[0].Reason)
+	}
+	if plan.Skipped[1].Reason == "" {
+		t.Error("flac skipped without a reason")
+	}
+}
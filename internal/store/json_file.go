@@ -0,0 +1,73 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// JSONFile 以 JSON 文件的形式持久化数据
+type JSONFile struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewJSONFile 创建指定数据目录下的 JSON 文件存储
+func NewJSONFile(dataDir, name string) *JSONFile {
+	return &JSONFile{
+		path: filepath.Join(dataDir, name),
+	}
+}
+
+// Path 返回文件路径
+func (f *JSONFile) Path() string {
+	return f.path
+}
+
+// Load 从文件读取数据，文件不存在时保持 v 不变
+func (f *JSONFile) Load(v interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error reading %s: %w", f.path, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("error unmarshaling %s: %w", f.path, err)
+	}
+
+	return nil
+}
+
+// Save 将数据写入文件，先写临时文件再重命名以避免写入中断导致文件损坏
+func (f *JSONFile) Save(v interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling %s: %w", f.path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("error creating data directory: %w", err)
+	}
+
+	tmpPath := f.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("error writing %s: %w", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, f.path); err != nil {
+		return fmt.Errorf("error renaming %s: %w", tmpPath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// pinyinByRune 汉字到拼音的映射，由 pinyinSyllables 构建
+	pinyinByRune map[rune]string
+	// simplifiedByRune 繁体字到简体字的映射
+	simplifiedByRune map[rune]rune
+)
+
+func init() {
+	pinyinByRune = make(map[rune]string, 7000)
+	for syllable, chars := range pinyinSyllables {
+		for _, r := range chars {
+			pinyinByRune[r] = syllable
+		}
+	}
+
+	simplifiedByRune = make(map[rune]rune, utf8.RuneCountInString(traditionalChars))
+	simplified := []rune(simplifiedChars)
+	for i, r := range []rune(traditionalChars) {
+		simplifiedByRune[r] = simplified[i]
+	}
+}
+
+// IsHan 判断字符是否为汉字
+func IsHan(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
+
+// ToSimplified 将文本中的繁体字转换为简体字，未收录的字符保持不变
+func ToSimplified(text string) string {
+	return strings.Map(func(r rune) rune {
+		if s, ok := simplifiedByRune[r]; ok {
+			return s
+		}
+		return r
+	}, text)
+}
+
+// Pinyin 将文本中的汉字转换为不带声调的拼音，多音字只取常用读音，未收录的字符保持不变
+func Pinyin(text string) string {
+	var sb strings.Builder
+	for _, r := range ToSimplified(text) {
+		if syllable, ok := pinyinByRune[r]; ok {
+			sb.WriteString(syllable)
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// PinyinInitials 返回文本中汉字的拼音首字母，其他字母和数字保持不变
+func PinyinInitials(text string) string {
+	var sb strings.Builder
+	for _, r := range ToSimplified(text) {
+		if syllable, ok := pinyinByRune[r]; ok {
+			sb.WriteByte(syllable[0])
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return sb.String()
+}
+
+// NormalizeTitle 规范化标题用于比较：繁体转简体、全角转半角、转小写并去掉空白和标点
+func NormalizeTitle(title string) string {
+	var sb strings.Builder
+	for _, r := range ToSimplified(title) {
+		// 全角 ASCII 字符转为半角
+		if r >= 0xFF01 && r <= 0xFF5E {
+			r -= 0xFEE0
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return sb.String()
+}
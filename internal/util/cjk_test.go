@@ -0,0 +1,62 @@
+package util
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCJKData(t *testing.T) {
+	syllables := make(map[rune]string)
+	for syllable, chars := range pinyinSyllables {
+		for _, r := range syllable {
+			if r < 'a' || r > 'z' {
+				t.Errorf("syllable %q is not lowercase ASCII", syllable)
+				break
+			}
+		}
+		for _, r := range chars {
+			if other, ok := syllables[r]; ok {
+				t.Errorf("%c is listed under both %q and %q", r, other, syllable)
+			}
+			syllables[r] = syllable
+		}
+	}
+	if len(syllables) != 6763 {
+		t.Errorf("pinyin table has %d characters, want the 6763 of GB2312", len(syllables))
+	}
+
+	if n, m := utf8.RuneCountInString(traditionalChars), utf8.RuneCountInString(simplifiedChars); n != m {
+		t.Fatalf("traditionalChars has %d characters, simplifiedChars has %d", n, m)
+	}
+	simplified := []rune(simplifiedChars)
+	seen := make(map[rune]bool)
+	for i, r := range []rune(traditionalChars) {
+		if seen[r] {
+			t.Errorf("traditional %c is listed twice", r)
+		}
+		seen[r] = true
+		if simplified[i] == r {
+			t.Errorf("traditional %c maps to itself", r)
+		}
+	}
+}
+
+func TestCJKConversions(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(string) string
+		in   string
+		want string
+	}{
+		{"simplified", ToSimplified, "魔戒：雙塔奇兵", "魔戒：双塔奇兵"},
+		{"simplified keeps others", ToSimplified, "Dune 沙丘", "Dune 沙丘"},
+		{"pinyin", Pinyin, "三体", "santi"},
+		{"initials", PinyinInitials, "三体", "st"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.fn(tt.in); got != tt.want {
+			t.Errorf("%s(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
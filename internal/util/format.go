@@ -0,0 +1,193 @@
+package util
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// markdownReplacer 转义 Telegram Markdown 特殊字符
+var markdownReplacer = strings.NewReplacer(
+	"_", "\\_",
+	"*", "\\*",
+	"`", "\\`",
+	"[", "\\[",
+)
+
+// EscapeMarkdown 转义文本中的 Markdown 特殊字符
+func EscapeMarkdown(text string) string {
+	return markdownReplacer.Replace(text)
+}
+
+var (
+	// htmlBreakPattern 换行和段落结束标签
+	htmlBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>`)
+	// htmlTagPattern 其他 HTML 标签
+	htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+	// blankLinesPattern 连续的空行
+	blankLinesPattern = regexp.MustCompile(`\n{3,}`)
+	// minutesPattern 按分钟计算的纯数字时间，如 90 或 1.5
+	minutesPattern = regexp.MustCompile(`^\d+(\.\d+)?$`)
+)
+
+// StripHTML 去掉简介中的 HTML 标签并还原实体字符，段落和换行标签转换为换行
+func StripHTML(text string) string {
+	if !strings.Contains(text, "<") && !strings.Contains(text, "&") {
+		return text
+	}
+	text = htmlBreakPattern.ReplaceAllString(text, "\n")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = blankLinesPattern.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// ProgressBar 生成文本进度条，fraction 取值范围为 0 到 1
+func ProgressBar(fraction float64, width int) string {
+	if fraction < 0 {
+		fraction = 0
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	filled := int(fraction*float64(width) + 0.5)
+	return strings.Repeat("▓", filled) + strings.Repeat("░", width-filled)
+}
+
+// FormatClock 将持续时间格式化为 时:分:秒
+func FormatClock(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	seconds := int(d.Seconds()) % 60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
+
+// FormatBitrate 格式化码率
+func FormatBitrate(bps int64) string {
+	switch {
+	case bps >= 1000000:
+		return fmt.Sprintf("%.1f Mbps", float64(bps)/1000000)
+	case bps >= 1000:
+		return fmt.Sprintf("%d kbps", bps/1000)
+	default:
+		return fmt.Sprintf("%d bps", bps)
+	}
+}
+
+// FormatListeningTime 将秒数格式化为简短的收听时长
+func FormatListeningTime(seconds float64) string {
+	minutes := int(seconds / 60)
+	if minutes < 60 {
+		return fmt.Sprintf("%d分钟", minutes)
+	}
+	return fmt.Sprintf("%d小时%d分钟", minutes/60, minutes%60)
+}
+
+// ParseTimestamp 解析用户输入的播放位置，返回秒数
+// 支持 “1h23m”、“1h23m45s” 等时长写法，“83:00”（分:秒）和 “1:23:00”（时:分:秒），纯数字按分钟计算
+func ParseTimestamp(text string) (float64, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return 0, fmt.Errorf("时间为空")
+	}
+
+	if strings.Contains(text, ":") {
+		parts := strings.Split(text, ":")
+		if len(parts) > 3 {
+			return 0, fmt.Errorf("无法识别的时间: %s", text)
+		}
+		var seconds float64
+		for i, part := range parts {
+			value, err := strconv.Atoi(part)
+			if err != nil || value < 0 {
+				return 0, fmt.Errorf("无法识别的时间: %s", text)
+			}
+			// 第一段可以超过 59，如 83:00 表示 83 分钟
+			if i > 0 && value >= 60 {
+				return 0, fmt.Errorf("无法识别的时间: %s", text)
+			}
+			seconds = seconds*60 + float64(value)
+		}
+		return seconds, nil
+	}
+
+	// 只接受普通的小数写法，ParseFloat 还会接受 NaN、Inf 和 1e9 等写法
+	if strings.HasPrefix(text, "-") {
+		return 0, fmt.Errorf("时间不能为负数: %s", text)
+	}
+	if minutesPattern.MatchString(text) {
+		minutes, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsNaN(minutes) || math.IsInf(minutes, 0) {
+			return 0, fmt.Errorf("无法识别的时间: %s", text)
+		}
+		return minutes * 60, nil
+	}
+
+	duration, err := time.ParseDuration(text)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("无法识别的时间: %s", text)
+	}
+	return duration.Seconds(), nil
+}
+
+// FormatTimestamp 将秒数格式化为 “时:分:秒”，不足一小时时为 “分:秒”
+func FormatTimestamp(seconds float64) string {
+	total := int(seconds)
+	if total < 3600 {
+		return fmt.Sprintf("%d:%02d", total/60, total%60)
+	}
+	return fmt.Sprintf("%d:%02d:%02d", total/3600, total/60%60, total%60)
+}
+
+// SplitMessage 按行将文本拆分为多段，每段的长度（按 Telegram 计算方式的 UTF-16 编码单位）不超过 limit
+// 单行超出限制时按字符拆分
+func SplitMessage(text string, limit int) []string {
+	var chunks []string
+	var current strings.Builder
+	currentLength := 0
+	flush := func() {
+		if chunk := strings.TrimRight(current.String(), "\n"); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		current.Reset()
+		currentLength = 0
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		lineLength := len(utf16.Encode([]rune(line)))
+		if currentLength+lineLength > limit {
+			flush()
+		}
+		for lineLength > limit {
+			// 单行超出限制，按字符截取
+			runes := []rune(line)
+			n, length := 0, 0
+			for n < len(runes) {
+				size := len(utf16.Encode(runes[n : n+1]))
+				if length+size > limit {
+					break
+				}
+				length += size
+				n++
+			}
+			chunks = append(chunks, string(runes[:n]))
+			line = string(runes[n:])
+			lineLength -= length
+		}
+		current.WriteString(line)
+		currentLength += lineLength
+	}
+	flush()
+	return chunks
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+	}{
+		{"90", 5400},
+		{"1.5", 90},
+		{" 2 ", 120},
+		{"83:00", 4980},
+		{"1:23:00", 4980},
+		{"0:05", 5},
+		{"1h23m", 4980},
+		{"1h23m45s", 5025},
+		{"45s", 45},
+		{"1H", 3600},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimestamp(tt.input)
+		if err != nil {
+			t.Errorf("ParseTimestamp(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTimestamp(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseTimestampInvalid(t *testing.T) {
+	for _, input := range []string{
+		"", "NaN", "nan", "Inf", "+Inf", "-inf", "infinity", "1e9", "1E3", "0x10",
+		"-5", "-1:00", "-5m", "1:60", "1:2:3:4", "a:b", "1.", ".5", "abc",
+	} {
+		if got, err := ParseTimestamp(input); err == nil {
+			t.Errorf("ParseTimestamp(%q) = %v, want error", input, got)
+		}
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	if got := SplitMessage("short\ntext\n", 100); len(got) != 1 || got[0] != "short\ntext" {
+		t.Errorf("SplitMessage(short) = %q", got)
+	}
+
+	var lines []string
+	for i := 0; i < 300; i++ {
+		lines = append(lines, fmt.Sprintf("%d. 三体：地球往事 — 刘慈欣 · 20小时30分钟", i+1))
+	}
+	text := strings.Join(lines, "\n")
+	chunks := SplitMessage(text, 4096)
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks, want at least 2", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if n := len(utf16.Encode([]rune(chunk))); n > 4096 {
+			t.Errorf("chunk %d has length %d", i, n)
+		}
+	}
+	if strings.Join(chunks, "\n") != text {
+		t.Error("chunks do not rejoin to the original lines")
+	}
+
+	// 单行超出限制时按字符拆分，emoji 占两个编码单位
+	long := strings.Repeat("🎧", 5)
+	chunks = SplitMessage(long, 4)
+	if len(chunks) != 3 || strings.Join(chunks, "") != long {
+		t.Errorf("SplitMessage(long) = %q", chunks)
+	}
+}